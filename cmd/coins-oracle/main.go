@@ -2,25 +2,37 @@ package main
 
 import (
 	"context"
+	"log"
+	"os"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/awslabs/aws-lambda-go-api-proxy/echo"
 	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
 
 	"github.com/hugorut/coins-oracle/internal/handlers"
+	"github.com/hugorut/coins-oracle/internal/reorg"
+	"github.com/hugorut/coins-oracle/internal/stream"
 	"github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/internal/watch"
 )
 
 var (
 	echoAdapter *echoadapter.EchoLambda
 )
 
-func init() {
+// newRouter returns the api's routes. The block streams hold their connection open, so they are only
+// served when streaming is true, as the lambda adapter buffers the whole response before returning it.
+func newRouter(streaming bool) *echo.Echo {
 	r := echo.New()
+	r.Use(middleware.Recover())
+
 	resolver := transport.NewResolver(r.Logger)
+	reorgs := reorg.NewRegistry(resolver)
 
 	r.GET("/ping", handlers.Ping)
+	r.GET("/portfolio/:addr", handlers.GetPortfolio, handlers.SetRouterMiddlewareFunc(resolver))
 
 	// set all urls under the nodes prefix to use the coin client middleware function
 	// which sets a coin client for the given :assetId if one is provided.
@@ -28,20 +40,69 @@ func init() {
 		"/nodes",
 		handlers.SetRouterMiddlewareFunc(resolver),
 		handlers.SetCoinClientMiddlewareFunc(resolver),
+		handlers.SetReorgRegistryMiddlewareFunc(reorgs),
 	)
 
 	// node routes
 	ng.GET("", handlers.GetNodes)
 	ng.GET("/:assetId/info", handlers.GetInfo)
 
+	// block routes
+	if streaming {
+		hub := stream.NewHub(resolver)
+		ng.GET("/:assetId/blocks/stream", handlers.StreamBlocks, handlers.SetBlockHubMiddlewareFunc(hub))
+		ng.GET("/:assetId/blocks/ws", handlers.StreamBlocksWS, handlers.SetBlockHubMiddlewareFunc(hub))
+	}
+	ng.GET("/:assetId/blocks/:heightOrHash", handlers.GetBlock)
+	ng.GET("/:assetId/reorgs", handlers.GetReorgs)
+	ng.GET("/:assetId/mempool", handlers.GetMempool)
+
+	// batch routes
+	ng.POST("/batch/balances", handlers.GetBatchBalances)
+	ng.POST("/batch/txs", handlers.GetBatchTransactions)
+
 	// address routes
 	ng.GET("/:assetId/addrs/:addr/balance", handlers.GetWalletBalance)
+	ng.GET("/:assetId/addrs/:addr/utxos", handlers.GetUTXOs)
+	ng.GET("/:assetId/tokens/:contract/addrs/:addr/balance", handlers.GetTokenBalance)
+	ng.GET("/:assetId/addrs/:addr/nfts/:contract", handlers.ListNFTs)
+	ng.GET("/:assetId/addrs/:addr/resources", handlers.GetResources)
 	ng.POST("/:assetId/addrs/import", handlers.ImportAddress)
+	ng.GET("/:assetId/imports/:id", handlers.GetImport)
+
+	// xpub routes
+	ng.GET("/:assetId/xpubs/:xpub/balance", handlers.GetXPubBalance)
+	ng.GET("/:assetId/xpubs/:xpub/addresses", handlers.ListXPubAddresses)
+	ng.GET("/:assetId/xpubs/:xpub/txs", handlers.ListXPubTransactions)
+
+	// nft routes
+	ng.GET("/:assetId/nfts/:contract/tokens/:id/owner", handlers.GetNFTOwner)
 
 	// transaction routes
 	ng.GET("/:assetId/txs/:txHash", handlers.GetTransactionByHash)
+	ng.GET("/:assetId/txs/:txHash/wait", handlers.WaitForTransaction)
 
-	echoAdapter = echoadapter.New(r)
+	// watch routes are only served with a store set, as watches are polled by coins-watcher which has to share it.
+	if path := os.Getenv("WATCH_STORE_PATH"); path != "" {
+		store, err := watch.NewFileStore(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		wg := r.Group(
+			"/watches",
+			handlers.SetRouterMiddlewareFunc(resolver),
+			handlers.SetWatchStoreMiddlewareFunc(store),
+		)
+
+		wg.POST("", handlers.CreateWatch)
+		wg.GET("", handlers.GetWatches)
+		wg.GET("/deadletters", handlers.GetDeadLetters)
+		wg.GET("/:id", handlers.GetWatch)
+		wg.DELETE("/:id", handlers.DeleteWatch)
+	}
+
+	return r
 }
 
 // Handler wraps the echo adapter in a common function that the lambda start accepts
@@ -49,6 +110,12 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	return echoAdapter.ProxyWithContext(ctx, req)
 }
 
+// main serves the api over http on HTTP_ADDR when it's set, block streams included, otherwise as a lambda function.
 func main() {
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		log.Fatal(newRouter(true).Start(addr))
+	}
+
+	echoAdapter = echoadapter.New(newRouter(false))
 	lambda.Start(Handler)
 }
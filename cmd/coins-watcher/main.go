@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/internal/watch"
+)
+
+const (
+	defaultInterval = time.Minute
+)
+
+// coins-watcher polls every registered watch and sends webhook callbacks for new activity.
+// It shares the watch store file, set with WATCH_STORE_PATH, with the coins-oracle api.
+func main() {
+	logger := log.New(os.Stderr, "[WATCHER] ", log.LstdFlags)
+
+	path := os.Getenv("WATCH_STORE_PATH")
+	if path == "" {
+		logger.Fatal("WATCH_STORE_PATH must be set to the watch store shared with the coins-oracle api")
+	}
+
+	interval := defaultInterval
+	if v := os.Getenv("WATCH_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.Fatalf("invalid WATCH_POLL_INTERVAL: %s, err: %s", v, err)
+		}
+
+		interval = d
+	}
+
+	store, err := watch.NewFileStore(path)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	poller := watch.Poller{
+		Store:    store,
+		Clients:  transport.NewResolver(echo.New().Logger),
+		Sender:   watch.NewNotifier(),
+		Interval: interval,
+		Logger:   logger,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	logger.Printf("polling watches in %s every %s", path, interval)
+	if err := poller.Run(ctx); err != nil && err != context.Canceled {
+		logger.Fatal(err)
+	}
+}
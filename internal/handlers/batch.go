@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/reorg"
+	"github.com/hugorut/coins-oracle/internal/transport"
+)
+
+var (
+	// MaxBatchSize is the maximum number of lookups accepted in a single batch request.
+	MaxBatchSize = 500
+)
+
+// BatchBalancesResponse struct to map the batch balance results to the required json format.
+type BatchBalancesResponse struct {
+	Data struct {
+		Balances []transport.BalanceResult `json:"balances"`
+	} `json:"data"`
+}
+
+// BatchTransactionsResponse struct to map the batch transaction results to the required json format.
+type BatchTransactionsResponse struct {
+	Data struct {
+		Transactions []transport.TransactionResult `json:"transactions"`
+	} `json:"data"`
+}
+
+// GetBatchBalances fetches the balances for a list of asset addresses in a single request.
+// Errors are reported against each lookup so that one failing asset doesn't fail the whole batch.
+func GetBatchBalances(c echo.Context) error {
+	c.Logger().Print("executing GetBatchBalances handler")
+
+	var req []transport.BalanceLookup
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "request body must be a list of assetId and addr objects",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if len(req) > MaxBatchSize {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("batch size cannot exceed %d lookups", MaxBatchSize),
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	router := c.Get("coin_router").(transport.Resolver)
+
+	var res BatchBalancesResponse
+	res.Data.Balances = router.GetBalances(req)
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetBatchTransactions fetches a list of asset transactions in a single request.
+// Errors are reported against each lookup so that one failing asset doesn't fail the whole batch.
+func GetBatchTransactions(c echo.Context) error {
+	c.Logger().Print("executing GetBatchTransactions handler")
+
+	var req []transport.TransactionLookup
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "request body must be a list of assetId and hash objects",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if len(req) > MaxBatchSize {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("batch size cannot exceed %d lookups", MaxBatchSize),
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	router := c.Get("coin_router").(transport.Resolver)
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), ReorgSyncTimeout)
+	defer cancel()
+
+	trackers := make(map[string]func() *reorg.Tracker)
+	for _, l := range req {
+		if _, ok := trackers[l.AssetID]; !ok {
+			trackers[l.AssetID] = syncReorgTracker(ctx, c, l.AssetID)
+		}
+	}
+
+	var res BatchTransactionsResponse
+	res.Data.Transactions = router.GetTransactions(req)
+
+	for _, r := range res.Data.Transactions {
+		tracker, ok := trackers[r.AssetID]
+		if !ok || r.Transaction == nil {
+			continue
+		}
+
+		markReorged(tracker(), r.Hash, r.Transaction)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
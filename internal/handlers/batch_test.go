@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	"github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	transport2 "github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Batch", func() {
+	var (
+		e      *echo.Echo
+		ctrl   *gomock.Controller
+		router *mock_transport.MockRouter
+		logger *mock_echo.MockLogger
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		logger = mock_echo.NewMockLogger(ctrl)
+		router = mock_transport.NewMockRouter(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetBatchBalances", func() {
+		It("Should return a result for every lookup", func() {
+			req := httptest.NewRequest(http.MethodPost, "/nodes/batch/balances", strings.NewReader(`[
+				{"assetId": "btc", "addr": "addr1"},
+				{"assetId": "missing", "addr": "addr2"}
+			]`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.Set("coin_router", router)
+
+			router.EXPECT().GetBalances(gomock.Eq([]transport.BalanceLookup{
+				{AssetID: "btc", Addr: "addr1"},
+				{AssetID: "missing", Addr: "addr2"},
+			})).Return([]transport.BalanceResult{
+				{
+					AssetID: "btc",
+					Addr:    "addr1",
+					Balance: &transport2.BalanceData{
+						Assets: []transport2.Asset{
+							{Asset: "BTC", Balance: "0.100000"},
+						},
+					},
+				},
+				{
+					AssetID: "missing",
+					Addr:    "addr2",
+					Error:   "could not find client named: missing, have you registered the client",
+				},
+			})
+
+			Expect(GetBatchBalances(c)).To(Succeed())
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"balances": [
+						{
+							"assetId": "btc",
+							"addr": "addr1",
+							"balance": {
+								"assets": [{"asset": "BTC", "balance": "0.100000"}]
+							}
+						},
+						{
+							"assetId": "missing",
+							"addr": "addr2",
+							"error": "could not find client named: missing, have you registered the client"
+						}
+					]
+				}
+			}`))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should reject a malformed body", func() {
+			req := httptest.NewRequest(http.MethodPost, "/nodes/batch/balances", strings.NewReader(`{"assetId": "btc"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.Set("coin_router", router)
+
+			Expect(GetBatchBalances(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "request body must be a list of assetId and addr objects",
+				"code": 101
+			}`))
+		})
+	})
+
+	Describe("GetBatchTransactions", func() {
+		It("Should return a result for every lookup", func() {
+			req := httptest.NewRequest(http.MethodPost, "/nodes/batch/txs", strings.NewReader(`[
+				{"assetId": "neo", "hash": "hash1"}
+			]`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.Set("coin_router", router)
+
+			router.EXPECT().GetTransactions(gomock.Eq([]transport.TransactionLookup{
+				{AssetID: "neo", Hash: "hash1"},
+			})).Return([]transport.TransactionResult{
+				{
+					AssetID: "neo",
+					Hash:    "hash1",
+					Transaction: &transport2.Transaction{
+						ID:    "hash1",
+						From:  "from",
+						To:    "to",
+						Value: "1",
+						Confirmations: transport2.Confirmations{
+							Confirmed: true,
+						},
+					},
+				},
+			})
+
+			Expect(GetBatchTransactions(c)).To(Succeed())
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"transactions": [
+						{
+							"assetId": "neo",
+							"hash": "hash1",
+							"transaction": {
+								"id": "hash1",
+								"from": "from",
+								"to": "to",
+								"value": "1",
+								"confirmations": {
+									"confirmed": true
+								}
+							}
+						}
+					]
+				}
+			}`))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+
+	transport2 "github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// GetBlock fetches a block by its height or hash. Passing txs=full in the query expands the
+// block's transaction ids into transactions.
+func GetBlock(c echo.Context) error {
+	c.Logger().Print("executing GetBlock handler")
+
+	heightOrHash := c.Param("heightOrHash")
+
+	fetcher, ok := c.Get("coin_client").(transport.BlockFetcher)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have block lookup functionality", c.Param("assetId")),
+			Code:  ErrorCodeGetBlockError,
+		})
+	}
+
+	res, err := getBlock(fetcher, heightOrHash)
+	if err != nil {
+		c.Logger().Errorf("error getting block: %s for coin: %s, err: %v", heightOrHash, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not return block details for the given height/hash",
+			Code:  ErrorCodeGetBlockError,
+		})
+	}
+
+	if c.QueryParam("txs") == "full" {
+		client := c.Get("coin_client").(transport.CoinClient)
+
+		errs := transport2.ExpandBlockTransactions(client, &res.Data.Block)
+		for id, err := range errs {
+			c.Logger().Errorf("error expanding transaction: %s of block: %s for coin: %s, err: %v", id, heightOrHash, c.Param("assetId"), err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// getBlock looks up numeric values by height first. As some chains, e.g. lisk, use numeric block ids
+// the value is looked up as a hash if there's no block at that height.
+func getBlock(fetcher transport.BlockFetcher, heightOrHash string) (*transport.BlockResp, error) {
+	height, err := strconv.ParseInt(heightOrHash, 10, 64)
+	if err != nil || height < 0 {
+		return fetcher.GetBlockByHash(heightOrHash)
+	}
+
+	res, err := fetcher.GetBlockByHeight(height)
+	if err == nil {
+		return res, nil
+	}
+
+	if byHash, hashErr := fetcher.GetBlockByHash(heightOrHash); hashErr == nil {
+		return byHash, nil
+	}
+
+	return nil, err
+}
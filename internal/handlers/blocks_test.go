@@ -0,0 +1,201 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Blocks", func() {
+	var (
+		e       *echo.Echo
+		ctrl    *gomock.Controller
+		client  *mock_transport.MockCoinClient
+		fetcher *mock_transport.MockBlockFetcher
+		logger  *mock_echo.MockLogger
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		fetcher = mock_transport.NewMockBlockFetcher(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newContext := func(assetID, heightOrHash, query string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/nodes/%s/blocks/%s%s", assetID, heightOrHash, query), nil)
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.SetParamNames("assetId", "heightOrHash")
+		c.SetParamValues(assetID, heightOrHash)
+
+		c.Set("coin_client", struct {
+			*mock_transport.MockCoinClient
+			*mock_transport.MockBlockFetcher
+		}{client, fetcher})
+
+		return c, rec
+	}
+
+	block := func() *transport.BlockResp {
+		res := &transport.BlockResp{}
+		res.Data.Block = transport.Block{
+			Height:    100,
+			Hash:      "hash100",
+			Parent:    "hash99",
+			Timestamp: 1568729415,
+			TxCount:   2,
+			TxIDs:     []string{"tx1", "tx2"},
+		}
+
+		return res
+	}
+
+	Describe("GetBlock", func() {
+		It("Should look up a numeric value by height", func() {
+			c, rec := newContext("test", "100", "")
+
+			fetcher.EXPECT().GetBlockByHeight(int64(100)).Return(block(), nil)
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"block": {
+						"height": 100,
+						"hash": "hash100",
+						"parent": "hash99",
+						"timestamp": 1568729415,
+						"tx_count": 2,
+						"tx_ids": ["tx1", "tx2"]
+					}
+				}
+			}`))
+		})
+
+		It("Should look up any other value by hash", func() {
+			c, rec := newContext("test", "hash100", "")
+
+			fetcher.EXPECT().GetBlockByHash("hash100").Return(block(), nil)
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should fall back to the hash for numeric ids with no block at that height", func() {
+			c, rec := newContext("test", "9181329057331339714", "")
+
+			gomock.InOrder(
+				fetcher.EXPECT().GetBlockByHeight(int64(9181329057331339714)).Return(nil, errors.New("not found")),
+				fetcher.EXPECT().GetBlockByHash("9181329057331339714").Return(block(), nil),
+			)
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should expand the transactions when txs=full, leaving out those which can't be fetched", func() {
+			c, rec := newContext("test", "100", "?txs=full")
+
+			tx := &transport.TransactionResp{}
+			tx.Data.Transaction = transport.Transaction{
+				ID:    "tx2",
+				From:  "addr1",
+				To:    "addr2",
+				Value: "1.000000",
+			}
+
+			fetcher.EXPECT().GetBlockByHeight(int64(100)).Return(block(), nil)
+			client.EXPECT().GetTransactionByHash("tx1").Return(nil, errors.New("coinbase"))
+			client.EXPECT().GetTransactionByHash("tx2").Return(tx, nil)
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "tx1", "100", "test", gomock.Any())
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"block": {
+						"height": 100,
+						"hash": "hash100",
+						"parent": "hash99",
+						"timestamp": 1568729415,
+						"tx_count": 2,
+						"tx_ids": ["tx1", "tx2"],
+						"transactions": [
+							{
+								"id": "tx2",
+								"from": "addr1",
+								"to": "addr2",
+								"value": "1.000000",
+								"confirmations": {"confirmed": false}
+							}
+						]
+					}
+				}
+			}`))
+		})
+
+		It("Should return an error if the block can't be found", func() {
+			c, rec := newContext("test", "hash100", "")
+
+			fetcher.EXPECT().GetBlockByHash("hash100").Return(nil, errors.New("not found"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "hash100", "test", gomock.Any())
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "could not return block details for the given height/hash",
+				"code": 402
+			}`))
+		})
+
+		It("Should return an error if the client can't look up blocks", func() {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test/blocks/100", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "heightOrHash")
+			c.SetParamValues("test", "100")
+			c.Set("coin_client", client)
+
+			err := GetBlock(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: test does not have block lookup functionality",
+				"code": 402
+			}`))
+		})
+	})
+})
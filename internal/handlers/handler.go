@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/labstack/echo"
 
@@ -13,12 +14,25 @@ import (
 const (
 	ErrorInvalidRequest = 101
 
-	ErrorCodeCannotImport = 201
-	ErrorCodeBalanceError = 202
+	ErrorCodeCannotImport   = 201
+	ErrorCodeBalanceError   = 202
+	ErrorCodePortfolioError = 203
+	ErrorCodeUTXOError      = 204
+	ErrorCodeTokenError     = 205
+	ErrorCodeNFTError       = 206
+	ErrorCodeXPubError      = 207
+	ErrorCodeResourceError  = 208
 
 	ErrorCodeGetTransactionError = 301
 
-	ErrorCodeGetInfoError = 401
+	ErrorCodeGetInfoError  = 401
+	ErrorCodeGetBlockError = 402
+	ErrorCodeReorgError    = 403
+	ErrorCodeMempoolError  = 404
+	ErrorCodeStreamError   = 405
+
+	ErrorCodeWatchError    = 501
+	ErrorCodeWatchNotFound = 502
 )
 
 var (
@@ -55,6 +69,10 @@ func SetCoinClientMiddlewareFunc(router transport.Resolver) echo.MiddlewareFunc
 				return next(c)
 			}
 
+			if network := c.QueryParam("network"); network != "" {
+				assetID = transport.NetworkAssetID(strings.ToUpper(assetID), network)
+			}
+
 			client, err := router.Get(assetID)
 			if err != nil {
 				return c.JSON(http.StatusNotFound, map[string]string{
@@ -88,6 +88,53 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("With a network query param", func() {
+			It("Should set the CoinClient of the asset on that network", func() {
+				r.Register("BTC", mock_transport.NewMockCoinClient(ctrl))
+				r.Register("BTC-TESTNET", client)
+
+				req := httptest.NewRequest(http.MethodGet, "/nodes/btc?network=testnet", nil)
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+
+				c := e.NewContext(req, rec)
+				c.SetParamNames("assetId")
+				c.SetParamValues("btc")
+
+				f := SetCoinClientMiddlewareFunc(r)
+				err := f(func(c echo.Context) error {
+					v := c.Get("coin_client")
+
+					Expect(v).To(BeIdenticalTo(client))
+					return nil
+				})(c)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("Should not find an asset which has no client on the network", func() {
+				r.Register("BTC", client)
+
+				req := httptest.NewRequest(http.MethodGet, "/nodes/btc?network=regtest", nil)
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+
+				c := e.NewContext(req, rec)
+				c.SetParamNames("assetId")
+				c.SetParamValues("btc")
+
+				f := SetCoinClientMiddlewareFunc(r)
+				err := f(func(c echo.Context) error {
+					return nil
+				})(c)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(rec.Body.String()).Should(MatchJSON(`{
+					"error": "asset: BTC-REGTEST was not found"
+				}`))
+				Expect(rec.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
 		Context("With invalid assetId", func() {
 			It("Should terminate middleware chain with error", func() {
 				assetID := "test-coin"
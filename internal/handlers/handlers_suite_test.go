@@ -1,8 +1,12 @@
 package handlers_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/labstack/echo"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -13,3 +17,26 @@ func TestBooks(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Handlers Suite")
 }
+
+// newCoinContext returns a context for a GET of path with the route params set and the coin client applied,
+// as SetCoinClientMiddlewareFunc would.
+func newCoinContext(e *echo.Echo, coinClient interface{}, path string, names, values []string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	c.Set("coin_client", coinClient)
+
+	return c, rec
+}
+
+// expectBadRequest asserts the handler responded with a bad request carrying the msg and code.
+func expectBadRequest(rec *httptest.ResponseRecorder, msg string, code int) {
+	raw, err := json.Marshal(map[string]interface{}{"data": nil, "error": msg, "code": code})
+	Expect(err).ToNot(HaveOccurred())
+
+	ExpectWithOffset(1, rec.Code).To(Equal(http.StatusBadRequest))
+	ExpectWithOffset(1, rec.Body.String()).To(MatchJSON(raw))
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// GetMempool returns a summary of the transactions waiting to be included in a block by the coin's node.
+func GetMempool(c echo.Context) error {
+	c.Logger().Print("executing GetMempool handler")
+
+	inspector, ok := c.Get("coin_client").(transport.MempoolInspector)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have mempool functionality", c.Param("assetId")),
+			Code:  ErrorCodeMempoolError,
+		})
+	}
+
+	res, err := inspector.GetMempool()
+	if err != nil {
+		c.Logger().Errorf("error getting mempool for coin: %s, err: %v", c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not return mempool details",
+			Code:  ErrorCodeMempoolError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Mempool", func() {
+	var (
+		e         *echo.Echo
+		ctrl      *gomock.Controller
+		client    *mock_transport.MockCoinClient
+		inspector *mock_transport.MockMempoolInspector
+		logger    *mock_echo.MockLogger
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		inspector = mock_transport.NewMockMempoolInspector(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newContext := func(coinClient interface{}) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/nodes/test/mempool", nil)
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.SetParamNames("assetId")
+		c.SetParamValues("test")
+		c.Set("coin_client", coinClient)
+
+		return c, rec
+	}
+
+	Describe("GetMempool", func() {
+		It("Should return the mempool summary of the node", func() {
+			c, rec := newContext(struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockMempoolInspector
+			}{client, inspector})
+
+			res := &transport.MempoolResp{}
+			res.Data.Mempool = transport.Mempool{
+				Size:  3,
+				Bytes: 750,
+				FeeHistogram: []transport.FeeBucket{
+					{FeeRate: 1, Count: 2, Bytes: 500},
+					{FeeRate: 10, Count: 1, Bytes: 250},
+				},
+			}
+
+			inspector.EXPECT().GetMempool().Return(res, nil)
+
+			err := GetMempool(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"mempool": {
+						"size": 3,
+						"bytes": 750,
+						"fee_histogram": [
+							{"fee_rate": 1, "count": 2, "bytes": 500},
+							{"fee_rate": 10, "count": 1, "bytes": 250}
+						]
+					}
+				}
+			}`))
+		})
+
+		It("Should return a bad request when the node fails", func() {
+			c, rec := newContext(struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockMempoolInspector
+			}{client, inspector})
+
+			inspector.EXPECT().GetMempool().Return(nil, errors.New("node down"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "test", gomock.Any())
+
+			err := GetMempool(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "could not return mempool details",
+				"code": 404
+			}`))
+		})
+
+		It("Should return a bad request when the client can't inspect its mempool", func() {
+			c, rec := newContext(client)
+
+			err := GetMempool(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: test does not have mempool functionality",
+				"code": 404
+			}`))
+		})
+	})
+})
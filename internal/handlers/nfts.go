@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	tokenIDReg = regexp.MustCompile(`^(0[xX][0-9a-fA-F]+|[0-9]+)$`)
+)
+
+// GetNFTOwner fetches the current owner of the non fungible token with the id given in the url.
+func GetNFTOwner(c echo.Context) error {
+	c.Logger().Print("executing GetNFTOwner handler")
+
+	contract, id := c.Param("contract"), c.Param("id")
+	if !contractReg.MatchString(contract) {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "contract must be a hex encoded address",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if !tokenIDReg.MatchString(id) {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "token id must be a decimal or 0x prefixed hex integer",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	inspector, ok := c.Get("coin_client").(transport.NFTInspector)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have nft functionality", c.Param("assetId")),
+			Code:  ErrorCodeNFTError,
+		})
+	}
+
+	res, err := inspector.GetNFTOwner(contract, id)
+	if err != nil {
+		c.Logger().Errorf("error getting owner of token: %s in contract: %s for coin: %s, err: %v", id, contract, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get owner of given token",
+			Code:  ErrorCodeNFTError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListNFTs fetches the non fungible tokens of the contract given in the url held by the address, the ids of the
+// tokens being listed a page at a time from the offset query param.
+func ListNFTs(c echo.Context) error {
+	c.Logger().Print("executing ListNFTs handler")
+
+	contract, addr := c.Param("contract"), c.Param("addr")
+	if !contractReg.MatchString(contract) {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "contract must be a hex encoded address",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if !contractReg.MatchString(addr) {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "addr must be a hex encoded address",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	var offset int64
+	if param := c.QueryParam("offset"); param != "" {
+		var err error
+		if offset, err = strconv.ParseInt(param, 10, 64); err != nil || offset < 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "offset must be a non negative integer",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+	}
+
+	inspector, ok := c.Get("coin_client").(transport.NFTInspector)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have nft functionality", c.Param("assetId")),
+			Code:  ErrorCodeNFTError,
+		})
+	}
+
+	res, err := inspector.ListNFTs(contract, addr, offset)
+	if err != nil {
+		c.Logger().Errorf("error listing tokens of address: %s in contract: %s for coin: %s, err: %v", addr, contract, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not list nfts of given address",
+			Code:  ErrorCodeNFTError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,259 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("NFTs", func() {
+	var (
+		e         *echo.Echo
+		ctrl      *gomock.Controller
+		client    *mock_transport.MockCoinClient
+		inspector *mock_transport.MockNFTInspector
+		logger    *mock_echo.MockLogger
+	)
+
+	contract := "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		inspector = mock_transport.NewMockNFTInspector(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	nftClient := func() interface{} {
+		return struct {
+			*mock_transport.MockCoinClient
+			*mock_transport.MockNFTInspector
+		}{client, inspector}
+	}
+
+	Describe("GetNFTOwner", func() {
+		ownerContext := func(coinClient interface{}, contract, id string) (echo.Context, *httptest.ResponseRecorder) {
+			return newCoinContext(
+				e,
+				coinClient,
+				"/nodes/eth/nfts/"+contract+"/tokens/"+id+"/owner",
+				[]string{"assetId", "contract", "id"},
+				[]string{"eth", contract, id},
+			)
+		}
+
+		It("Should return the owner of the token", func() {
+			c, rec := ownerContext(nftClient(), contract, "0x1f")
+
+			res := &transport.NFTOwnerResp{}
+			res.Data.NFT = transport.NFTOwner{
+				Contract: contract,
+				TokenID:  "31",
+				Owner:    "0x1111111111111111111111111111111111111111",
+			}
+
+			inspector.EXPECT().GetNFTOwner(contract, "0x1f").Return(res, nil)
+
+			err := GetNFTOwner(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"nft": {
+						"contract": "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+						"tokenId": "31",
+						"owner": "0x1111111111111111111111111111111111111111"
+					}
+				}
+			}`))
+		})
+
+		It("Should reject a token id which isn't an integer", func() {
+			c, rec := ownerContext(nftClient(), contract, "ape")
+
+			err := GetNFTOwner(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "token id must be a decimal or 0x prefixed hex integer", ErrorInvalidRequest)
+		})
+
+		It("Should reject a contract which isn't an address", func() {
+			c, rec := ownerContext(nftClient(), "apes", "1")
+
+			err := GetNFTOwner(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "contract must be a hex encoded address", ErrorInvalidRequest)
+		})
+
+		It("Should return a bad request when the client fails", func() {
+			c, rec := ownerContext(nftClient(), contract, "1")
+
+			inspector.EXPECT().GetNFTOwner(contract, "1").Return(nil, errors.New("execution reverted"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "1", contract, "eth", gomock.Any())
+
+			err := GetNFTOwner(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "could not get owner of given token", ErrorCodeNFTError)
+		})
+
+		It("Should return a bad request when the client can't look up nfts", func() {
+			c, rec := ownerContext(client, contract, "1")
+
+			err := GetNFTOwner(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "client: eth does not have nft functionality", ErrorCodeNFTError)
+		})
+	})
+
+	Describe("ListNFTs", func() {
+		owner := "0x1111111111111111111111111111111111111111"
+
+		listContext := func(coinClient interface{}, addr, query string) (echo.Context, *httptest.ResponseRecorder) {
+			return newCoinContext(
+				e,
+				coinClient,
+				"/nodes/eth/addrs/"+addr+"/nfts/"+contract+query,
+				[]string{"assetId", "addr", "contract"},
+				[]string{"eth", addr, contract},
+			)
+		}
+
+		It("Should return the tokens held by the address", func() {
+			c, rec := listContext(nftClient(), owner, "")
+
+			res := &transport.NFTHoldingsResp{}
+			res.Data.NFTs = transport.NFTHoldings{
+				Contract:   contract,
+				Owner:      owner,
+				Balance:    "2",
+				Enumerable: true,
+				TokenIDs:   []string{"7", "31"},
+			}
+
+			inspector.EXPECT().ListNFTs(contract, owner, int64(0)).Return(res, nil)
+
+			err := ListNFTs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"nfts": {
+						"contract": "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+						"owner": "0x1111111111111111111111111111111111111111",
+						"balance": "2",
+						"enumerable": true,
+						"tokenIds": ["7", "31"]
+					}
+				}
+			}`))
+		})
+
+		It("Should omit the token ids of contracts which can't be enumerated", func() {
+			c, rec := listContext(nftClient(), owner, "")
+
+			res := &transport.NFTHoldingsResp{}
+			res.Data.NFTs = transport.NFTHoldings{
+				Contract: contract,
+				Owner:    owner,
+				Balance:  "2",
+			}
+
+			inspector.EXPECT().ListNFTs(contract, owner, int64(0)).Return(res, nil)
+
+			err := ListNFTs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"nfts": {
+						"contract": "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+						"owner": "0x1111111111111111111111111111111111111111",
+						"balance": "2",
+						"enumerable": false
+					}
+				}
+			}`))
+		})
+
+		It("Should list the token ids from the offset", func() {
+			c, rec := listContext(nftClient(), owner, "?offset=20")
+
+			res := &transport.NFTHoldingsResp{}
+			res.Data.NFTs = transport.NFTHoldings{
+				Contract:   contract,
+				Owner:      owner,
+				Balance:    "22",
+				Enumerable: true,
+				TokenIDs:   []string{"120", "121"},
+			}
+
+			inspector.EXPECT().ListNFTs(contract, owner, int64(20)).Return(res, nil)
+
+			err := ListNFTs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		DescribeTable("Should reject invalid requests without calling the client",
+			func(addr, query, msg string) {
+				c, rec := listContext(nftClient(), addr, query)
+
+				err := ListNFTs(c)
+				Expect(err).ToNot(HaveOccurred())
+
+				expectBadRequest(rec, msg, ErrorInvalidRequest)
+			},
+			Entry("non hex address", "address", "", "addr must be a hex encoded address"),
+			Entry("short address", "0x1111", "", "addr must be a hex encoded address"),
+			Entry("negative offset", owner, "?offset=-1", "offset must be a non negative integer"),
+			Entry("non integer offset", owner, "?offset=next", "offset must be a non negative integer"),
+		)
+
+		It("Should return a bad request when the client fails", func() {
+			c, rec := listContext(nftClient(), owner, "")
+
+			inspector.EXPECT().ListNFTs(contract, owner, int64(0)).Return(nil, errors.New("execution reverted"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), owner, contract, "eth", gomock.Any())
+
+			err := ListNFTs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "could not list nfts of given address", ErrorCodeNFTError)
+		})
+
+		It("Should return a bad request when the client can't look up nfts", func() {
+			c, rec := listContext(client, owner, "")
+
+			err := ListNFTs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "client: eth does not have nft functionality", ErrorCodeNFTError)
+		})
+	})
+})
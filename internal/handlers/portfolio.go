@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/transport"
+)
+
+// GetPortfolio fetches the balance of every asset held by an address on the chain given in the chain query param.
+func GetPortfolio(c echo.Context) error {
+	c.Logger().Print("executing GetPortfolio handler")
+
+	addr := c.Param("addr")
+	chain := c.QueryParam("chain")
+	if chain == "" {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "missing chain query parameter",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	router := c.Get("coin_router").(transport.Resolver)
+
+	data, err := router.GetPortfolio(chain, addr)
+	if err != nil {
+		c.Logger().Errorf("error getting portfolio for address: %s on chain: %s, err: %v", addr, chain, err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get portfolio of given address",
+			Code:  ErrorCodePortfolioError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, transport.Portfolio{Data: *data})
+}
@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	"github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+)
+
+var _ = Describe("Portfolio", func() {
+	var (
+		e      *echo.Echo
+		ctrl   *gomock.Controller
+		router *mock_transport.MockRouter
+		logger *mock_echo.MockLogger
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		logger = mock_echo.NewMockLogger(ctrl)
+		router = mock_transport.NewMockRouter(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetPortfolio", func() {
+		It("Should return the portfolio of the address", func() {
+			req := httptest.NewRequest(http.MethodGet, "/portfolio/0xabc?chain=ethereum", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("addr")
+			c.SetParamValues("0xabc")
+			c.Set("coin_router", router)
+
+			decimals := 18
+			router.EXPECT().GetPortfolio(gomock.Eq("ethereum"), gomock.Eq("0xabc")).Return(&transport.PortfolioData{
+				Owner: "0xabc",
+				Chain: "ethereum",
+				Assets: []transport.PortfolioAsset{
+					{Asset: "ETH", Balance: "1.5", RawBalance: "1500000000000000000", Decimals: &decimals},
+				},
+				Errors: []transport.PortfolioError{
+					{AssetID: "ZRX", Error: "node down"},
+				},
+			}, nil)
+
+			Expect(GetPortfolio(c)).To(Succeed())
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"owner": "0xabc",
+					"chain": "ethereum",
+					"assets": [
+						{"asset": "ETH", "balance": "1.5", "rawBalance": "1500000000000000000", "decimals": 18}
+					],
+					"errors": [
+						{"assetId": "ZRX", "error": "node down"}
+					]
+				}
+			}`))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should require the chain query parameter", func() {
+			req := httptest.NewRequest(http.MethodGet, "/portfolio/0xabc", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("addr")
+			c.SetParamValues("0xabc")
+			c.Set("coin_router", router)
+
+			Expect(GetPortfolio(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "missing chain query parameter",
+				"code": 101
+			}`))
+		})
+
+		It("Should return an error if the portfolio could not be fetched", func() {
+			req := httptest.NewRequest(http.MethodGet, "/portfolio/0xabc?chain=ethereum", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("addr")
+			c.SetParamValues("0xabc")
+			c.Set("coin_router", router)
+
+			routerE := errors.New("all clients failed")
+			router.EXPECT().GetPortfolio(gomock.Eq("ethereum"), gomock.Eq("0xabc")).Return(nil, routerE)
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), gomock.Eq("0xabc"), gomock.Eq("ethereum"), gomock.Eq(routerE))
+
+			Expect(GetPortfolio(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "could not get portfolio of given address",
+				"code": 203
+			}`))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/reorg"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	// ReorgSyncTimeout bounds the sync of the reorg tracker run alongside a transaction lookup. A sync which doesn't
+	// finish in time is dropped and the transaction is checked against the blocks the tracker already has.
+	ReorgSyncTimeout = time.Second
+)
+
+// ReorgsResponse struct to map the recent reorgs of an asset to the required json format.
+type ReorgsResponse struct {
+	Data struct {
+		Reorgs []reorg.Event `json:"reorgs"`
+	} `json:"data"`
+}
+
+// SetReorgRegistryMiddlewareFunc applies a reorg registry to the context.
+// Transaction lookups check it to mark transactions whose block was orphaned.
+func SetReorgRegistryMiddlewareFunc(registry *reorg.Registry) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("reorg_registry", registry)
+
+			return next(c)
+		}
+	}
+}
+
+// GetReorgs returns the recent chain reorganisations detected for the asset, most recent first.
+func GetReorgs(c echo.Context) error {
+	c.Logger().Print("executing GetReorgs handler")
+
+	assetID := c.Param("assetId")
+	registry := c.Get("reorg_registry").(*reorg.Registry)
+
+	tracker, err := registry.Get(assetID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have reorg tracking functionality", assetID),
+			Code:  ErrorCodeReorgError,
+		})
+	}
+
+	if err := tracker.SyncContext(c.Request().Context()); err != nil {
+		c.Logger().Errorf("error syncing reorg tracker for coin: %s, err: %v", assetID, err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not sync the chain tip",
+			Code:  ErrorCodeReorgError,
+		})
+	}
+
+	var res ReorgsResponse
+	res.Data.Reorgs = tracker.Events()
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// syncReorgTracker starts syncing the reorg tracker of the asset, so that it runs alongside the lookups of the
+// request until ctx ends. The func returned waits for the sync and returns the tracker, or nil if reorg tracking
+// isn't enabled for the request or supported by the asset's client.
+func syncReorgTracker(ctx context.Context, c echo.Context, assetID string) func() *reorg.Tracker {
+	registry, ok := c.Get("reorg_registry").(*reorg.Registry)
+	if !ok {
+		return func() *reorg.Tracker { return nil }
+	}
+
+	tracker, err := registry.Get(assetID)
+	if err != nil {
+		return func() *reorg.Tracker { return nil }
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.SyncContext(ctx)
+	}()
+
+	var once sync.Once
+	return func() *reorg.Tracker {
+		once.Do(func() {
+			if err := <-done; err != nil {
+				c.Logger().Errorf("error syncing reorg tracker for coin: %s, err: %v", assetID, err)
+			}
+		})
+
+		return tracker
+	}
+}
+
+// markReorged flags the transaction with the given hash as no longer confirmed if its block was orphaned.
+func markReorged(tracker *reorg.Tracker, hash string, tx *transport.Transaction) bool {
+	if tracker == nil || tx == nil || !tracker.IsReorged(hash) {
+		return false
+	}
+
+	tx.Confirmations.Confirmed = false
+	tx.Confirmations.Reorged = true
+
+	return true
+}
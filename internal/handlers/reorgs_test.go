@@ -0,0 +1,193 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	"github.com/hugorut/coins-oracle/internal/reorg"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+type reorgClients map[string]transport.CoinClient
+
+func (r reorgClients) Get(name string) (transport.CoinClient, error) {
+	if client, ok := r[name]; ok {
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("could not find client named: %s", name)
+}
+
+var _ = Describe("Reorgs", func() {
+	var (
+		e        *echo.Echo
+		ctrl     *gomock.Controller
+		client   *mock_transport.MockCoinClient
+		fetcher  *mock_transport.MockBlockFetcher
+		logger   *mock_echo.MockLogger
+		registry *reorg.Registry
+	)
+
+	blockAt := func(hash, parent string) *transport.BlockResp {
+		res := &transport.BlockResp{}
+		res.Data.Block = transport.Block{
+			Height: 10,
+			Hash:   hash,
+			Parent: parent,
+			TxIDs:  []string{"tx-" + hash},
+		}
+
+		return res
+	}
+
+	tip := &transport.CoinState{Data: transport.CoinData{Chain: "main", BlockHeight: 10}}
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		fetcher = mock_transport.NewMockBlockFetcher(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+
+		registry = reorg.NewRegistry(reorgClients{
+			"test": struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockBlockFetcher
+			}{client, fetcher},
+			"plain": client,
+		})
+
+		// replace the tip block at height 10 so the tracker records a single block reorg.
+		tracker, err := registry.Get("test")
+		Expect(err).ToNot(HaveOccurred())
+		tracker.SyncInterval = 0
+		tracker.Window = 1
+
+		client.EXPECT().GetInfo().Return(tip, nil).AnyTimes()
+		gomock.InOrder(
+			fetcher.EXPECT().GetBlockByHeight(int64(10)).Return(blockAt("a10", "a9"), nil),
+			fetcher.EXPECT().GetBlockByHeight(int64(10)).Return(blockAt("b10", "a9"), nil).AnyTimes(),
+		)
+
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(tracker.Sync()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetReorgs", func() {
+		It("Should return the reorgs detected for the asset", func() {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test/reorgs", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId")
+			c.SetParamValues("test")
+			c.Set("reorg_registry", registry)
+
+			err := GetReorgs(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var res ReorgsResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+
+			Expect(res.Data.Reorgs).To(HaveLen(1))
+
+			r := res.Data.Reorgs[0]
+			Expect(r.AssetID).To(Equal("test"))
+			Expect(r.ForkHeight).To(Equal(int64(9)))
+			Expect(r.Depth).To(Equal(1))
+			Expect(r.OldTip).To(Equal("a10"))
+			Expect(r.NewTip).To(Equal("b10"))
+			Expect(r.Orphaned).To(Equal([]string{"a10"}))
+			Expect(r.ReorgedTxIDs).To(Equal([]string{"tx-a10"}))
+		})
+
+		It("Should return an error if the client can't look up blocks", func() {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/plain/reorgs", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId")
+			c.SetParamValues("plain")
+			c.Set("reorg_registry", registry)
+
+			err := GetReorgs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: plain does not have reorg tracking functionality",
+				"code": 403
+			}`))
+		})
+	})
+
+	Describe("GetTransactionByHash", func() {
+		It("Should mark a transaction from an orphaned block as reorged", func() {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test/txs/tx-a10", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "txHash")
+			c.SetParamValues("test", "tx-a10")
+			c.Set("coin_client", client)
+			c.Set("reorg_registry", registry)
+
+			var confirmations int64 = 6
+
+			tx := &transport.TransactionResp{}
+			tx.Data.Transaction = transport.Transaction{
+				ID:    "tx-a10",
+				From:  "addr1",
+				To:    "addr2",
+				Value: "1",
+				Confirmations: transport.Confirmations{
+					Threshold: transport.ConfirmThresholdValue,
+					Confirmed: true,
+					Value:     &confirmations,
+				},
+			}
+
+			client.EXPECT().GetTransactionByHash("tx-a10").Return(tx, nil)
+
+			err := GetTransactionByHash(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"transaction": {
+						"id": "tx-a10",
+						"from": "addr1",
+						"to": "addr2",
+						"value": "1",
+						"confirmations": {
+							"threshold": 5,
+							"confirmed": false,
+							"value": 6,
+							"reorged": true
+						}
+					}
+				}
+			}`))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// GetResources fetches the resources the chain allots to the address given in the url, e.g. cpu, net and ram.
+func GetResources(c echo.Context) error {
+	c.Logger().Print("executing GetResources handler")
+
+	inspector, ok := c.Get("coin_client").(transport.ResourceInspector)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have resource functionality", c.Param("assetId")),
+			Code:  ErrorCodeResourceError,
+		})
+	}
+
+	res, err := inspector.GetResources(c.Param("addr"))
+	if err != nil {
+		c.Logger().Errorf("error getting resources of addr: %s for coin: %s, err: %v", c.Param("addr"), c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get resources of given address",
+			Code:  ErrorCodeResourceError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Resources", func() {
+	var (
+		e         *echo.Echo
+		ctrl      *gomock.Controller
+		client    *mock_transport.MockCoinClient
+		inspector *mock_transport.MockResourceInspector
+		logger    *mock_echo.MockLogger
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		inspector = mock_transport.NewMockResourceInspector(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newContext := func(coinClient interface{}, addr string) (echo.Context, *httptest.ResponseRecorder) {
+		return newCoinContext(
+			e,
+			coinClient,
+			"/nodes/eos/addrs/"+addr+"/resources",
+			[]string{"assetId", "addr"},
+			[]string{"eos", addr},
+		)
+	}
+
+	resourceClient := func() interface{} {
+		return struct {
+			*mock_transport.MockCoinClient
+			*mock_transport.MockResourceInspector
+		}{client, inspector}
+	}
+
+	Describe("GetResources", func() {
+		It("Should return the resources of the address", func() {
+			c, rec := newContext(resourceClient(), "eospaceioeos")
+
+			res := &transport.ResourcesResp{}
+			res.Data.Resources = []transport.Resource{
+				{Resource: "cpu", Unit: "us", Used: 3837, Available: 14098, Max: 17935, Staked: "2.5000"},
+				{Resource: "ram", Unit: "bytes", Used: 3446, Available: 2021, Max: 5467},
+			}
+
+			inspector.EXPECT().GetResources("eospaceioeos").Return(res, nil)
+
+			Expect(GetResources(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"resources": [
+						{"resource": "cpu", "unit": "us", "used": 3837, "available": 14098, "max": 17935, "staked": "2.5000"},
+						{"resource": "ram", "unit": "bytes", "used": 3446, "available": 2021, "max": 5467}
+					]
+				}
+			}`))
+		})
+
+		It("Should return unlimited resources as -1 and omit the stake of accounts which have none", func() {
+			c, rec := newContext(resourceClient(), "eosio")
+
+			res := &transport.ResourcesResp{}
+			res.Data.Resources = []transport.Resource{
+				{Resource: "cpu", Unit: "us", Used: -1, Available: -1, Max: -1},
+				{Resource: "ram", Unit: "bytes", Used: 1832957, Available: -1, Max: -1},
+			}
+
+			inspector.EXPECT().GetResources("eosio").Return(res, nil)
+
+			Expect(GetResources(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"resources": [
+						{"resource": "cpu", "unit": "us", "used": -1, "available": -1, "max": -1},
+						{"resource": "ram", "unit": "bytes", "used": 1832957, "available": -1, "max": -1}
+					]
+				}
+			}`))
+		})
+
+		It("Should return an error if the client doesn't meter resources", func() {
+			c, rec := newContext(client, "eospaceioeos")
+
+			Expect(GetResources(c)).To(Succeed())
+			expectBadRequest(rec, "client: eos does not have resource functionality", ErrorCodeResourceError)
+		})
+
+		It("Should return an error if the resources can't be fetched", func() {
+			c, rec := newContext(resourceClient(), "eospaceioeos")
+
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "eospaceioeos", "eos", gomock.Any())
+			inspector.EXPECT().GetResources("eospaceioeos").Return(nil, errors.New("unknown key"))
+
+			Expect(GetResources(c)).To(Succeed())
+			expectBadRequest(rec, "could not get resources of given address", ErrorCodeResourceError)
+		})
+	})
+})
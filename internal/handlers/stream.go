@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/stream"
+)
+
+var (
+	// StreamKeepAlive is how often a comment is written to idle block streams so proxies don't close them.
+	StreamKeepAlive = 15 * time.Second
+
+	upgrader = websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+)
+
+// SetBlockHubMiddlewareFunc applies a block hub to the context.
+func SetBlockHubMiddlewareFunc(hub *stream.Hub) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("block_hub", hub)
+
+			return next(c)
+		}
+	}
+}
+
+// StreamBlocks pushes a server-sent event every time the chain tip of the asset changes.
+func StreamBlocks(c echo.Context) error {
+	c.Logger().Print("executing StreamBlocks handler")
+	hub := c.Get("block_hub").(*stream.Hub)
+
+	// writers which buffer the whole body, like the lambda proxy's, can't send events as they happen.
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return streamNotSupported(c)
+	}
+
+	events, unsubscribe, err := hub.Subscribe(c.Param("assetId"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, genericResponse{
+			Error: fmt.Sprintf("asset: %s was not found", c.Param("assetId")),
+			Code:  ErrorInvalidRequest,
+		})
+	}
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(StreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case e := <-events:
+			raw, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(res, "event: block\ndata: %s\n\n", raw); err != nil {
+				return nil
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+// StreamBlocksWS pushes a json message over a websocket every time the chain tip of the asset changes.
+func StreamBlocksWS(c echo.Context) error {
+	c.Logger().Print("executing StreamBlocksWS handler")
+	hub := c.Get("block_hub").(*stream.Hub)
+
+	if _, ok := c.Response().Writer.(http.Hijacker); !ok {
+		return streamNotSupported(c)
+	}
+
+	events, unsubscribe, err := hub.Subscribe(c.Param("assetId"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, genericResponse{
+			Error: fmt.Sprintf("asset: %s was not found", c.Param("assetId")),
+			Code:  ErrorInvalidRequest,
+		})
+	}
+	defer unsubscribe()
+
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		c.Logger().Errorf("error upgrading block stream for coin: %s, err: %v", c.Param("assetId"), err)
+		return nil
+	}
+	defer conn.Close()
+
+	// the client doesn't send anything, reading is only used to notice when it goes away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(StreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return nil
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return nil
+			}
+		case e := <-events:
+			if err := conn.WriteJSON(e); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// streamNotSupported responds to a stream request the server can't hold open, e.g. when served through lambda.
+func streamNotSupported(c echo.Context) error {
+	return c.JSON(http.StatusNotImplemented, genericResponse{
+		Error: "block streams are not supported by this server, run it with HTTP_ADDR set to stream blocks",
+		Code:  ErrorCodeStreamError,
+	})
+}
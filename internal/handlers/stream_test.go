@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/echo"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	"github.com/hugorut/coins-oracle/internal/stream"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	transport2 "github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Stream", func() {
+	var (
+		e      *echo.Echo
+		ctrl   *gomock.Controller
+		router *mock_transport.MockRouter
+		client *mock_transport.MockCoinClient
+		logger *mock_echo.MockLogger
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		logger = mock_echo.NewMockLogger(ctrl)
+		router = mock_transport.NewMockRouter(ctrl)
+		client = mock_transport.NewMockCoinClient(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+
+		router.EXPECT().Get(gomock.Eq("btc")).Return(client, nil).AnyTimes()
+		client.EXPECT().GetInfo().Return(&transport2.CoinState{
+			Data: transport2.CoinData{Chain: "main", BlockHeight: 10, CurrentBlock: "hash"},
+		}, nil).AnyTimes()
+
+		hub := &stream.Hub{Clients: router, PollInterval: time.Hour}
+		e.GET("/nodes/:assetId/blocks/stream", StreamBlocks, SetBlockHubMiddlewareFunc(hub))
+		e.GET("/nodes/:assetId/blocks/ws", StreamBlocksWS, SetBlockHubMiddlewareFunc(hub))
+
+		server = httptest.NewServer(e)
+	})
+
+	AfterEach(func() {
+		server.Close()
+		ctrl.Finish()
+	})
+
+	Describe("StreamBlocks", func() {
+		It("Should send the chain tip as a server-sent event", func() {
+			res, err := http.Get(server.URL + "/nodes/btc/blocks/stream")
+			Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+
+			Expect(res.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+			r := bufio.NewReader(res.Body)
+
+			line, err := r.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			Expect(line).To(Equal("event: block\n"))
+
+			line, err = r.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strings.TrimPrefix(line, "data: ")).To(MatchJSON(`{
+				"assetId": "btc",
+				"chain": "main",
+				"block_height": 10,
+				"current_block_hash": "hash"
+			}`))
+		})
+	})
+
+	Describe("behind the lambda adapter", func() {
+		// the adapter's response writer buffers the body, so it can neither flush events nor be hijacked.
+		for _, path := range []string{"/nodes/btc/blocks/stream", "/nodes/btc/blocks/ws"} {
+			path := path
+
+			It("Should respond that "+path+" is not supported", func() {
+				res, err := echoadapter.New(e).Proxy(events.APIGatewayProxyRequest{
+					Path:       path,
+					HTTPMethod: http.MethodGet,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.StatusCode).To(Equal(http.StatusNotImplemented))
+
+				var body map[string]interface{}
+				Expect(json.Unmarshal([]byte(res.Body), &body)).To(Succeed())
+				Expect(body["code"]).To(BeEquivalentTo(ErrorCodeStreamError))
+			})
+		}
+	})
+
+	Describe("StreamBlocksWS", func() {
+		It("Should send the chain tip as a websocket message", func() {
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/nodes/btc/blocks/ws", nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			_, msg, err := conn.ReadMessage()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(msg).To(MatchJSON(`{
+				"assetId": "btc",
+				"chain": "main",
+				"block_height": 10,
+				"current_block_hash": "hash"
+			}`))
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	contractReg = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// GetTokenBalance fetches the balance of the address in the token contract given in the url, so that tokens
+// which haven't been configured as an asset can be looked up on the coin's chain.
+func GetTokenBalance(c echo.Context) error {
+	c.Logger().Print("executing GetTokenBalance handler")
+
+	contract, addr := c.Param("contract"), c.Param("addr")
+	if !contractReg.MatchString(contract) {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "contract must be a hex encoded address",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	getter, ok := c.Get("coin_client").(transport.TokenBalanceGetter)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have token balance functionality", c.Param("assetId")),
+			Code:  ErrorCodeTokenError,
+		})
+	}
+
+	res, err := getter.GetTokenBalance(contract, addr)
+	if err != nil {
+		c.Logger().Errorf("error getting balance of address: %s in token: %s for coin: %s, err: %v", addr, contract, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get token balance of given address",
+			Code:  ErrorCodeTokenError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Tokens", func() {
+	var (
+		e      *echo.Echo
+		ctrl   *gomock.Controller
+		client *mock_transport.MockCoinClient
+		getter *mock_transport.MockTokenBalanceGetter
+		logger *mock_echo.MockLogger
+	)
+
+	contract := "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984"
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		getter = mock_transport.NewMockTokenBalanceGetter(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newContext := func(coinClient interface{}, contract string) (echo.Context, *httptest.ResponseRecorder) {
+		return newCoinContext(
+			e,
+			coinClient,
+			"/nodes/eth/tokens/"+contract+"/addrs/address/balance",
+			[]string{"assetId", "contract", "addr"},
+			[]string{"eth", contract, "address"},
+		)
+	}
+
+	tokenClient := func() interface{} {
+		return struct {
+			*mock_transport.MockCoinClient
+			*mock_transport.MockTokenBalanceGetter
+		}{client, getter}
+	}
+
+	Describe("GetTokenBalance", func() {
+		It("Should return the balance read from the contract", func() {
+			c, rec := newContext(tokenClient(), contract)
+
+			res := &transport.TokenBalanceResp{}
+			res.Data.Token = transport.TokenBalance{
+				Contract:   contract,
+				Symbol:     "UNI",
+				Decimals:   18,
+				Balance:    "1.5",
+				RawBalance: "1500000000000000000",
+			}
+
+			getter.EXPECT().GetTokenBalance(contract, "address").Return(res, nil)
+
+			err := GetTokenBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"token": {
+						"contract": "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984",
+						"symbol": "UNI",
+						"decimals": 18,
+						"balance": "1.5",
+						"rawBalance": "1500000000000000000"
+					}
+				}
+			}`))
+		})
+
+		It("Should reject a contract which isn't an address", func() {
+			c, rec := newContext(tokenClient(), "uniswap")
+
+			err := GetTokenBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "contract must be a hex encoded address", ErrorInvalidRequest)
+		})
+
+		It("Should return a bad request when the client fails", func() {
+			c, rec := newContext(tokenClient(), contract)
+
+			getter.EXPECT().GetTokenBalance(contract, "address").Return(nil, errors.New("execution reverted"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "address", contract, "eth", gomock.Any())
+
+			err := GetTokenBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "could not get token balance of given address", ErrorCodeTokenError)
+		})
+
+		It("Should return a bad request when the client can't look up tokens", func() {
+			c, rec := newContext(client, contract)
+
+			err := GetTokenBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectBadRequest(rec, "client: eth does not have token balance functionality", ErrorCodeTokenError)
+		})
+	})
+})
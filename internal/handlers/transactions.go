@@ -1,13 +1,32 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo"
+
+	transport2 "github.com/hugorut/coins-oracle/internal/transport"
+)
+
+var (
+	// DefaultWaitTimeout is how long WaitForTransaction blocks when no timeout is given and the request has no deadline.
+	DefaultWaitTimeout = 20 * time.Second
+	// MaxWaitTimeout caps the timeout of WaitForTransaction when the request has no deadline.
+	MaxWaitTimeout = 25 * time.Second
+	// WaitResponseMargin is kept back from the request's deadline, e.g. the lambda's, to write the response.
+	WaitResponseMargin = 500 * time.Millisecond
 )
 
+// TransactionWaitResponse struct to map the outcome of waiting for a transaction to the required json format.
+type TransactionWaitResponse struct {
+	Data *transport2.TransactionWait `json:"data"`
+}
+
 // GetTransactionByHash fetches information about a transaction on a ledger by its hash.
 func GetTransactionByHash(c echo.Context) error {
 	c.Logger().Print("executing GetTransactionByHash handler")
@@ -15,6 +34,11 @@ func GetTransactionByHash(c echo.Context) error {
 	hash := c.Param("txHash")
 	client := c.Get("coin_client").(transport.CoinClient)
 
+	ctx, cancel := context.WithTimeout(c.Request().Context(), ReorgSyncTimeout)
+	defer cancel()
+
+	tracker := syncReorgTracker(ctx, c, c.Param("assetId"))
+
 	tr, err := client.GetTransactionByHash(hash)
 	if err != nil {
 		c.Logger().Errorf("error getting transaction for hash: %s for coin: %s, err: %v", hash, c.Param("assetId"), err)
@@ -24,5 +48,81 @@ func GetTransactionByHash(c echo.Context) error {
 		})
 	}
 
+	markReorged(tracker(), hash, &tr.Data.Transaction)
+
 	return c.JSON(http.StatusOK, tr)
 }
+
+// WaitForTransaction blocks until the transaction reaches the number of confirmations given in the confirmations
+// query param, is dropped or reorged, or the timeout query param elapses. The timeout accepts a duration, e.g. 30s,
+// or a number of seconds. When the request has a deadline the wait ends WaitResponseMargin before it, otherwise the
+// timeout defaults to DefaultWaitTimeout and is capped at MaxWaitTimeout.
+func WaitForTransaction(c echo.Context) error {
+	c.Logger().Print("executing WaitForTransaction handler")
+
+	hash := c.Param("txHash")
+	client := c.Get("coin_client").(transport.CoinClient)
+
+	confirmations := *transport.ConfirmThresholdValue
+	if v := c.QueryParam("confirmations"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "confirmations must be a positive integer",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+
+		confirmations = n
+	}
+
+	var timeout time.Duration
+	if v := c.QueryParam("timeout"); v != "" {
+		d, err := parseTimeout(v)
+		if err != nil || d <= 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "timeout must be a positive duration, e.g. 30s",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+
+		timeout = d
+	}
+
+	if deadline, ok := c.Request().Context().Deadline(); ok {
+		budget := time.Until(deadline) - WaitResponseMargin
+		if timeout == 0 || timeout > budget {
+			timeout = budget
+		}
+	} else {
+		if timeout == 0 {
+			timeout = DefaultWaitTimeout
+		}
+
+		if timeout > MaxWaitTimeout {
+			timeout = MaxWaitTimeout
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	defer cancel()
+
+	tracker := syncReorgTracker(ctx, c, c.Param("assetId"))
+
+	wait := transport2.WaitForTransaction(ctx, client, hash, confirmations)
+	if markReorged(tracker(), hash, wait.Transaction) {
+		wait.State = transport2.TxStateReorged
+	}
+
+	return c.JSON(http.StatusOK, TransactionWaitResponse{
+		Data: wait,
+	})
+}
+
+func parseTimeout(v string) (time.Duration, error) {
+	if n, err := strconv.Atoi(v); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+
+	return time.ParseDuration(v)
+}
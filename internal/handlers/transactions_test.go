@@ -1,10 +1,12 @@
 package handlers_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo"
@@ -97,4 +99,120 @@ var _ = Describe("Wallets", func() {
 			Expect(rec.Code).To(Equal(http.StatusOK))
 		})
 	})
+
+	Describe("WaitForTransaction", func() {
+		It("Should return the confirmed transaction with its state", func() {
+			assetID := "test-node"
+			hash := "hash1234"
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/nodes/%s/txs/%s/wait?confirmations=1&timeout=5s", assetID, hash), nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "txHash")
+			c.SetParamValues(assetID, hash)
+
+			c.Set("coin_client", client)
+
+			var confirmationsValue int64 = 1
+			client.EXPECT().GetTransactionByHash(gomock.Eq(hash)).Return(&transport.TransactionResp{
+				Data: struct {
+					Transaction transport.Transaction `json:"transaction"`
+				}{
+					Transaction: transport.Transaction{
+						ID: hash,
+						Confirmations: transport.Confirmations{
+							Value: &confirmationsValue,
+						},
+					},
+				},
+			}, nil)
+
+			Expect(WaitForTransaction(c)).To(Succeed())
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"state": "confirmed",
+					"transaction": {
+						"id": "hash1234",
+						"from": "",
+						"to": "",
+						"value": "",
+						"confirmations": {
+							"confirmed": false,
+							"value": 1
+						}
+					}
+				}
+			}`))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should stop waiting before the deadline of the request", func() {
+			hash := "hash1234"
+
+			ctx, cancel := context.WithTimeout(context.Background(), WaitResponseMargin+200*time.Millisecond)
+			defer cancel()
+
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test-node/txs/"+hash+"/wait?timeout=20s", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "txHash")
+			c.SetParamValues("test-node", hash)
+
+			c.Set("coin_client", client)
+
+			var confirmationsValue int64
+			client.EXPECT().GetTransactionByHash(gomock.Eq(hash)).Return(&transport.TransactionResp{
+				Data: struct {
+					Transaction transport.Transaction `json:"transaction"`
+				}{
+					Transaction: transport.Transaction{
+						ID: hash,
+						Confirmations: transport.Confirmations{
+							Value: &confirmationsValue,
+						},
+					},
+				},
+			}, nil).AnyTimes()
+
+			Expect(WaitForTransaction(c)).To(Succeed())
+			Expect(ctx.Err()).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"state": "pending",
+					"transaction": {
+						"id": "hash1234",
+						"from": "",
+						"to": "",
+						"value": "",
+						"confirmations": {
+							"confirmed": false,
+							"value": 0
+						}
+					}
+				}
+			}`))
+		})
+
+		It("Should reject an invalid timeout", func() {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test-node/txs/hash1234/wait?timeout=soon", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "txHash")
+			c.SetParamValues("test-node", "hash1234")
+
+			c.Set("coin_client", client)
+
+			Expect(WaitForTransaction(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "timeout must be a positive duration, e.g. 30s",
+				"code": 101
+			}`))
+		})
+	})
 })
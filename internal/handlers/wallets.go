@@ -5,18 +5,90 @@ import (
 	"fmt"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo"
 )
 
-// GetWalletBalance fetches the current balance of assets in the address.
+// GetWalletBalance fetches the current balance of assets in the address. Passing minconf in the query
+// only counts funds with at least that many confirmations, passing at returns the balance as of a block
+// height or RFC3339 timestamp, for clients that support it.
 func GetWalletBalance(c echo.Context) error {
 	c.Logger().Print("executing GetWalletBalance handler")
 
 	addr := c.Param("addr")
 	client := c.Get("coin_client").(transport.CoinClient)
 
-	ob, err := client.GetBalance(addr)
+	var (
+		ob  *transport.Balance
+		err error
+	)
+
+	at, minConfParam := c.QueryParam("at"), c.QueryParam("minconf")
+	switch {
+	case at != "" && minConfParam != "":
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "at and minconf can not be used together",
+			Code:  ErrorInvalidRequest,
+		})
+	case at != "":
+		getter, ok := client.(transport.HistoricalBalanceGetter)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: fmt.Sprintf("client: %s does not have historical balance functionality", c.Param("assetId")),
+				Code:  ErrorCodeBalanceError,
+			})
+		}
+
+		height, perr := strconv.ParseInt(at, 10, 64)
+		if perr != nil {
+			t, terr := time.Parse(time.RFC3339, at)
+			if terr != nil {
+				return c.JSON(http.StatusBadRequest, genericResponse{
+					Error: "at must be a block height or an RFC3339 timestamp",
+					Code:  ErrorInvalidRequest,
+				})
+			}
+
+			height, err = heightAt(client, getter, t)
+			if err != nil {
+				c.Logger().Errorf("error finding block at: %s for coin: %s, err: %v", at, c.Param("assetId"), err)
+				return c.JSON(http.StatusBadRequest, genericResponse{
+					Error: "could not find the block at the given time",
+					Code:  ErrorCodeBalanceError,
+				})
+			}
+		} else if height < 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "at must be a block height or an RFC3339 timestamp",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+
+		ob, err = getter.GetBalanceAt(addr, height)
+	case minConfParam != "":
+		minConf, perr := strconv.Atoi(minConfParam)
+		if perr != nil || minConf < 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "minconf must be a non negative integer",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+
+		getter, ok := client.(transport.ConfirmedBalanceGetter)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: fmt.Sprintf("client: %s does not have confirmed balance functionality", c.Param("assetId")),
+				Code:  ErrorCodeBalanceError,
+			})
+		}
+
+		ob, err = getter.GetConfirmedBalance(addr, minConf)
+	default:
+		ob, err = client.GetBalance(addr)
+	}
+
 	if err != nil {
 		c.Logger().Errorf("error getting balance for wallet address: %s for coin: %s, err: %v", addr, c.Param("assetId"), err)
 		return c.JSON(http.StatusBadRequest, genericResponse{
@@ -28,15 +100,62 @@ func GetWalletBalance(c echo.Context) error {
 	return c.JSON(http.StatusOK, ob)
 }
 
-type importAddressReq struct {
-	Addr string `json:"addr"`
+// heightAt resolves t to the last block mined at or before it, searching up to the client's current height.
+func heightAt(client transport.CoinClient, getter transport.HistoricalBalanceGetter, t time.Time) (int64, error) {
+	info, err := client.GetInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return transport.HeightAt(t, int64(info.Data.BlockHeight), getter.GetBlockTime)
+}
+
+// GetUTXOs lists the unspent outputs of the address. Passing minconf in the query sets the confirmations
+// an output needs to be listed, defaulting to 1.
+func GetUTXOs(c echo.Context) error {
+	c.Logger().Print("executing GetUTXOs handler")
+
+	addr := c.Param("addr")
+
+	minConf := 1
+	if v := c.QueryParam("minconf"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "minconf must be a non negative integer",
+				Code:  ErrorInvalidRequest,
+			})
+		}
+
+		minConf = parsed
+	}
+
+	client, ok := c.Get("coin_client").(transport.UTXOLister)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have utxo listing functionality", c.Param("assetId")),
+			Code:  ErrorCodeUTXOError,
+		})
+	}
+
+	res, err := client.ListUTXOs(addr, minConf)
+	if err != nil {
+		c.Logger().Errorf("error listing utxos for wallet address: %s for coin: %s, err: %v", addr, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not list unspent outputs of given address",
+			Code:  ErrorCodeUTXOError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
 }
 
-// ImportAddress tells the asset to index a specific address so that wallet functionality can occur in the future.
+// ImportAddress tells the asset to index an address, or the addresses of an xpub, so that wallet functionality
+// can occur in the future. Clients which import in the background respond straight away with the import job.
 func ImportAddress(c echo.Context) error {
 	c.Logger().Print("executing ImportAddress handler")
 
-	var req importAddressReq
+	var req transport.ImportReq
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, genericResponse{
 			Error: "missing addr field in request",
@@ -44,6 +163,19 @@ func ImportAddress(c echo.Context) error {
 		})
 	}
 
+	if scheduler, ok := c.Get("coin_client").(transport.ImportScheduler); ok {
+		res, err := scheduler.StartImport(req)
+		if err != nil {
+			c.Logger().Errorf("error starting import of address: %s xpub: %s for coin: %s, err: %v", req.Addr, req.XPub, c.Param("assetId"), err)
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "could not import address",
+				Code:  ErrorCodeCannotImport,
+			})
+		}
+
+		return c.JSON(http.StatusAccepted, res)
+	}
+
 	client, ok := c.Get("coin_client").(transport.AddressImporter)
 	if !ok {
 		return c.JSON(http.StatusBadRequest, genericResponse{
@@ -52,6 +184,13 @@ func ImportAddress(c echo.Context) error {
 		})
 	}
 
+	if req.XPub != "" {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s can not import xpubs", c.Param("assetId")),
+			Code:  ErrorCodeCannotImport,
+		})
+	}
+
 	err := client.ImportAddress(req.Addr)
 	if err != nil {
 		c.Logger().Errorf("error getting importing address: %s for coin: %s, err: %v", req.Addr, c.Param("assetId"), err)
@@ -63,3 +202,36 @@ func ImportAddress(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, successResponse)
 }
+
+// GetImport reports the progress of an import started by ImportAddress.
+func GetImport(c echo.Context) error {
+	c.Logger().Print("executing GetImport handler")
+
+	id := c.Param("id")
+
+	client, ok := c.Get("coin_client").(transport.ImportScheduler)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: fmt.Sprintf("client: %s does not have import job functionality", c.Param("assetId")),
+			Code:  ErrorCodeCannotImport,
+		})
+	}
+
+	res, err := client.GetImport(id)
+	if err == transport.ErrImportNotFound {
+		return c.JSON(http.StatusNotFound, genericResponse{
+			Error: fmt.Sprintf("import: %s was not found", id),
+			Code:  ErrorCodeCannotImport,
+		})
+	}
+
+	if err != nil {
+		c.Logger().Errorf("error getting import: %s for coin: %s, err: %v", id, c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get import",
+			Code:  ErrorCodeCannotImport,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo"
@@ -111,4 +113,495 @@ var _ = Describe("Wallets", func() {
 			})
 		})
 	})
+
+	Describe("GetUTXOs", func() {
+		var lister *mock_transport.MockUTXOLister
+
+		BeforeEach(func() {
+			lister = mock_transport.NewMockUTXOLister(ctrl)
+		})
+
+		newContext := func(coinClient interface{}, query string) (echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test-node/addrs/address/utxos"+query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "addr")
+			c.SetParamValues("test-node", "address")
+			c.Set("coin_client", coinClient)
+
+			return c, rec
+		}
+
+		utxoClient := func() interface{} {
+			return struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockUTXOLister
+			}{client, lister}
+		}
+
+		It("Should list the outputs with at least one confirmation by default", func() {
+			c, rec := newContext(utxoClient(), "")
+
+			res := &transport.UTXOsResp{}
+			res.Data.UTXOs = []transport.UTXO{
+				{
+					TxID:          "txid1",
+					Vout:          1,
+					Amount:        25000000,
+					Script:        "76a914b5f0f59ed466f998aae81497a2b895e89525d98888ac",
+					Confirmations: 12,
+				},
+			}
+
+			lister.EXPECT().ListUTXOs("address", 1).Return(res, nil)
+
+			err := GetUTXOs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"utxos": [
+						{
+							"txid": "txid1",
+							"vout": 1,
+							"amount": 25000000,
+							"script": "76a914b5f0f59ed466f998aae81497a2b895e89525d98888ac",
+							"confirmations": 12
+						}
+					]
+				}
+			}`))
+		})
+
+		It("Should pass minconf through to the client", func() {
+			c, rec := newContext(utxoClient(), "?minconf=0")
+
+			res := &transport.UTXOsResp{}
+			res.Data.UTXOs = []transport.UTXO{}
+
+			lister.EXPECT().ListUTXOs("address", 0).Return(res, nil)
+
+			err := GetUTXOs(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should reject an invalid minconf", func() {
+			c, rec := newContext(utxoClient(), "?minconf=-1")
+
+			err := GetUTXOs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "minconf must be a non negative integer",
+				"code": 101
+			}`))
+		})
+
+		It("Should return a bad request when the client fails", func() {
+			c, rec := newContext(utxoClient(), "")
+
+			lister.EXPECT().ListUTXOs("address", 1).Return(nil, errors.New("node down"))
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "address", "test-node", gomock.Any())
+
+			err := GetUTXOs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "could not list unspent outputs of given address",
+				"code": 204
+			}`))
+		})
+
+		It("Should return a bad request when the client can't list utxos", func() {
+			c, rec := newContext(client, "")
+
+			err := GetUTXOs(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: test-node does not have utxo listing functionality",
+				"code": 204
+			}`))
+		})
+	})
+
+	Describe("GetBalance with minconf", func() {
+		var getter *mock_transport.MockConfirmedBalanceGetter
+
+		BeforeEach(func() {
+			getter = mock_transport.NewMockConfirmedBalanceGetter(ctrl)
+		})
+
+		newContext := func(coinClient interface{}, query string) (echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test-node/addrs/address/balance"+query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "addr")
+			c.SetParamValues("test-node", "address")
+			c.Set("coin_client", coinClient)
+
+			return c, rec
+		}
+
+		confirmedClient := func() interface{} {
+			return struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockConfirmedBalanceGetter
+			}{client, getter}
+		}
+
+		It("Should return the balance at the requested confirmation depth", func() {
+			c, rec := newContext(confirmedClient(), "?minconf=6")
+
+			getter.EXPECT().GetConfirmedBalance("address", 6).Return(&transport.Balance{
+				Data: transport.BalanceData{
+					Assets: []transport.Asset{
+						{
+							Asset:       "test-node",
+							Balance:     "14",
+							Confirmed:   "14",
+							Unconfirmed: "2",
+						},
+					},
+				},
+			}, nil)
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"assets": [
+						{
+							"asset": "test-node",
+							"balance": "14",
+							"confirmed": "14",
+							"unconfirmed": "2"
+						}
+					]
+				}
+			}`))
+		})
+
+		It("Should reject an invalid minconf", func() {
+			c, rec := newContext(confirmedClient(), "?minconf=abc")
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "minconf must be a non negative integer",
+				"code": 101
+			}`))
+		})
+
+		It("Should return a bad request when the client can't get confirmed balances", func() {
+			c, rec := newContext(client, "?minconf=1")
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: test-node does not have confirmed balance functionality",
+				"code": 202
+			}`))
+		})
+	})
+
+	Describe("GetBalance with at", func() {
+		var getter *mock_transport.MockHistoricalBalanceGetter
+
+		BeforeEach(func() {
+			getter = mock_transport.NewMockHistoricalBalanceGetter(ctrl)
+		})
+
+		newContext := func(coinClient interface{}, query string) (echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(http.MethodGet, "/nodes/test-node/addrs/address/balance"+query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "addr")
+			c.SetParamValues("test-node", "address")
+			c.Set("coin_client", coinClient)
+
+			return c, rec
+		}
+
+		historicalClient := func() interface{} {
+			return struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockHistoricalBalanceGetter
+			}{client, getter}
+		}
+
+		balance := &transport.Balance{
+			Data: transport.BalanceData{
+				Assets: []transport.Asset{
+					{
+						Asset:   "test-node",
+						Balance: "14",
+					},
+				},
+			},
+		}
+
+		It("Should return the balance at the given height", func() {
+			c, rec := newContext(historicalClient(), "?at=8000000")
+
+			getter.EXPECT().GetBalanceAt("address", int64(8000000)).Return(balance, nil)
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"assets": [
+						{
+							"asset": "test-node",
+							"balance": "14"
+						}
+					]
+				}
+			}`))
+		})
+
+		It("Should resolve a timestamp to the last block mined before it", func() {
+			c, rec := newContext(historicalClient(), "?at=2020-01-31T23:59:59Z")
+
+			genesis := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+			client.EXPECT().GetInfo().Return(&transport.CoinState{
+				Data: transport.CoinData{BlockHeight: 100},
+			}, nil)
+			// one block a day
+			getter.EXPECT().GetBlockTime(gomock.Any()).DoAndReturn(func(height int64) (time.Time, error) {
+				return genesis.AddDate(0, 0, int(height)), nil
+			}).AnyTimes()
+			getter.EXPECT().GetBalanceAt("address", int64(30)).Return(balance, nil)
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Should reject an invalid at", func() {
+			c, rec := newContext(historicalClient(), "?at=yesterday")
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "at must be a block height or an RFC3339 timestamp",
+				"code": 101
+			}`))
+		})
+
+		It("Should reject at combined with minconf", func() {
+			c, rec := newContext(historicalClient(), "?at=10&minconf=1")
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "at and minconf can not be used together",
+				"code": 101
+			}`))
+		})
+
+		It("Should return a bad request when the client can't get historical balances", func() {
+			c, rec := newContext(client, "?at=10")
+
+			err := GetWalletBalance(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": null,
+				"error": "client: test-node does not have historical balance functionality",
+				"code": 202
+			}`))
+		})
+	})
+
+	Describe("ImportAddress", func() {
+		It("Should respond with the import job of clients which import in the background", func() {
+			scheduler := mock_transport.NewMockImportScheduler(ctrl)
+
+			body := `{"xpub": "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", "script": "p2wpkh", "birthHeight": 595303}`
+			req := httptest.NewRequest(http.MethodPost, "/nodes/btc/addrs/import", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId")
+			c.SetParamValues("btc")
+
+			c.Set("coin_client", scheduler)
+
+			res := &transport.ImportResp{}
+			res.Data.Import = transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     "BTC",
+				Descriptors: []string{"wpkh(xpub/0/*)#wvk84d79", "wpkh(xpub/1/*)#lcnxgcwa"},
+				Timestamp:   1568729415,
+				Status:      transport.ImportScanning,
+				StartedAt:   time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+			}
+
+			scheduler.EXPECT().StartImport(transport.ImportReq{
+				XPub:        "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+				Script:      "p2wpkh",
+				BirthHeight: 595303,
+			}).Return(res, nil)
+
+			err := ImportAddress(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"import": {
+						"id": "5f0c",
+						"assetId": "BTC",
+						"descriptors": ["wpkh(xpub/0/*)#wvk84d79", "wpkh(xpub/1/*)#lcnxgcwa"],
+						"timestamp": 1568729415,
+						"status": "scanning",
+						"progress": 0,
+						"startedAt": "2020-09-13T12:26:40Z"
+					}
+				}
+			}`))
+		})
+
+		It("Should not import xpubs with clients which only import addresses", func() {
+			importer := mock_transport.NewMockAddressImporter(ctrl)
+
+			req := httptest.NewRequest(http.MethodPost, "/nodes/eth/addrs/import", strings.NewReader(`{"xpub": "xpub661MyMwAqRbc"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId")
+			c.SetParamValues("eth")
+
+			c.Set("coin_client", importer)
+
+			err := ImportAddress(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "client: eth can not import xpubs",
+				"code": 201
+			}`))
+		})
+	})
+
+	Describe("GetImport", func() {
+		var scheduler *mock_transport.MockImportScheduler
+
+		BeforeEach(func() {
+			scheduler = mock_transport.NewMockImportScheduler(ctrl)
+		})
+
+		newContext := func(id string) (echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/nodes/btc/imports/%s", id), nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("assetId", "id")
+			c.SetParamValues("btc", id)
+
+			c.Set("coin_client", scheduler)
+			return c, rec
+		}
+
+		It("Should render the progress of the import", func() {
+			c, rec := newContext("5f0c")
+
+			res := &transport.ImportResp{}
+			res.Data.Import = transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     "BTC",
+				Descriptors: []string{"addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"},
+				Status:      transport.ImportScanning,
+				Progress:    0.25,
+				StartedAt:   time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+			}
+
+			scheduler.EXPECT().GetImport("5f0c").Return(res, nil)
+
+			err := GetImport(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": {
+					"import": {
+						"id": "5f0c",
+						"assetId": "BTC",
+						"descriptors": ["addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"],
+						"timestamp": 0,
+						"status": "scanning",
+						"progress": 0.25,
+						"startedAt": "2020-09-13T12:26:40Z"
+					}
+				}
+			}`))
+		})
+
+		It("Should respond not found for an unknown import", func() {
+			c, rec := newContext("unknown")
+
+			scheduler.EXPECT().GetImport("unknown").Return(nil, transport.ErrImportNotFound)
+
+			err := GetImport(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "import: unknown was not found",
+				"code": 201
+			}`))
+		})
+
+		It("Should respond with an error when the node can't be asked for the progress", func() {
+			c, rec := newContext("5f0c")
+
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), "5f0c", "btc", gomock.Any())
+			scheduler.EXPECT().GetImport("5f0c").Return(nil, errors.New("connection refused"))
+
+			err := GetImport(c)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "could not get import",
+				"code": 201
+			}`))
+		})
+	})
 })
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/internal/watch"
+	transport2 "github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// WatchResponse struct to map a watch to the required json format.
+type WatchResponse struct {
+	Data struct {
+		Watch *watch.Watch `json:"watch"`
+	} `json:"data"`
+}
+
+// WatchesResponse struct to map a list of watches to the required json format.
+type WatchesResponse struct {
+	Data struct {
+		Watches []*watch.Watch `json:"watches"`
+	} `json:"data"`
+}
+
+// DeadLettersResponse struct to map the undelivered watch events to the required json format.
+type DeadLettersResponse struct {
+	Data struct {
+		DeadLetters []watch.DeadLetter `json:"deadLetters"`
+	} `json:"data"`
+}
+
+type createWatchReq struct {
+	AssetID          string `json:"assetId"`
+	Addr             string `json:"addr"`
+	CallbackURL      string `json:"callbackURL"`
+	MinConfirmations int64  `json:"minConfirmations"`
+}
+
+// SetWatchStoreMiddlewareFunc applies a watch store to the context.
+func SetWatchStoreMiddlewareFunc(store watch.Store) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("watch_store", store)
+
+			return next(c)
+		}
+	}
+}
+
+// CreateWatch registers a callback url to be notified of new transactions to an address.
+// If the asset client supports importing addresses the address is imported so the node tracks it.
+// The watch secret used to sign callbacks is only returned in this response.
+func CreateWatch(c echo.Context) error {
+	c.Logger().Print("executing CreateWatch handler")
+
+	var req createWatchReq
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "request body must contain assetId, addr and callbackURL fields",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if req.AssetID == "" || req.Addr == "" || req.MinConfirmations < 0 {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "request body must contain assetId, addr and callbackURL fields",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if u, err := url.Parse(req.CallbackURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "callbackURL must be a valid http or https url",
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	router := c.Get("coin_router").(transport.Resolver)
+	store := c.Get("watch_store").(watch.Store)
+
+	client, err := router.Get(req.AssetID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, genericResponse{
+			Error: fmt.Sprintf("asset: %s was not found", req.AssetID),
+			Code:  ErrorInvalidRequest,
+		})
+	}
+
+	if importer, ok := client.(transport2.AddressImporter); ok {
+		if err := importer.ImportAddress(req.Addr); err != nil {
+			c.Logger().Errorf("error importing address: %s for coin: %s, err: %v", req.Addr, req.AssetID, err)
+			return c.JSON(http.StatusBadRequest, genericResponse{
+				Error: "could not import address",
+				Code:  ErrorCodeCannotImport,
+			})
+		}
+	}
+
+	w, err := watch.NewWatch(req.AssetID, req.Addr, req.CallbackURL, req.MinConfirmations)
+	if err == nil {
+		err = store.Create(w)
+	}
+
+	if err != nil {
+		c.Logger().Errorf("error creating watch for address: %s for coin: %s, err: %v", req.Addr, req.AssetID, err)
+		return c.JSON(http.StatusInternalServerError, genericResponse{
+			Error: "could not create watch",
+			Code:  ErrorCodeWatchError,
+		})
+	}
+
+	var res WatchResponse
+	res.Data.Watch = w
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// GetWatches lists all registered watches.
+func GetWatches(c echo.Context) error {
+	c.Logger().Print("executing GetWatches handler")
+	store := c.Get("watch_store").(watch.Store)
+
+	watches, err := store.List()
+	if err != nil {
+		c.Logger().Errorf("error listing watches, err: %v", err)
+		return c.JSON(http.StatusInternalServerError, genericResponse{
+			Error: "could not list watches",
+			Code:  ErrorCodeWatchError,
+		})
+	}
+
+	var res WatchesResponse
+	res.Data.Watches = make([]*watch.Watch, len(watches))
+	for i, w := range watches {
+		res.Data.Watches[i] = withoutSecret(w)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetWatch fetches a single watch by its id.
+func GetWatch(c echo.Context) error {
+	c.Logger().Print("executing GetWatch handler")
+	store := c.Get("watch_store").(watch.Store)
+
+	w, err := store.Get(c.Param("id"))
+	if err != nil {
+		return watchError(c, err)
+	}
+
+	var res WatchResponse
+	res.Data.Watch = withoutSecret(w)
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteWatch removes a watch so that no further callbacks are sent.
+func DeleteWatch(c echo.Context) error {
+	c.Logger().Print("executing DeleteWatch handler")
+	store := c.Get("watch_store").(watch.Store)
+
+	if err := store.Delete(c.Param("id")); err != nil {
+		return watchError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, successResponse)
+}
+
+// GetDeadLetters lists the watch events which could not be delivered after all retries.
+func GetDeadLetters(c echo.Context) error {
+	c.Logger().Print("executing GetDeadLetters handler")
+	store := c.Get("watch_store").(watch.Store)
+
+	letters, err := store.DeadLetters()
+	if err != nil {
+		c.Logger().Errorf("error listing dead letters, err: %v", err)
+		return c.JSON(http.StatusInternalServerError, genericResponse{
+			Error: "could not list dead letters",
+			Code:  ErrorCodeWatchError,
+		})
+	}
+
+	var res DeadLettersResponse
+	res.Data.DeadLetters = letters
+	if res.Data.DeadLetters == nil {
+		res.Data.DeadLetters = []watch.DeadLetter{}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func watchError(c echo.Context, err error) error {
+	if err == watch.ErrNotFound {
+		return c.JSON(http.StatusNotFound, genericResponse{
+			Error: fmt.Sprintf("watch: %s was not found", c.Param("id")),
+			Code:  ErrorCodeWatchNotFound,
+		})
+	}
+
+	c.Logger().Errorf("error fetching watch: %s, err: %v", c.Param("id"), err)
+	return c.JSON(http.StatusInternalServerError, genericResponse{
+		Error: "could not fetch watch",
+		Code:  ErrorCodeWatchError,
+	})
+}
+
+func withoutSecret(w *watch.Watch) *watch.Watch {
+	cp := *w
+	cp.Secret = ""
+
+	return &cp
+}
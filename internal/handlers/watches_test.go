@@ -0,0 +1,178 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/internal/watch"
+)
+
+var _ = Describe("Watches", func() {
+	var (
+		e      *echo.Echo
+		ctrl   *gomock.Controller
+		router *mock_transport.MockRouter
+		logger *mock_echo.MockLogger
+		dir    string
+		store  *watch.FileStore
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		logger = mock_echo.NewMockLogger(ctrl)
+		router = mock_transport.NewMockRouter(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+
+		var err error
+		dir, err = ioutil.TempDir("", "watches")
+		Expect(err).ToNot(HaveOccurred())
+
+		store, err = watch.NewFileStore(filepath.Join(dir, "watches.json"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		os.RemoveAll(dir)
+	})
+
+	newContext := func(method, path, body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("coin_router", router)
+		c.Set("watch_store", store)
+
+		return c, rec
+	}
+
+	Describe("CreateWatch", func() {
+		It("Should create a watch and return its secret", func() {
+			c, rec := newContext(http.MethodPost, "/watches", `{
+				"assetId": "btc",
+				"addr": "addr1",
+				"callbackURL": "https://example.com/hook",
+				"minConfirmations": 3
+			}`)
+
+			router.EXPECT().Get(gomock.Eq("btc")).Return(mock_transport.NewMockCoinClient(ctrl), nil)
+
+			Expect(CreateWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			var res WatchResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+			Expect(res.Data.Watch.ID).ToNot(BeEmpty())
+			Expect(res.Data.Watch.Secret).ToNot(BeEmpty())
+			Expect(res.Data.Watch.MinConfirmations).To(Equal(int64(3)))
+
+			stored, err := store.Get(res.Data.Watch.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.CallbackURL).To(Equal("https://example.com/hook"))
+		})
+
+		It("Should import the address if the client supports it", func() {
+			c, rec := newContext(http.MethodPost, "/watches", `{
+				"assetId": "btc",
+				"addr": "addr1",
+				"callbackURL": "https://example.com/hook"
+			}`)
+
+			importer := mock_transport.NewMockAddressImporter(ctrl)
+			router.EXPECT().Get(gomock.Eq("btc")).Return(struct {
+				*mock_transport.MockCoinClient
+				*mock_transport.MockAddressImporter
+			}{mock_transport.NewMockCoinClient(ctrl), importer}, nil)
+			importer.EXPECT().ImportAddress(gomock.Eq("addr1")).Return(nil)
+
+			Expect(CreateWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("Should reject an invalid callback url", func() {
+			c, rec := newContext(http.MethodPost, "/watches", `{
+				"assetId": "btc",
+				"addr": "addr1",
+				"callbackURL": "ftp://example.com"
+			}`)
+
+			Expect(CreateWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "callbackURL must be a valid http or https url",
+				"code": 101
+			}`))
+		})
+	})
+
+	Describe("GetWatch", func() {
+		It("Should return the watch without its secret", func() {
+			w, err := watch.NewWatch("btc", "addr1", "https://example.com/hook", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(store.Create(w)).To(Succeed())
+
+			c, rec := newContext(http.MethodGet, "/watches/"+w.ID, "")
+			c.SetParamNames("id")
+			c.SetParamValues(w.ID)
+
+			Expect(GetWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var res WatchResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+			Expect(res.Data.Watch.ID).To(Equal(w.ID))
+			Expect(res.Data.Watch.Secret).To(BeEmpty())
+		})
+
+		It("Should return not found for a missing watch", func() {
+			c, rec := newContext(http.MethodGet, "/watches/missing", "")
+			c.SetParamNames("id")
+			c.SetParamValues("missing")
+
+			Expect(GetWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(rec.Body.String()).Should(MatchJSON(`{
+				"data": null,
+				"error": "watch: missing was not found",
+				"code": 502
+			}`))
+		})
+	})
+
+	Describe("DeleteWatch", func() {
+		It("Should remove the watch", func() {
+			w, err := watch.NewWatch("btc", "addr1", "https://example.com/hook", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(store.Create(w)).To(Succeed())
+
+			c, rec := newContext(http.MethodDelete, "/watches/"+w.ID, "")
+			c.SetParamNames("id")
+			c.SetParamValues(w.ID)
+
+			Expect(DeleteWatch(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			_, err = store.Get(w.ID)
+			Expect(err).To(Equal(watch.ErrNotFound))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+const (
+	// defaultGapLimit is the number of consecutive unused addresses after which wallets stop deriving addresses.
+	defaultGapLimit = 20
+	maxGapLimit     = 1000
+)
+
+// GetXPubBalance fetches the balance summed across the addresses derived from the extended public key in the url.
+func GetXPubBalance(c echo.Context) error {
+	c.Logger().Print("executing GetXPubBalance handler")
+
+	inspector, gap, errRes := xpubRequest(c)
+	if errRes != nil {
+		return c.JSON(http.StatusBadRequest, errRes)
+	}
+
+	res, err := inspector.GetXPubBalance(c.Param("xpub"), gap)
+	if err != nil {
+		c.Logger().Errorf("error getting balance of xpub: %s for coin: %s, err: %v", c.Param("xpub"), c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not get balance of given xpub",
+			Code:  ErrorCodeXPubError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListXPubAddresses lists the addresses derived from the extended public key in the url.
+func ListXPubAddresses(c echo.Context) error {
+	c.Logger().Print("executing ListXPubAddresses handler")
+
+	inspector, gap, errRes := xpubRequest(c)
+	if errRes != nil {
+		return c.JSON(http.StatusBadRequest, errRes)
+	}
+
+	res, err := inspector.ListXPubAddresses(c.Param("xpub"), gap)
+	if err != nil {
+		c.Logger().Errorf("error deriving addresses of xpub: %s for coin: %s, err: %v", c.Param("xpub"), c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not derive addresses of given xpub",
+			Code:  ErrorCodeXPubError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListXPubTransactions lists the transactions of the addresses derived from the extended public key in the url.
+func ListXPubTransactions(c echo.Context) error {
+	c.Logger().Print("executing ListXPubTransactions handler")
+
+	inspector, gap, errRes := xpubRequest(c)
+	if errRes != nil {
+		return c.JSON(http.StatusBadRequest, errRes)
+	}
+
+	res, err := inspector.ListXPubTransactions(c.Param("xpub"), gap)
+	if err != nil {
+		c.Logger().Errorf("error listing transactions of xpub: %s for coin: %s, err: %v", c.Param("xpub"), c.Param("assetId"), err)
+		return c.JSON(http.StatusBadRequest, genericResponse{
+			Error: "could not list transactions of given xpub",
+			Code:  ErrorCodeXPubError,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// xpubRequest returns the client of the request and the gap limit passed in the query, defaulting to 20.
+func xpubRequest(c echo.Context) (transport.XPubInspector, int, *genericResponse) {
+	gap := defaultGapLimit
+	if v := c.QueryParam("gap"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxGapLimit {
+			return nil, 0, &genericResponse{
+				Error: fmt.Sprintf("gap must be an integer between 1 and %d", maxGapLimit),
+				Code:  ErrorInvalidRequest,
+			}
+		}
+
+		gap = parsed
+	}
+
+	inspector, ok := c.Get("coin_client").(transport.XPubInspector)
+	if !ok {
+		return nil, 0, &genericResponse{
+			Error: fmt.Sprintf("client: %s does not have xpub functionality", c.Param("assetId")),
+			Code:  ErrorCodeXPubError,
+		}
+	}
+
+	return inspector, gap, nil
+}
@@ -0,0 +1,158 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/handlers"
+	mock_echo "github.com/hugorut/coins-oracle/internal/handlers/mocks"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("XPubs", func() {
+	var (
+		e         *echo.Echo
+		ctrl      *gomock.Controller
+		client    *mock_transport.MockCoinClient
+		inspector *mock_transport.MockXPubInspector
+		logger    *mock_echo.MockLogger
+	)
+
+	zpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+		inspector = mock_transport.NewMockXPubInspector(ctrl)
+		logger = mock_echo.NewMockLogger(ctrl)
+
+		logger.EXPECT().Print(gomock.Any()).AnyTimes()
+		e.Logger = logger
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newContext := func(coinClient interface{}, path string) (echo.Context, *httptest.ResponseRecorder) {
+		return newCoinContext(e, coinClient, path, []string{"assetId", "xpub"}, []string{"btc", zpub})
+	}
+
+	xpubClient := func() interface{} {
+		return struct {
+			*mock_transport.MockCoinClient
+			*mock_transport.MockXPubInspector
+		}{client, inspector}
+	}
+
+	Describe("GetXPubBalance", func() {
+		It("Should return the balance of the xpub using the default gap limit", func() {
+			c, rec := newContext(xpubClient(), "/nodes/btc/xpubs/"+zpub+"/balance")
+
+			inspector.EXPECT().GetXPubBalance(zpub, 20).Return(&transport.Balance{
+				Data: transport.BalanceData{
+					Assets: []transport.Asset{
+						{Asset: "BTC", Balance: "0.010000", Confirmed: "0.010000", Unconfirmed: "0.000000"},
+					},
+				},
+			}, nil)
+
+			Expect(GetXPubBalance(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"assets": [
+						{"asset": "BTC", "balance": "0.010000", "confirmed": "0.010000", "unconfirmed": "0.000000"}
+					]
+				}
+			}`))
+		})
+
+		It("Should reject a gap limit out of range", func() {
+			c, rec := newContext(xpubClient(), "/nodes/btc/xpubs/"+zpub+"/balance?gap=0")
+
+			Expect(GetXPubBalance(c)).To(Succeed())
+			expectBadRequest(rec, "gap must be an integer between 1 and 1000", ErrorInvalidRequest)
+		})
+
+		It("Should return an error if the client can't derive addresses", func() {
+			c, rec := newContext(client, "/nodes/eth/xpubs/"+zpub+"/balance")
+
+			Expect(GetXPubBalance(c)).To(Succeed())
+			expectBadRequest(rec, "client: btc does not have xpub functionality", ErrorCodeXPubError)
+		})
+	})
+
+	Describe("ListXPubAddresses", func() {
+		It("Should list the derived addresses using the gap limit passed", func() {
+			c, rec := newContext(xpubClient(), "/nodes/btc/xpubs/"+zpub+"/addresses?gap=1")
+
+			res := &transport.XPubAddressesResp{}
+			res.Data.Script = "p2wpkh"
+			res.Data.Addresses = []transport.XPubAddress{
+				{Address: "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", Path: "m/84'/0'/0'/0/0", Used: true},
+				{Address: "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", Path: "m/84'/0'/0'/0/1", Index: 1},
+				{Address: "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", Path: "m/84'/0'/0'/1/0", Change: true},
+			}
+
+			inspector.EXPECT().ListXPubAddresses(zpub, 1).Return(res, nil)
+
+			Expect(ListXPubAddresses(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{
+				"data": {
+					"script": "p2wpkh",
+					"addresses": [
+						{"address": "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "path": "m/84'/0'/0'/0/0", "change": false, "index": 0, "used": true},
+						{"address": "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", "path": "m/84'/0'/0'/0/1", "change": false, "index": 1, "used": false},
+						{"address": "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", "path": "m/84'/0'/0'/1/0", "change": true, "index": 0, "used": false}
+					]
+				}
+			}`))
+		})
+
+		It("Should return an error if the addresses can't be derived", func() {
+			c, rec := newContext(xpubClient(), "/nodes/btc/xpubs/"+zpub+"/addresses")
+
+			logger.EXPECT().Errorf(gomock.AssignableToTypeOf(""), zpub, "btc", gomock.Any())
+			inspector.EXPECT().ListXPubAddresses(zpub, 20).Return(nil, errors.New("xpub is not for the mainnet network"))
+
+			Expect(ListXPubAddresses(c)).To(Succeed())
+			expectBadRequest(rec, "could not derive addresses of given xpub", ErrorCodeXPubError)
+		})
+	})
+
+	Describe("ListXPubTransactions", func() {
+		It("Should list the transactions of the derived addresses", func() {
+			c, rec := newContext(xpubClient(), "/nodes/btc/xpubs/"+zpub+"/txs")
+
+			res := &transport.TransactionsResp{}
+			res.Data.Transactions = []transport.Transaction{
+				{
+					ID:    "8f2334f4037a945a0101408b5eacf657639d31548d22ef0f627f65eb00f0d36d",
+					To:    "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+					Value: "0.010000",
+				},
+			}
+
+			inspector.EXPECT().ListXPubTransactions(zpub, 20).Return(res, nil)
+
+			Expect(ListXPubTransactions(c)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var body transport.TransactionsResp
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Data.Transactions).To(HaveLen(1))
+			Expect(body.Data.Transactions[0].ID).To(Equal("8f2334f4037a945a0101408b5eacf657639d31548d22ef0f627f65eb00f0d36d"))
+		})
+	})
+})
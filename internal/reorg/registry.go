@@ -0,0 +1,49 @@
+package reorg
+
+import (
+	"strings"
+	"sync"
+)
+
+// Registry holds a Tracker for every asset which has been asked about, creating them on first use
+// so their windows persist between requests.
+type Registry struct {
+	Clients ClientGetter
+
+	mu       sync.Mutex
+	trackers map[string]*Tracker
+}
+
+// NewRegistry returns a Registry creating trackers for the clients.
+func NewRegistry(clients ClientGetter) *Registry {
+	return &Registry{Clients: clients}
+}
+
+// Get returns the Tracker of the asset, ErrNotSupported is returned if its client can't look up blocks.
+func (r *Registry) Get(assetID string) (*Tracker, error) {
+	asset := strings.ToLower(assetID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t, ok := r.trackers[asset]; ok {
+		return t, nil
+	}
+
+	client, err := r.Clients.Get(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := NewTracker(asset, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.trackers == nil {
+		r.trackers = make(map[string]*Tracker)
+	}
+
+	r.trackers[asset] = t
+	return t, nil
+}
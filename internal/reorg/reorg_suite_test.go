@@ -0,0 +1,13 @@
+package reorg_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestReorg(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reorg Suite")
+}
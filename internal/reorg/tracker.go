@@ -0,0 +1,300 @@
+package reorg
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	// DefaultWindow is the number of recent blocks a Tracker keeps to compare against the chain.
+	DefaultWindow = 50
+	// DefaultMaxEvents is the number of recent reorgs a Tracker keeps.
+	DefaultMaxEvents = 50
+	// DefaultSyncInterval is the minimum time between two syncs of a Tracker, syncs within it are skipped.
+	DefaultSyncInterval = 5 * time.Second
+
+	// ErrNotSupported is returned for assets whose client can't look up blocks.
+	ErrNotSupported = errors.New("reorg tracking is not supported by the client")
+)
+
+// ClientGetter returns the CoinClient registered for an asset id.
+type ClientGetter interface {
+	Get(name string) (transport.CoinClient, error)
+}
+
+// Event records a chain reorganisation, i.e. blocks which were on the main chain being replaced.
+type Event struct {
+	AssetID string `json:"assetId"`
+	// ForkHeight is the height of the last block shared by the old and new chains.
+	ForkHeight int64    `json:"forkHeight"`
+	Depth      int      `json:"depth"`
+	OldTip     string   `json:"oldTip"`
+	NewTip     string   `json:"newTip"`
+	Orphaned   []string `json:"orphaned"`
+	// ReorgedTxIDs holds the transactions of the orphaned blocks which are not in the new chain.
+	ReorgedTxIDs []string  `json:"reorgedTxIds,omitempty"`
+	DetectedAt   time.Time `json:"detectedAt"`
+}
+
+type entry struct {
+	hash   string
+	parent string
+	txIDs  []string
+}
+
+// Tracker follows the chain tip of a single asset, keeping a rolling window of recent block hashes.
+// A reorg is detected when a block's parent no longer matches the hash stored for the height below it.
+// Trackers sync lazily, so no reorgs are detected while nobody asks for them. The first sync fills the
+// whole window so reorgs of blocks mined before it are detected too.
+type Tracker struct {
+	AssetID      string
+	Client       transport.CoinClient
+	Fetcher      transport.BlockFetcher
+	Window       int
+	MaxEvents    int
+	SyncInterval time.Duration
+
+	mu       sync.Mutex
+	tip      int64
+	blocks   map[int64]entry
+	events   []Event
+	reorged  map[string]bool
+	lastSync time.Time
+}
+
+// NewTracker returns a Tracker using the defaults, the client must be a BlockFetcher.
+func NewTracker(assetID string, client transport.CoinClient) (*Tracker, error) {
+	fetcher, ok := client.(transport.BlockFetcher)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	return &Tracker{
+		AssetID:      strings.ToLower(assetID),
+		Client:       client,
+		Fetcher:      fetcher,
+		Window:       DefaultWindow,
+		MaxEvents:    DefaultMaxEvents,
+		SyncInterval: DefaultSyncInterval,
+	}, nil
+}
+
+// Sync fetches the blocks from the current tip back to the last block the tracker knows about,
+// recording a reorg if any of the stored blocks were replaced.
+func (t *Tracker) Sync() error {
+	return t.SyncContext(context.Background())
+}
+
+// SyncContext is Sync ending with the context. A sync which the context ends leaves the tracker as it was.
+func (t *Tracker) SyncContext(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.lastSync.IsZero() && time.Since(t.lastSync) < t.SyncInterval {
+		return nil
+	}
+
+	var info *transport.CoinState
+	err := call(ctx, func() (err error) {
+		info, err = t.Client.GetInfo()
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "error getting chain tip")
+	}
+
+	tip := int64(info.Data.BlockHeight)
+	window := t.window()
+
+	// fetched holds the blocks in descending height order, from tip down to lowest.
+	var fetched []entry
+	lowest := tip
+
+	for h := tip; h >= 0 && len(fetched) < window; h-- {
+		var res *transport.BlockResp
+		err := call(ctx, func() (err error) {
+			res, err = t.Fetcher.GetBlockByHeight(h)
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error getting block at height: %d", h)
+		}
+
+		b := res.Data.Block
+		fetched = append(fetched, entry{hash: b.Hash, parent: b.Parent, txIDs: b.TxIDs})
+		lowest = h
+
+		// a tracker without blocks fills its whole window.
+		if len(t.blocks) == 0 {
+			continue
+		}
+
+		// keep walking until the block links to one the tracker already knows.
+		if h-1 > t.tip {
+			continue
+		}
+
+		prev, ok := t.blocks[h-1]
+		if !ok || prev.hash == b.Parent {
+			break
+		}
+	}
+
+	// the window can't be compared if the chain moved further than it since the last sync.
+	if lowest-1 > t.tip {
+		t.blocks = nil
+	}
+
+	if t.blocks == nil {
+		t.blocks = make(map[int64]entry)
+	}
+
+	if t.reorged == nil {
+		t.reorged = make(map[string]bool)
+	}
+
+	t.record(tip, lowest, fetched)
+
+	for h := range t.blocks {
+		if h > tip || h <= tip-int64(window) {
+			delete(t.blocks, h)
+		}
+	}
+
+	for i, e := range fetched {
+		t.blocks[tip-int64(i)] = e
+
+		// a reorged transaction which is mined again is back on the main chain.
+		for _, id := range e.txIDs {
+			delete(t.reorged, id)
+		}
+	}
+
+	t.tip = tip
+	t.lastSync = time.Now()
+
+	return nil
+}
+
+// record compares the fetched blocks with the stored ones from the lowest height up, recording an event
+// for any stored blocks which are no longer on the main chain.
+func (t *Tracker) record(tip, lowest int64, fetched []entry) {
+	var heights []int64
+	for h := range t.blocks {
+		if h >= lowest {
+			heights = append(heights, h)
+		}
+	}
+
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	included := make(map[string]bool)
+	for _, e := range fetched {
+		for _, id := range e.txIDs {
+			included[id] = true
+		}
+	}
+
+	var orphaned, txIDs []string
+	for _, h := range heights {
+		old := t.blocks[h]
+		if h <= tip && fetched[tip-h].hash == old.hash {
+			continue
+		}
+
+		orphaned = append(orphaned, old.hash)
+		for _, id := range old.txIDs {
+			if !included[id] {
+				txIDs = append(txIDs, id)
+			}
+		}
+	}
+
+	if len(orphaned) == 0 {
+		return
+	}
+
+	t.events = append(t.events, Event{
+		AssetID:      t.AssetID,
+		ForkHeight:   lowest - 1,
+		Depth:        len(orphaned),
+		OldTip:       t.blocks[t.tip].hash,
+		NewTip:       fetched[0].hash,
+		Orphaned:     orphaned,
+		ReorgedTxIDs: txIDs,
+		DetectedAt:   time.Now().UTC(),
+	})
+
+	for _, id := range txIDs {
+		t.reorged[id] = true
+	}
+
+	max := t.MaxEvents
+	if max <= 0 {
+		max = DefaultMaxEvents
+	}
+
+	for len(t.events) > max {
+		for _, id := range t.events[0].ReorgedTxIDs {
+			delete(t.reorged, id)
+		}
+
+		t.events = t.events[1:]
+	}
+}
+
+// Events returns the recent reorgs, most recent first.
+func (t *Tracker) Events() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events := make([]Event, len(t.events))
+	for i, e := range t.events {
+		events[len(events)-1-i] = e
+	}
+
+	return events
+}
+
+// IsReorged returns whether the transaction was in an orphaned block and has not been mined again since.
+func (t *Tracker) IsReorged(txID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.reorged[txID]
+}
+
+// call runs fn unless the context is done. Clients can't cancel a call, so it is raced against the context
+// and its result dropped when the context ends first.
+func call(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+func (t *Tracker) window() int {
+	if t.Window <= 0 {
+		return DefaultWindow
+	}
+
+	return t.Window
+}
@@ -0,0 +1,287 @@
+package reorg_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/reorg"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// chain is a CoinClient and BlockFetcher serving an in memory chain which the tests can reorganise.
+type chain struct {
+	*mock_transport.MockCoinClient
+	blocks []transport.Block
+	// fetches counts the blocks looked up by height.
+	fetches int
+}
+
+// extend adds count blocks to the chain, tagging their hashes and tx ids so forks can be told apart.
+func (c *chain) extend(count int, tag string) {
+	for i := 0; i < count; i++ {
+		height := int64(len(c.blocks))
+		parent := ""
+		if height > 0 {
+			parent = c.blocks[height-1].Hash
+		}
+
+		hash := fmt.Sprintf("%s-%d", tag, height)
+		c.blocks = append(c.blocks, transport.Block{
+			Height: height,
+			Hash:   hash,
+			Parent: parent,
+			TxIDs:  []string{"tx-" + hash},
+		})
+	}
+}
+
+// fork removes the top depth blocks so the chain can be extended with another tag.
+func (c *chain) fork(depth int) {
+	c.blocks = c.blocks[:len(c.blocks)-depth]
+}
+
+func (c *chain) GetInfo() (*transport.CoinState, error) {
+	tip := c.blocks[len(c.blocks)-1]
+	return &transport.CoinState{
+		Data: transport.CoinData{Chain: "main", BlockHeight: int(tip.Height), CurrentBlock: tip.Hash},
+	}, nil
+}
+
+func (c *chain) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	c.fetches++
+	if height >= int64(len(c.blocks)) {
+		return nil, fmt.Errorf("no block at height: %d", height)
+	}
+
+	res := &transport.BlockResp{}
+	res.Data.Block = c.blocks[height]
+
+	return res, nil
+}
+
+func (c *chain) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	return nil, transport.ErrBlockHashNotSupported
+}
+
+var _ = Describe("Tracker", func() {
+	var (
+		c       *chain
+		tracker *Tracker
+	)
+
+	BeforeEach(func() {
+		c = &chain{}
+		c.extend(10, "a")
+
+		var err error
+		tracker, err = NewTracker("TEST", c)
+		Expect(err).ToNot(HaveOccurred())
+
+		tracker.SyncInterval = 0
+	})
+
+	It("Should fill the window on the first sync and only fetch the new blocks after", func() {
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(c.fetches).To(Equal(10))
+
+		c.extend(3, "a")
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(c.fetches).To(Equal(13))
+
+		Expect(tracker.Events()).To(BeEmpty())
+	})
+
+	It("Should detect a reorg of blocks mined before the first sync", func() {
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(3)
+		c.extend(4, "b")
+		Expect(tracker.Sync()).To(Succeed())
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].ForkHeight).To(Equal(int64(6)))
+		Expect(events[0].Orphaned).To(Equal([]string{"a-7", "a-8", "a-9"}))
+		Expect(tracker.IsReorged("tx-a-7")).To(BeTrue())
+	})
+
+	It("Should leave the tracker as it was when the context ends the sync", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Expect(tracker.SyncContext(ctx)).To(MatchError(ContainSubstring(context.Canceled.Error())))
+		Expect(c.fetches).To(Equal(0))
+
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(c.fetches).To(Equal(10))
+	})
+
+	It("Should record a reorg when a block's parent no longer matches", func() {
+		c.extend(3, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(2, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(2)
+		c.extend(3, "b")
+		Expect(tracker.Sync()).To(Succeed())
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(1))
+
+		e := events[0]
+		Expect(e.AssetID).To(Equal("test"))
+		Expect(e.ForkHeight).To(Equal(int64(12)))
+		Expect(e.Depth).To(Equal(2))
+		Expect(e.OldTip).To(Equal("a-14"))
+		Expect(e.NewTip).To(Equal("b-15"))
+		Expect(e.Orphaned).To(Equal([]string{"a-13", "a-14"}))
+		Expect(e.ReorgedTxIDs).To(Equal([]string{"tx-a-13", "tx-a-14"}))
+
+		Expect(tracker.IsReorged("tx-a-13")).To(BeTrue())
+		Expect(tracker.IsReorged("tx-a-12")).To(BeFalse())
+	})
+
+	It("Should detect a replaced tip at the same height", func() {
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(1)
+		c.extend(1, "b")
+		Expect(tracker.Sync()).To(Succeed())
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Orphaned).To(Equal([]string{"a-9"}))
+		Expect(events[0].ForkHeight).To(Equal(int64(8)))
+	})
+
+	It("Should detect a reorg to a shorter chain", func() {
+		c.extend(2, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(2)
+		c.extend(1, "b")
+		Expect(tracker.Sync()).To(Succeed())
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Orphaned).To(Equal([]string{"a-11", "a-12"}))
+		Expect(events[0].NewTip).To(Equal("b-11"))
+	})
+
+	It("Should not report transactions mined again in the new chain", func() {
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(1)
+		c.extend(1, "b")
+		c.blocks[11].TxIDs = append(c.blocks[11].TxIDs, "tx-a-11")
+		Expect(tracker.Sync()).To(Succeed())
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].ReorgedTxIDs).To(BeEmpty())
+		Expect(tracker.IsReorged("tx-a-11")).To(BeFalse())
+	})
+
+	It("Should clear a reorged transaction once it is mined again", func() {
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.fork(1)
+		c.extend(1, "b")
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(tracker.IsReorged("tx-a-11")).To(BeTrue())
+
+		c.extend(1, "b")
+		c.blocks[12].TxIDs = append(c.blocks[12].TxIDs, "tx-a-11")
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(tracker.IsReorged("tx-a-11")).To(BeFalse())
+	})
+
+	It("Should restart the window when the chain moved further than it", func() {
+		tracker.Window = 3
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(10, "a")
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(c.fetches).To(Equal(6))
+		Expect(tracker.Events()).To(BeEmpty())
+	})
+
+	It("Should keep only the most recent events, most recent first", func() {
+		tracker.MaxEvents = 2
+
+		Expect(tracker.Sync()).To(Succeed())
+
+		for _, tag := range []string{"b", "c", "d"} {
+			c.fork(1)
+			c.extend(1, tag)
+			Expect(tracker.Sync()).To(Succeed())
+		}
+
+		events := tracker.Events()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].NewTip).To(Equal("d-9"))
+		Expect(events[1].NewTip).To(Equal("c-9"))
+		Expect(tracker.IsReorged("tx-a-9")).To(BeFalse())
+		Expect(tracker.IsReorged("tx-b-9")).To(BeTrue())
+	})
+
+	It("Should skip syncs within the sync interval", func() {
+		tracker.SyncInterval = DefaultSyncInterval
+
+		Expect(tracker.Sync()).To(Succeed())
+
+		c.extend(1, "a")
+		Expect(tracker.Sync()).To(Succeed())
+		Expect(c.fetches).To(Equal(10))
+	})
+})
+
+var _ = Describe("Registry", func() {
+	It("Should return the same tracker for an asset", func() {
+		c := &chain{}
+		c.extend(1, "a")
+
+		registry := NewRegistry(clients{"test": c})
+
+		first, err := registry.Get("TEST")
+		Expect(err).ToNot(HaveOccurred())
+
+		second, err := registry.Get("test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+	})
+
+	It("Should not support clients which can't look up blocks", func() {
+		registry := NewRegistry(clients{"test": &mock_transport.MockCoinClient{}})
+
+		_, err := registry.Get("test")
+		Expect(err).To(Equal(ErrNotSupported))
+	})
+})
+
+type clients map[string]transport.CoinClient
+
+func (c clients) Get(name string) (transport.CoinClient, error) {
+	if client, ok := c[name]; ok {
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("could not find client named: %s", name)
+}
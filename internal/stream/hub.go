@@ -0,0 +1,199 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	// DefaultPollInterval is how often GetInfo is polled for clients without a native block subscription.
+	DefaultPollInterval = 10 * time.Second
+)
+
+// ClientGetter returns the CoinClient registered for an asset id.
+type ClientGetter interface {
+	Get(name string) (transport.CoinClient, error)
+}
+
+// BlockEvent is sent to subscribers every time the chain tip of an asset changes.
+type BlockEvent struct {
+	AssetID string `json:"assetId"`
+	transport.CoinData
+}
+
+// Hub fans out chain tip changes for each asset to any number of subscribers.
+// Each asset has a single upstream feed which is started with the first subscriber and
+// stopped when the last one leaves, so many subscribers cost one upstream subscription or poll.
+type Hub struct {
+	Clients      ClientGetter
+	PollInterval time.Duration
+	Logger       *log.Logger
+
+	mu    sync.Mutex
+	feeds map[string]*feed
+}
+
+type feed struct {
+	subs   map[chan BlockEvent]struct{}
+	last   *BlockEvent
+	cancel context.CancelFunc
+}
+
+// NewHub returns a Hub using the DefaultPollInterval.
+func NewHub(clients ClientGetter) *Hub {
+	return &Hub{
+		Clients:      clients,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Subscribe returns a channel which receives a BlockEvent every time the chain tip of the asset changes,
+// starting with the current tip if it is already known. Slow subscribers only receive the latest tip.
+// The returned func must be called to unsubscribe, after which the channel is closed.
+func (h *Hub) Subscribe(assetID string) (<-chan BlockEvent, func(), error) {
+	client, err := h.Clients.Get(assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	asset := strings.ToLower(assetID)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.feeds == nil {
+		h.feeds = make(map[string]*feed)
+	}
+
+	f, ok := h.feeds[asset]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		f = &feed{subs: make(map[chan BlockEvent]struct{}), cancel: cancel}
+		h.feeds[asset] = f
+
+		go h.run(ctx, asset, f, client)
+	}
+
+	ch := make(chan BlockEvent, 1)
+	f.subs[ch] = struct{}{}
+
+	if f.last != nil {
+		ch <- *f.last
+	}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(f.subs, ch)
+			close(ch)
+
+			if len(f.subs) == 0 {
+				f.cancel()
+				if h.feeds[asset] == f {
+					delete(h.feeds, asset)
+				}
+			}
+		})
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// run publishes the chain tip of the asset until the context is cancelled. A native subscription is used if the
+// client supports one, falling back to polling GetInfo if it isn't configured or fails.
+func (h *Hub) run(ctx context.Context, asset string, f *feed, client transport.CoinClient) {
+	h.poll(asset, f, client)
+
+	if sub, ok := client.(transport.BlockSubscriber); ok {
+		blocks := make(chan transport.CoinData)
+		errc := make(chan error, 1)
+
+		go func() {
+			errc <- sub.SubscribeBlocks(ctx, blocks)
+		}()
+
+	native:
+		for {
+			select {
+			case data := <-blocks:
+				h.publish(asset, f, data)
+			case err := <-errc:
+				if ctx.Err() != nil {
+					return
+				}
+
+				if err != transport.ErrSubscriptionNotSupported {
+					h.logf("block subscription for asset: %s failed, falling back to polling, err: %s", asset, err)
+				}
+
+				break native
+			}
+		}
+	}
+
+	interval := h.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.poll(asset, f, client)
+		}
+	}
+}
+
+func (h *Hub) poll(asset string, f *feed, client transport.CoinClient) {
+	info, err := client.GetInfo()
+	if err != nil {
+		h.logf("error getting info for asset: %s, err: %s", asset, err)
+		return
+	}
+
+	h.publish(asset, f, info.Data)
+}
+
+// publish sends the tip to every subscriber of the feed if it has changed, replacing any
+// event a subscriber hasn't read yet.
+func (h *Hub) publish(asset string, f *feed, data transport.CoinData) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if f.last != nil && f.last.CoinData == data {
+		return
+	}
+
+	e := BlockEvent{AssetID: asset, CoinData: data}
+	f.last = &e
+
+	for ch := range f.subs {
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- e
+	}
+}
+
+func (h *Hub) logf(format string, v ...interface{}) {
+	if h.Logger == nil {
+		return
+	}
+
+	h.Logger.Printf(format, v...)
+}
@@ -0,0 +1,139 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/stream"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+type clients map[string]transport.CoinClient
+
+func (c clients) Get(name string) (transport.CoinClient, error) {
+	if client, ok := c[name]; ok {
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("could not find client named: %s", name)
+}
+
+// subscriberClient is a CoinClient with a native block subscription fed by the test.
+type subscriberClient struct {
+	*mock_transport.MockCoinClient
+	blocks chan transport.CoinData
+	err    error
+}
+
+func (s subscriberClient) SubscribeBlocks(ctx context.Context, blocks chan<- transport.CoinData) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	for {
+		select {
+		case b := <-s.blocks:
+			blocks <- b
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func info(height int, hash string) *transport.CoinState {
+	return &transport.CoinState{
+		Data: transport.CoinData{Chain: "main", BlockHeight: height, CurrentBlock: hash},
+	}
+}
+
+var _ = Describe("Hub", func() {
+	var (
+		ctrl *gomock.Controller
+		hub  *Hub
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Should poll GetInfo once for all subscribers and only send tip changes", func() {
+		client := mock_transport.NewMockCoinClient(ctrl)
+		hub = &Hub{Clients: clients{"test": client}, PollInterval: 50 * time.Millisecond}
+
+		client.EXPECT().GetInfo().Return(info(1, "a"), nil).Times(2)
+		client.EXPECT().GetInfo().Return(info(2, "b"), nil).AnyTimes()
+
+		first, unsubscribeFirst, err := hub.Subscribe("test")
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(first).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(1, "a").Data})))
+
+		second, unsubscribeSecond, err := hub.Subscribe("test")
+		Expect(err).ToNot(HaveOccurred())
+
+		// a new subscriber is sent the current tip straight away.
+		Expect(second).To(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(1, "a").Data})))
+
+		Eventually(first).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(2, "b").Data})))
+		Eventually(second).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(2, "b").Data})))
+		Consistently(first, 50*time.Millisecond).ShouldNot(Receive())
+
+		unsubscribeFirst()
+		unsubscribeSecond()
+		Expect(first).To(BeClosed())
+	})
+
+	It("Should use a native block subscription when the client supports it", func() {
+		client := subscriberClient{
+			MockCoinClient: mock_transport.NewMockCoinClient(ctrl),
+			blocks:         make(chan transport.CoinData),
+		}
+		hub = &Hub{Clients: clients{"test": client}, PollInterval: time.Hour}
+
+		client.MockCoinClient.EXPECT().GetInfo().Return(info(1, "a"), nil)
+
+		events, unsubscribe, err := hub.Subscribe("test")
+		Expect(err).ToNot(HaveOccurred())
+		defer unsubscribe()
+
+		Eventually(events).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(1, "a").Data})))
+
+		client.blocks <- info(2, "b").Data
+		Eventually(events).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(2, "b").Data})))
+	})
+
+	It("Should fall back to polling when the native subscription is not supported", func() {
+		client := subscriberClient{
+			MockCoinClient: mock_transport.NewMockCoinClient(ctrl),
+			err:            transport.ErrSubscriptionNotSupported,
+		}
+		hub = &Hub{Clients: clients{"test": client}, PollInterval: 10 * time.Millisecond}
+
+		client.MockCoinClient.EXPECT().GetInfo().Return(info(1, "a"), nil)
+		client.MockCoinClient.EXPECT().GetInfo().Return(info(2, "b"), nil).AnyTimes()
+
+		events, unsubscribe, err := hub.Subscribe("test")
+		Expect(err).ToNot(HaveOccurred())
+		defer unsubscribe()
+
+		// only the latest tip is kept for a subscriber so the first block may already be replaced.
+		Eventually(events).Should(Receive(Equal(BlockEvent{AssetID: "test", CoinData: info(2, "b").Data})))
+	})
+
+	It("Should error for an unknown asset", func() {
+		hub = NewHub(clients{})
+
+		_, _, err := hub.Subscribe("missing")
+		Expect(err).To(MatchError("could not find client named: missing"))
+	})
+})
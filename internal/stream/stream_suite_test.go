@@ -0,0 +1,13 @@
+package stream_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestStream(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Stream Suite")
+}
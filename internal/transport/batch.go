@@ -0,0 +1,207 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	// BatchConcurrency is the maximum number of upstream requests a single batch lookup will run at once.
+	BatchConcurrency = 10
+)
+
+// BalanceLookup identifies a single address balance within a batch request.
+type BalanceLookup struct {
+	AssetID string `json:"assetId"`
+	Addr    string `json:"addr"`
+}
+
+// BalanceResult holds the outcome of a single BalanceLookup, either the balance or the error encountered.
+type BalanceResult struct {
+	AssetID string                 `json:"assetId"`
+	Addr    string                 `json:"addr"`
+	Balance *transport.BalanceData `json:"balance,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// TransactionLookup identifies a single transaction within a batch request.
+type TransactionLookup struct {
+	AssetID string `json:"assetId"`
+	Hash    string `json:"hash"`
+}
+
+// TransactionResult holds the outcome of a single TransactionLookup, either the transaction or the error encountered.
+type TransactionResult struct {
+	AssetID     string                 `json:"assetId"`
+	Hash        string                 `json:"hash"`
+	Transaction *transport.Transaction `json:"transaction,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// GetBalances fetches the balances for every lookup, returning results in the same order as the lookups.
+// Lookups are grouped by asset so that clients which support batching are called once,
+// all other lookups are fanned out to the client with at most BatchConcurrency requests in flight.
+func (r CoinResolver) GetBalances(lookups []BalanceLookup) []BalanceResult {
+	results := make([]BalanceResult, len(lookups))
+	groups := make(map[string][]int)
+
+	for i, l := range lookups {
+		results[i] = BalanceResult{AssetID: l.AssetID, Addr: l.Addr}
+
+		asset := strings.ToLower(l.AssetID)
+		groups[asset] = append(groups[asset], i)
+	}
+
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, BatchConcurrency)
+
+	for asset, indexes := range groups {
+		client, err := r.Get(asset)
+		if err != nil {
+			for _, i := range indexes {
+				results[i].Error = err.Error()
+			}
+
+			continue
+		}
+
+		if batcher, ok := client.(transport.BatchBalanceGetter); ok {
+			wg.Add(1)
+			go func(batcher transport.BatchBalanceGetter, indexes []int) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				addrs := make([]string, len(indexes))
+				for k, i := range indexes {
+					addrs[k] = lookups[i].Addr
+				}
+
+				balances, err := batcher.GetBalances(addrs)
+				for _, i := range indexes {
+					if err != nil {
+						results[i].Error = err.Error()
+						continue
+					}
+
+					b, ok := balances[lookups[i].Addr]
+					if !ok || b == nil {
+						results[i].Error = fmt.Sprintf("no balance returned for address: %s", lookups[i].Addr)
+						continue
+					}
+
+					results[i].Balance = &b.Data
+				}
+			}(batcher, indexes)
+
+			continue
+		}
+
+		for _, i := range indexes {
+			wg.Add(1)
+			go func(client transport.CoinClient, i int) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				b, err := client.GetBalance(lookups[i].Addr)
+				if err != nil {
+					results[i].Error = err.Error()
+					return
+				}
+
+				results[i].Balance = &b.Data
+			}(client, i)
+		}
+	}
+
+	wg.Wait()
+	return results
+}
+
+// GetTransactions fetches the transactions for every lookup, returning results in the same order as the lookups.
+// It follows the same grouping and concurrency rules as GetBalances.
+func (r CoinResolver) GetTransactions(lookups []TransactionLookup) []TransactionResult {
+	results := make([]TransactionResult, len(lookups))
+	groups := make(map[string][]int)
+
+	for i, l := range lookups {
+		results[i] = TransactionResult{AssetID: l.AssetID, Hash: l.Hash}
+
+		asset := strings.ToLower(l.AssetID)
+		groups[asset] = append(groups[asset], i)
+	}
+
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, BatchConcurrency)
+
+	for asset, indexes := range groups {
+		client, err := r.Get(asset)
+		if err != nil {
+			for _, i := range indexes {
+				results[i].Error = err.Error()
+			}
+
+			continue
+		}
+
+		if batcher, ok := client.(transport.BatchTransactionGetter); ok {
+			wg.Add(1)
+			go func(batcher transport.BatchTransactionGetter, indexes []int) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				hashes := make([]string, len(indexes))
+				for k, i := range indexes {
+					hashes[k] = lookups[i].Hash
+				}
+
+				txs, err := batcher.GetTransactionsByHash(hashes)
+				for _, i := range indexes {
+					if err != nil {
+						results[i].Error = err.Error()
+						continue
+					}
+
+					tx, ok := txs[lookups[i].Hash]
+					if !ok || tx == nil {
+						results[i].Error = fmt.Sprintf("no transaction returned for hash: %s", lookups[i].Hash)
+						continue
+					}
+
+					results[i].Transaction = &tx.Data.Transaction
+				}
+			}(batcher, indexes)
+
+			continue
+		}
+
+		for _, i := range indexes {
+			wg.Add(1)
+			go func(client transport.CoinClient, i int) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				tx, err := client.GetTransactionByHash(lookups[i].Hash)
+				if err != nil {
+					results[i].Error = err.Error()
+					return
+				}
+
+				results[i].Transaction = &tx.Data.Transaction
+			}(client, i)
+		}
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,154 @@
+package transport_test
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// batchClient is a CoinClient which also supports native batch lookups.
+type batchClient struct {
+	*mock_transport.MockCoinClient
+	*mock_transport.MockBatchBalanceGetter
+	*mock_transport.MockBatchTransactionGetter
+}
+
+func balanceOf(asset, value string) *transport.Balance {
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{
+				{Asset: asset, Balance: value},
+			},
+		},
+	}
+}
+
+var _ = Describe("Batch", func() {
+	var (
+		resolver *CoinResolver
+		ctrl     *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		resolver = &CoinResolver{
+			C:  make(map[string]transport.CoinClient),
+			Mu: &sync.Mutex{},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#GetBalances", func() {
+		It("Should fan out lookups to clients and keep the lookup order", func() {
+			client := mock_transport.NewMockCoinClient(ctrl)
+			resolver.Register("test", client)
+
+			client.EXPECT().GetBalance("addr1").Return(balanceOf("TEST", "1"), nil)
+			client.EXPECT().GetBalance("addr2").Return(nil, errors.New("node down"))
+
+			results := resolver.GetBalances([]BalanceLookup{
+				{AssetID: "TEST", Addr: "addr1"},
+				{AssetID: "missing", Addr: "addr3"},
+				{AssetID: "test", Addr: "addr2"},
+			})
+
+			Expect(results).To(HaveLen(3))
+			Expect(results[0]).To(MatchAllFields(Fields{
+				"AssetID": Equal("TEST"),
+				"Addr":    Equal("addr1"),
+				"Balance": PointTo(Equal(balanceOf("TEST", "1").Data)),
+				"Error":   BeEmpty(),
+			}))
+			Expect(results[1]).To(MatchAllFields(Fields{
+				"AssetID": Equal("missing"),
+				"Addr":    Equal("addr3"),
+				"Balance": BeNil(),
+				"Error":   ContainSubstring("could not find client"),
+			}))
+			Expect(results[2]).To(MatchAllFields(Fields{
+				"AssetID": Equal("test"),
+				"Addr":    Equal("addr2"),
+				"Balance": BeNil(),
+				"Error":   Equal("node down"),
+			}))
+		})
+
+		It("Should use a single native batch call when the client supports it", func() {
+			client := batchClient{
+				MockCoinClient:         mock_transport.NewMockCoinClient(ctrl),
+				MockBatchBalanceGetter: mock_transport.NewMockBatchBalanceGetter(ctrl),
+			}
+			resolver.Register("test", client)
+
+			client.MockBatchBalanceGetter.EXPECT().GetBalances([]string{"addr1", "addr2"}).Return(map[string]*transport.Balance{
+				"addr1": balanceOf("TEST", "1"),
+			}, nil)
+
+			results := resolver.GetBalances([]BalanceLookup{
+				{AssetID: "test", Addr: "addr1"},
+				{AssetID: "test", Addr: "addr2"},
+			})
+
+			Expect(results[0].Balance).To(PointTo(Equal(balanceOf("TEST", "1").Data)))
+			Expect(results[0].Error).To(BeEmpty())
+			Expect(results[1].Balance).To(BeNil())
+			Expect(results[1].Error).To(Equal("no balance returned for address: addr2"))
+		})
+	})
+
+	Describe("#GetTransactions", func() {
+		It("Should fan out lookups to clients and report errors per lookup", func() {
+			client := mock_transport.NewMockCoinClient(ctrl)
+			resolver.Register("test", client)
+
+			tx := &transport.TransactionResp{}
+			tx.Data.Transaction = transport.Transaction{ID: "hash1"}
+
+			client.EXPECT().GetTransactionByHash("hash1").Return(tx, nil)
+			client.EXPECT().GetTransactionByHash("hash2").Return(nil, errors.New("not found"))
+
+			results := resolver.GetTransactions([]TransactionLookup{
+				{AssetID: "test", Hash: "hash1"},
+				{AssetID: "test", Hash: "hash2"},
+			})
+
+			Expect(results[0].Transaction).To(PointTo(Equal(tx.Data.Transaction)))
+			Expect(results[1].Transaction).To(BeNil())
+			Expect(results[1].Error).To(Equal("not found"))
+		})
+
+		It("Should use a single native batch call when the client supports it", func() {
+			client := batchClient{
+				MockCoinClient:             mock_transport.NewMockCoinClient(ctrl),
+				MockBatchTransactionGetter: mock_transport.NewMockBatchTransactionGetter(ctrl),
+			}
+			resolver.Register("test", client)
+
+			tx := &transport.TransactionResp{}
+			tx.Data.Transaction = transport.Transaction{ID: "hash1"}
+
+			client.MockBatchTransactionGetter.EXPECT().GetTransactionsByHash([]string{"hash1"}).Return(map[string]*transport.TransactionResp{
+				"hash1": tx,
+			}, nil)
+
+			results := resolver.GetTransactions([]TransactionLookup{
+				{AssetID: "test", Hash: "hash1"},
+			})
+
+			Expect(results[0].Transaction).To(PointTo(Equal(tx.Data.Transaction)))
+			Expect(results[0].Error).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// ExpandBlockTransactions populates the block's Transactions by fetching each of its transaction ids through the client,
+// keeping the order of the ids. Transactions the client can't normalise, e.g. coinbase transactions, are left out
+// and returned keyed by id with the error encountered.
+func ExpandBlockTransactions(client transport.CoinClient, block *transport.Block) map[string]error {
+	txs := make([]*transport.Transaction, len(block.TxIDs))
+	errs := make(map[string]error)
+
+	if batcher, ok := client.(transport.BatchTransactionGetter); ok {
+		res, err := batcher.GetTransactionsByHash(block.TxIDs)
+		for i, id := range block.TxIDs {
+			if err != nil {
+				errs[id] = err
+				continue
+			}
+
+			tx, ok := res[id]
+			if !ok || tx == nil {
+				errs[id] = fmt.Errorf("no transaction returned for hash: %s", id)
+				continue
+			}
+
+			txs[i] = &tx.Data.Transaction
+		}
+	} else {
+		mu := sync.Mutex{}
+		wg := sync.WaitGroup{}
+		sem := make(chan struct{}, BatchConcurrency)
+
+		for i, id := range block.TxIDs {
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				tx, err := client.GetTransactionByHash(id)
+				if err != nil {
+					mu.Lock()
+					errs[id] = err
+					mu.Unlock()
+					return
+				}
+
+				txs[i] = &tx.Data.Transaction
+			}(i, id)
+		}
+
+		wg.Wait()
+	}
+
+	block.Transactions = make([]transport.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx != nil {
+			block.Transactions = append(block.Transactions, *tx)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,64 @@
+package transport_test
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("ExpandBlockTransactions", func() {
+	var (
+		ctrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	txResp := func(id string) *transport.TransactionResp {
+		res := &transport.TransactionResp{}
+		res.Data.Transaction = transport.Transaction{ID: id}
+
+		return res
+	}
+
+	It("Should fetch every transaction in the order of the ids", func() {
+		client := mock_transport.NewMockCoinClient(ctrl)
+		block := &transport.Block{TxIDs: []string{"tx1", "tx2", "tx3"}}
+
+		client.EXPECT().GetTransactionByHash("tx1").Return(txResp("tx1"), nil)
+		client.EXPECT().GetTransactionByHash("tx2").Return(nil, errors.New("coinbase"))
+		client.EXPECT().GetTransactionByHash("tx3").Return(txResp("tx3"), nil)
+
+		errs := ExpandBlockTransactions(client, block)
+
+		Expect(errs).To(HaveLen(1))
+		Expect(errs).To(HaveKey("tx2"))
+		Expect(block.Transactions).To(Equal([]transport.Transaction{{ID: "tx1"}, {ID: "tx3"}}))
+	})
+
+	It("Should use a single call for clients which batch transactions", func() {
+		batcher := mock_transport.NewMockBatchTransactionGetter(ctrl)
+		client := batchClient{mock_transport.NewMockCoinClient(ctrl), nil, batcher}
+		block := &transport.Block{TxIDs: []string{"tx1", "tx2"}}
+
+		batcher.EXPECT().GetTransactionsByHash([]string{"tx1", "tx2"}).Return(map[string]*transport.TransactionResp{
+			"tx2": txResp("tx2"),
+		}, nil)
+
+		errs := ExpandBlockTransactions(client, block)
+
+		Expect(errs).To(HaveKey("tx1"))
+		Expect(block.Transactions).To(Equal([]transport.Transaction{{ID: "tx2"}}))
+	})
+})
@@ -1,12 +1,15 @@
 package transport
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/url"
 	"os"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -22,9 +25,29 @@ import (
 var (
 	BitcoinAssetID = "BTC"
 
-	ErrorAlreadyImported = errors.New("address already imported")
+	ErrorInvalidAddress  = errors.New("address is not valid for the network")
+
+	// btcFeeRates are the lower bounds, in satoshis per virtual byte, of the mempool fee histogram buckets.
+	btcFeeRates = []int64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
 )
 
+// btcMempoolInfo represents the result of a getmempoolinfo call.
+type btcMempoolInfo struct {
+	Size  int   `json:"size"`
+	Bytes int64 `json:"bytes"`
+}
+
+// btcMempoolEntry represents a transaction in the result of a verbose getrawmempool call.
+// Older nodes and forks without segwit only report the size and the fee, newer nodes the vsize and the fees.
+type btcMempoolEntry struct {
+	Size  int64   `json:"size"`
+	VSize int64   `json:"vsize"`
+	Fee   float64 `json:"fee"`
+	Fees  struct {
+		Base float64 `json:"base"`
+	} `json:"fees"`
+}
+
 // btcStdLogger implements the BTCLogger interface and directs output to stdout.
 type btcStdLogger struct {
 	log   *log.Logger
@@ -65,40 +88,96 @@ func (b *btcStdLogger) SetLevel(level btclog.Level) { b.level = level }
 // but simply returns the base addr string.
 type btcStrAddr struct{ addr string }
 
-func (b btcStrAddr) String() string                 { return b.addr }
-func (b btcStrAddr) EncodeAddress() string          { return b.addr }
-func (b btcStrAddr) ScriptAddress() []byte          { return []byte(b.addr) }
-func (b btcStrAddr) IsForNet(*chaincfg.Params) bool { return true }
+func (b btcStrAddr) String() string        { return b.addr }
+func (b btcStrAddr) EncodeAddress() string { return b.addr }
+func (b btcStrAddr) ScriptAddress() []byte { return []byte(b.addr) }
+
+// IsForNet decodes the address to check it belongs to the network. Segwit addresses are decoded separately
+// as btcutil can neither decode taproot addresses nor those of networks it doesn't have registered.
+func (b btcStrAddr) IsForNet(net *chaincfg.Params) bool {
+	if net.Bech32HRPSegwit != "" && strings.HasPrefix(strings.ToLower(b.addr), net.Bech32HRPSegwit+"1") {
+		return validSegwitAddr(net.Bech32HRPSegwit, b.addr)
+	}
+
+	addr, err := btcutil.DecodeAddress(b.addr, net)
+	return err == nil && addr.IsForNet(net)
+}
 
 // BitcoinClient is the Bitcoin implementation of the CoinClient
 type BitcoinClient struct {
 	AssetID string
 	Client  *rpcclient.Client
+	// Network holds the address prefixes and RPC differences of the chain, bitcoin's are used if nil.
+	Network *BTCNetwork
+	// ZMQURL is the node's zmqpubhashblock address, e.g. tcp://127.0.0.1:28332. If empty blocks can't be subscribed to.
+	ZMQURL string
+	// ImportClient sends the imports, which the node doesn't respond to until its rescan is done. rpcclient sends
+	// requests one at a time so they're kept off Client, which is used if ImportClient is nil.
+	ImportClient *rpcclient.Client
+	// Imports persists the import jobs, which can't be started without it.
+	Imports ImportStore
 }
 
 // NewBitcoinClient returns a new client using os variables.
 func NewBitcoinClient() (*BitcoinClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("BITCOIN_URL"))
+	return newBitcoinClient(MainNet)
+}
+
+func newBitcoinClient(network string) (*BitcoinClient, error) {
+	return newBTCFamilyClient(BitcoinAssetID, "BITCOIN", network, BitcoinNetwork)
+}
+
+// newBTCFamilyClient returns a client of the asset on the network, main being the params of the asset
+// on mainnet. Its node is configured by the os variables prefixed with the coin and network.
+func newBTCFamilyClient(assetID, coin, network string, main *BTCNetwork) (*BitcoinClient, error) {
+	net, err := btcNetwork(assetID, network, main)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BitcoinClient{
-		AssetID: BitcoinAssetID,
-		Client:  btcClient,
-	}, nil
+	env := networkEnv(coin, network)
+
+	btcClient, err := newBTCClient(env)
+	if err != nil {
+		return nil, err
+	}
+
+	importClient, err := newBTCClient(env)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &BitcoinClient{
+		AssetID:      assetID,
+		Client:       btcClient,
+		Network:      net,
+		ZMQURL:       os.Getenv(env + "_ZMQ_URL"),
+		ImportClient: importClient,
+	}
+
+	// the jobs of every asset are kept in the same store, which needs to be shared by the instances of the api.
+	if path := os.Getenv("IMPORT_STORE_PATH"); path != "" {
+		client.Imports, err = NewFileImportStore(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
 }
 
-func newBTCClient(addr string) (*rpcclient.Client, error) {
-	u, err := url.Parse(addr)
+// newBTCClient returns a client for the node at <COIN>_URL. The credentials are read from <COIN>_RPC_USER
+// and <COIN>_RPC_PASS, falling back to RPC_USER and RPC_PASS which are shared by all the nodes.
+func newBTCClient(coin string) (*rpcclient.Client, error) {
+	u, err := url.Parse(getNodeURL(coin + "_URL"))
 	if err != nil {
 		return nil, err
 	}
 
 	connCfg := &rpcclient.ConnConfig{
 		Host:         u.Host,
-		User:         os.Getenv("RPC_USER"),
-		Pass:         os.Getenv("RPC_PASS"),
+		User:         btcEnv(coin, "RPC_USER"),
+		Pass:         btcEnv(coin, "RPC_PASS"),
 		HTTPPostMode: true,
 		DisableTLS:   true,
 	}
@@ -107,13 +186,51 @@ func newBTCClient(addr string) (*rpcclient.Client, error) {
 	return rpcclient.New(connCfg, nil)
 }
 
+func btcEnv(coin, key string) string {
+	if v := os.Getenv(coin + "_" + key); v != "" {
+		return v
+	}
+
+	return os.Getenv(key)
+}
+
+// network returns the params of the client's chain.
+func (b BitcoinClient) network() *BTCNetwork {
+	if b.Network == nil {
+		return BitcoinNetwork
+	}
+
+	return b.Network
+}
+
+// ValidateAddress returns ErrorInvalidAddress if the address doesn't belong to the client's chain,
+// e.g. a bitcoin address given to the litecoin client.
+func (b BitcoinClient) ValidateAddress(addr string) error {
+	if !b.network().IsForNet(addr) {
+		return errors.Wrapf(ErrorInvalidAddress, "%s is not a %s address", addr, b.AssetID)
+	}
+
+	return nil
+}
+
 // GetInfo attempts to get standardised coin info from multiple rpc calls.
 func (b BitcoinClient) GetInfo() (*transport.CoinState, error) {
-	res, err := b.Client.GetBlockChainInfo()
+	if b.network().LegacyInfo {
+		return b.getLegacyInfo()
+	}
+
+	// GetBlockChainInfo asks the node for its version with getnetworkinfo first, which is a wasted round trip
+	// as the result is decoded the same way by every version.
+	raw, err := b.Client.RawRequest("getblockchaininfo", nil)
 	if err != nil {
 		return nil, err
 	}
 
+	var res btcjson.GetBlockChainInfoResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, errors.Wrap(err, "error decoding blockchain info")
+	}
+
 	return &transport.CoinState{
 		Data: transport.CoinData{
 			Chain:        res.Chain,
@@ -123,139 +240,562 @@ func (b BitcoinClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
-// GetBalance returns the balance of the address.
-func (b BitcoinClient) GetBalance(addr string) (*transport.Balance, error) {
-	unspent, err := b.Client.ListUnspentMinMaxAddresses(1, 9999999, []btcutil.Address{btcStrAddr{addr: addr}})
+// btcLegacyInfo represents the result of a getinfo call.
+type btcLegacyInfo struct {
+	Blocks  int  `json:"blocks"`
+	Testnet bool `json:"testnet"`
+}
+
+// getLegacyInfo reads the height from getinfo, which doesn't report the chain tip's hash so it's fetched separately.
+func (b BitcoinClient) getLegacyInfo() (*transport.CoinState, error) {
+	raw, err := b.Client.RawRequest("getinfo", nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "error listing unspent for given addr")
+		return nil, errors.Wrap(err, "error getting info")
 	}
 
-	var total float64
-	for _, value := range unspent {
-		total += value.Amount
+	var info btcLegacyInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, errors.Wrap(err, "error decoding info")
 	}
 
-	return &transport.Balance{
-		Data: transport.BalanceData{
-			Assets: []transport.Asset{
-				{
-					Asset:   b.AssetID,
-					Balance: fmt.Sprintf("%f", total),
-				},
-			},
+	hash, err := b.Client.GetBestBlockHash()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting best block hash")
+	}
+
+	chain := "main"
+	if info.Testnet {
+		chain = "test"
+	}
+
+	return &transport.CoinState{
+		Data: transport.CoinData{
+			Chain:        chain,
+			BlockHeight:  info.Blocks,
+			CurrentBlock: hash.String(),
 		},
 	}, nil
 }
 
-// GetTransactionByHash returns the transaction stored at the given hash.
-func (b BitcoinClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
-	raw, err := b.getTransaction(hash)
+// SubscribeBlocks listens for hashblock notifications published by the node's zmq interface,
+// sending the chain info for every new block.
+func (b BitcoinClient) SubscribeBlocks(ctx context.Context, blocks chan<- transport.CoinData) error {
+	if b.ZMQURL == "" {
+		return transport.ErrSubscriptionNotSupported
+	}
+
+	sub, err := dialZMQ(ctx, b.ZMQURL, "hashblock")
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting transaction from initial input hash: %s", hash)
+		return err
+	}
+	defer sub.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// closing the connection unblocks ReadMessage once the subscription is cancelled.
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		msg, err := sub.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return errors.Wrap(err, "error reading zmq notification")
+		}
+
+		if len(msg) < 2 || string(msg[0]) != "hashblock" {
+			continue
+		}
+
+		info, err := b.GetInfo()
+		if err != nil {
+			return errors.Wrap(err, "error getting info for new block")
+		}
+
+		select {
+		case blocks <- info.Data:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
+}
 
-	sendingTx := raw.Vin[0]
+// GetBlockByHeight returns the block at the given height of the main chain.
+func (b BitcoinClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	hash, err := b.Client.GetBlockHash(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting block hash at height: %d", height)
+	}
+
+	return b.getBlock(hash.String())
+}
 
-	from, err := b.getTransaction(sendingTx.Txid)
+// GetBlockByHash returns the block with the given hash.
+func (b BitcoinClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	if _, err := chainhash.NewHashFromStr(hash); err != nil {
+		return nil, errors.Wrap(err, "error generating a chain hash from given hash")
+	}
+
+	return b.getBlock(hash)
+}
+
+// getBlock calls getblock with a verbosity of 1 directly, as the params rpcclient sends for getblock differ between versions.
+func (b BitcoinClient) getBlock(hash string) (*transport.BlockResp, error) {
+	params := []json.RawMessage{
+		json.RawMessage(fmt.Sprintf("%q", hash)),
+		json.RawMessage("1"),
+	}
+
+	raw, err := b.Client.RawRequest("getblock", params)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting transaction for input transaction: %s", sendingTx.Txid)
+		return nil, errors.Wrapf(err, "error getting block: %s", hash)
 	}
 
-	var value float64
-	for _, v := range raw.Vout {
-		value += v.Value
+	var block btcjson.GetBlockVerboseResult
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, errors.Wrap(err, "error decoding block")
 	}
 
-	confirmationsValue := transport.NewInt64(int64(raw.Confirmations))
+	res := &transport.BlockResp{}
+	res.Data.Block = transport.Block{
+		Height:    block.Height,
+		Hash:      block.Hash,
+		Parent:    block.PreviousHash,
+		Timestamp: block.Time,
+		TxCount:   len(block.Tx),
+		TxIDs:     block.Tx,
+	}
 
-	return &transport.TransactionResp{
-		Data: struct {
-			Transaction transport.Transaction `json:"transaction"`
-		}{
-			Transaction: transport.Transaction{
-				From:  from.Vout[sendingTx.Vout].ScriptPubKey.Addresses[0],
-				To:    raw.Vout[0].ScriptPubKey.Addresses[0],
-				ID:    raw.Hash,
-				Value: fmt.Sprintf("%f", value),
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: *confirmationsValue >= *transport.ConfirmThresholdValue,
-					Value:     confirmationsValue,
+	return res, nil
+}
+
+// GetMempool returns the size of the mempool along with a histogram of the fee rates paid by its transactions.
+func (b BitcoinClient) GetMempool() (*transport.MempoolResp, error) {
+	raw, err := b.Client.RawRequest("getmempoolinfo", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting mempool info")
+	}
+
+	var info btcMempoolInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, errors.Wrap(err, "error decoding mempool info")
+	}
+
+	raw, err = b.Client.RawRequest("getrawmempool", []json.RawMessage{json.RawMessage("true")})
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting raw mempool")
+	}
+
+	var entries map[string]btcMempoolEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, errors.Wrap(err, "error decoding raw mempool")
+	}
+
+	histogram := make([]transport.FeeBucket, len(btcFeeRates))
+	for key, rate := range btcFeeRates {
+		histogram[key].FeeRate = rate
+	}
+
+	for _, entry := range entries {
+		size := entry.VSize
+		if size == 0 {
+			size = entry.Size
+		}
+
+		fee := entry.Fees.Base
+		if fee == 0 {
+			fee = entry.Fee
+		}
+
+		if size == 0 {
+			continue
+		}
+
+		rate := int64(math.Round(fee*btcutil.SatoshiPerBitcoin)) / size
+
+		// buckets are ordered so the last bound which the rate reaches is the one it belongs to.
+		bucket := 0
+		for key, bound := range btcFeeRates {
+			if rate >= bound {
+				bucket = key
+			}
+		}
+
+		histogram[bucket].Count++
+		histogram[bucket].Bytes += size
+	}
+
+	res := &transport.MempoolResp{}
+	res.Data.Mempool = transport.Mempool{
+		Size:         info.Size,
+		Bytes:        info.Bytes,
+		FeeHistogram: histogram,
+	}
+
+	return res, nil
+}
+
+// GetBalance returns the balance of the address with at least one confirmation, along with the
+// value of its unconfirmed outputs.
+func (b BitcoinClient) GetBalance(addr string) (*transport.Balance, error) {
+	return b.GetConfirmedBalance(addr, 1)
+}
+
+// GetConfirmedBalance returns the balance of the address counting the outputs with at least minConf
+// confirmations, the value of the remaining outputs is returned as unconfirmed.
+func (b BitcoinClient) GetConfirmedBalance(addr string, minConf int) (*transport.Balance, error) {
+	balances, err := b.getBalances([]string{addr}, minConf)
+	if err != nil {
+		return nil, err
+	}
+
+	return balances[addr], nil
+}
+
+// GetBalances returns the balances of all the addresses using a single listunspent call.
+func (b BitcoinClient) GetBalances(addrs []string) (map[string]*transport.Balance, error) {
+	return b.getBalances(addrs, 1)
+}
+
+func (b BitcoinClient) getBalances(addrs []string, minConf int) (map[string]*transport.Balance, error) {
+	totals, err := b.unspentTotals(addrs, minConf)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*transport.Balance, len(totals))
+	for addr, total := range totals {
+		balances[addr] = &transport.Balance{
+			Data: transport.BalanceData{
+				Assets: []transport.Asset{
+					{
+						Asset:       b.AssetID,
+						Balance:     fmt.Sprintf("%f", total.confirmed.ToBTC()),
+						Confirmed:   fmt.Sprintf("%f", total.confirmed.ToBTC()),
+						Unconfirmed: fmt.Sprintf("%f", total.unconfirmed.ToBTC()),
+					},
 				},
 			},
-		},
-	}, nil
+		}
+	}
+
+	return balances, nil
 }
 
-func (b BitcoinClient) getTransaction(hash string) (*btcjson.TxRawResult, error) {
-	chainH, err := chainhash.NewHashFromStr(hash)
+// btcUnspentTotal sums the unspent outputs of an address.
+type btcUnspentTotal struct {
+	confirmed   btcutil.Amount
+	unconfirmed btcutil.Amount
+}
+
+// unspentTotals sums the unspent outputs of each of the addresses using a single listunspent call, those with
+// fewer than minConf confirmations being summed as unconfirmed.
+func (b BitcoinClient) unspentTotals(addrs []string, minConf int) (map[string]*btcUnspentTotal, error) {
+	btcAddrs := make([]btcutil.Address, len(addrs))
+	totals := make(map[string]*btcUnspentTotal, len(addrs))
+
+	for key, addr := range addrs {
+		if err := b.ValidateAddress(addr); err != nil {
+			return nil, err
+		}
+
+		btcAddrs[key] = btcStrAddr{addr: addr}
+		totals[addr] = &btcUnspentTotal{}
+	}
+
+	// outputs are listed from the mempool up so the ones below the depth can be reported as unconfirmed.
+	unspent, err := b.Client.ListUnspentMinMaxAddresses(0, 9999999, btcAddrs)
 	if err != nil {
-		return nil, errors.Wrap(err, "error generating a chain hash from given hash")
+		return nil, errors.Wrap(err, "error listing unspent for given addrs")
 	}
 
-	msg, err := b.Client.GetRawTransaction(chainH)
+	for _, value := range unspent {
+		total, ok := totals[value.Address]
+		if !ok {
+			continue
+		}
+
+		// NewAmount rounds to the nearest satoshi, avoiding float truncation errors.
+		amount, err := btcutil.NewAmount(value.Amount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting amount of output: %s:%d", value.TxID, value.Vout)
+		}
+
+		if value.Confirmations >= int64(minConf) {
+			total.confirmed += amount
+			continue
+		}
+
+		total.unconfirmed += amount
+	}
+
+	return totals, nil
+}
+
+// ListTransactions returns the transactions received by the address, including those still in the mempool and
+// those whose outputs have since been spent. The address must have been imported for the node to know about them.
+func (b BitcoinClient) ListTransactions(addr string) (*transport.TransactionsResp, error) {
+	res, _, err := b.ListTransactionsSince(addr, "", 1)
+	return res, err
+}
+
+// ListTransactionsSince returns the transactions received by the address in the blocks after the cursor block hash
+// and in the mempool, using a single listsinceblock call. The cursor returned is confirmations - 1 blocks below the
+// tip, so transactions keep being listed until they have the given confirmations.
+func (b BitcoinClient) ListTransactionsSince(addr, cursor string, confirmations int64) (*transport.TransactionsResp, string, error) {
+	if err := b.ValidateAddress(addr); err != nil {
+		return nil, "", err
+	}
+
+	if confirmations < 1 {
+		confirmations = 1
+	}
+
+	since, err := b.listSinceBlock(cursor, confirmations)
 	if err != nil {
-		return nil, errors.Wrap(err, "error getting raw transaction")
+		return nil, "", err
 	}
 
-	buf := bytes.NewBuffer([]byte{})
-	err = msg.MsgTx().Serialize(buf)
+	txs, err := receivedEntries(addr, since.Transactions)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not serialize transaction msg")
+		return nil, "", err
 	}
 
-	raw, err := b.Client.DecodeRawTransaction(buf.Bytes())
+	res := &transport.TransactionsResp{}
+	res.Data.Transactions = txs
+
+	return res, since.LastBlock, nil
+}
+
+// listSinceBlock lists the entries of the wallet's transactions, watch-only addresses included, mined since the
+// block or still in the mempool. An empty block lists every transaction of the wallet.
+func (b BitcoinClient) listSinceBlock(block string, confirmations int64) (*btcjson.ListSinceBlockResult, error) {
+	raw, err := b.Client.RawRequest("listsinceblock", []json.RawMessage{
+		json.RawMessage(strconv.Quote(block)),
+		json.RawMessage(strconv.FormatInt(confirmations, 10)),
+		json.RawMessage("true"),
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error decoding transaction")
+		return nil, errors.Wrapf(err, "error listing transactions since block: %s", block)
 	}
 
-	return raw, nil
+	var since btcjson.ListSinceBlockResult
+	if err := json.Unmarshal(raw, &since); err != nil {
+		return nil, errors.Wrap(err, "error decoding transactions since block")
+	}
+
+	return &since, nil
 }
 
-// ImportAddress imports the given address. This will reindex the chain, which may block connections, so use wisely.
-func (b BitcoinClient) ImportAddress(addr string) error {
-	err := b.importAddress(addr)
-	if err == ErrorAlreadyImported || err == nil {
-		return nil
+// receivedEntries sums what the address received in each transaction of the wallet entries, which list every output
+// separately. Transactions which conflict with the chain, e.g. double spent ones, are left out.
+func receivedEntries(addr string, entries []btcjson.ListTransactionsResult) ([]transport.Transaction, error) {
+	txs := []transport.Transaction{}
+	totals := map[string]btcutil.Amount{}
+
+	for _, e := range entries {
+		if e.Address != addr || e.Confirmations < 0 || !btcReceiveCategory(e.Category) {
+			continue
+		}
+
+		// NewAmount rounds to the nearest satoshi, avoiding float truncation errors.
+		amount, err := btcutil.NewAmount(e.Amount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting amount of transaction: %s", e.TxID)
+		}
+
+		if _, ok := totals[e.TxID]; !ok {
+			txs = append(txs, transport.Transaction{
+				ID:            e.TxID,
+				To:            addr,
+				Confirmations: btcConfirmations(e.Confirmations),
+			})
+		}
+
+		totals[e.TxID] += amount
 	}
 
-	err = b.importAddress(addr)
-	if err == ErrorAlreadyImported || err == nil {
-		return nil
+	for key := range txs {
+		txs[key].Value = fmt.Sprintf("%f", totals[txs[key].ID].ToBTC())
 	}
 
-	return err
+	return txs, nil
 }
 
-// importAddress wraps the btcClients method in with a timeout since this method.
-// This is done as the internals don't have a default timeout and this importAddress
-// normally blocks for a considerable with the first call. It only returns a response
-// after successful import, which can take hours.
-//
-// On second call it will return a address already imported error. So the solution is to
-// cancel the first request and try again to make sure the import is kicked off.
-func (b BitcoinClient) importAddress(addr string) error {
-	errChn := make(chan error)
-	go func() {
-		errChn <- b.Client.ImportAddress(addr)
-		close(errChn)
-	}()
+// btcReceiveCategory reports whether a wallet entry of the category credits the address.
+func btcReceiveCategory(category string) bool {
+	return category == "receive" || category == "generate" || category == "immature"
+}
 
-	for {
-		select {
-		case err := <-errChn:
-			btcErr, ok := err.(*btcjson.RPCError)
-			if !ok {
-				return err
-			}
+// listReceived returns the ids of the transactions received by every address in the node's wallet, watch-only
+// addresses included. The address filter of listreceivedbyaddress is left out as the forks' nodes don't have it.
+func (b BitcoinClient) listReceived() (map[string][]string, error) {
+	raw, err := b.Client.RawRequest("listreceivedbyaddress", []json.RawMessage{
+		json.RawMessage("0"),
+		json.RawMessage("false"),
+		json.RawMessage("true"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing received by address")
+	}
 
-			if btcErr.Code == btcjson.ErrRPCWallet {
-				return ErrorAlreadyImported
-			}
+	var received []btcReceived
+	if err := json.Unmarshal(raw, &received); err != nil {
+		return nil, errors.Wrap(err, "error decoding received by address")
+	}
+
+	txids := make(map[string][]string, len(received))
+	for _, r := range received {
+		txids[r.Address] = r.TxIDs
+	}
+
+	return txids, nil
+}
 
-			return err
-		case <-time.After(transport.DefaultClientTimeout):
-			return errors.New("timeout")
+// ListUTXOs returns the unspent outputs of the address with at least minConf confirmations.
+// The address must have been imported for the node to know about its outputs.
+func (b BitcoinClient) ListUTXOs(addr string, minConf int) (*transport.UTXOsResp, error) {
+	if err := b.ValidateAddress(addr); err != nil {
+		return nil, err
+	}
+
+	unspent, err := b.Client.ListUnspentMinMaxAddresses(minConf, 9999999, []btcutil.Address{btcStrAddr{addr: addr}})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing unspent for given addr")
+	}
+
+	res := &transport.UTXOsResp{}
+	res.Data.UTXOs = make([]transport.UTXO, len(unspent))
+
+	for key, value := range unspent {
+		// NewAmount rounds to the nearest satoshi, avoiding float truncation errors.
+		amount, err := btcutil.NewAmount(value.Amount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting amount of output: %s:%d", value.TxID, value.Vout)
+		}
+
+		res.Data.UTXOs[key] = transport.UTXO{
+			TxID:          value.TxID,
+			Vout:          value.Vout,
+			Amount:        int64(amount),
+			Script:        value.ScriptPubKey,
+			Confirmations: value.Confirmations,
+		}
+	}
+
+	return res, nil
+}
+
+// GetTransactionByHash returns the transaction stored at the given hash with its outputs. The sender is the
+// address paid by the previous output spent by the first input, coinbase transactions having none, and the
+// receiver the first output which pays an address. The data of the first OP_RETURN output is returned as the memo.
+func (b BitcoinClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
+	raw, err := b.getTransaction(hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting transaction from initial input hash: %s", hash)
+	}
+
+	if len(raw.Vin) == 0 {
+		return nil, fmt.Errorf("transaction: %s has no inputs", hash)
+	}
+
+	tx := transport.Transaction{
+		ID:            raw.Txid,
+		Confirmations: btcConfirmations(raw.Confirmations),
+		Coinbase:      raw.Vin[0].Coinbase != "",
+	}
+
+	var value float64
+	for _, v := range raw.Vout {
+		value += v.Value
+
+		out := btcOutput(v)
+		if out.Type == "nulldata" && tx.Memo == "" {
+			tx.Memo = btcMemo(v.ScriptPubKey.Hex)
+		}
+
+		if out.Address != "" && tx.To == "" {
+			tx.To = out.Address
+		}
+
+		tx.Outputs = append(tx.Outputs, out)
+	}
+
+	tx.Value = fmt.Sprintf("%f", value)
+
+	if !tx.Coinbase {
+		sendingTx := raw.Vin[0]
+
+		from, err := b.getTransaction(sendingTx.Txid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting transaction for input transaction: %s", sendingTx.Txid)
 		}
+
+		if int(sendingTx.Vout) >= len(from.Vout) {
+			return nil, fmt.Errorf("input transaction: %s has no output: %d", sendingTx.Txid, sendingTx.Vout)
+		}
+
+		tx.From = btcOutput(from.Vout[sendingTx.Vout]).Address
+	}
+
+	return &transport.TransactionResp{
+		Data: struct {
+			Transaction transport.Transaction `json:"transaction"`
+		}{
+			Transaction: tx,
+		},
+	}, nil
+}
+
+// btcConfirmations returns the confirmations for a transaction, where no confirmations means it is still in the mempool.
+func btcConfirmations(confirmations int64) transport.Confirmations {
+	if confirmations == 0 {
+		return transport.PendingConfirmations()
+	}
+
+	return transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: confirmations >= *transport.ConfirmThresholdValue,
+		Value:     transport.NewInt64(confirmations),
 	}
 }
+
+// getTransaction calls getrawtransaction with verbose output directly, decoding the outputs of every script type.
+func (b BitcoinClient) getTransaction(hash string) (*btcRawTransaction, error) {
+	if _, err := chainhash.NewHashFromStr(hash); err != nil {
+		return nil, errors.Wrap(err, "error generating a chain hash from given hash")
+	}
+
+	params := []json.RawMessage{
+		json.RawMessage(fmt.Sprintf("%q", hash)),
+		json.RawMessage("1"),
+	}
+
+	// the verbose result includes the confirmations, which are omitted while the transaction is in the mempool.
+	res, err := b.Client.RawRequest("getrawtransaction", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting raw transaction")
+	}
+
+	var raw btcRawTransaction
+	if err := json.Unmarshal(res, &raw); err != nil {
+		return nil, errors.Wrap(err, "error decoding raw transaction")
+	}
+
+	return &raw, nil
+}
+
+// ImportAddress imports the given address in the background, watching it from now on without rescanning the chain.
+func (b BitcoinClient) ImportAddress(addr string) error {
+	_, err := b.startImport(transport.ImportReq{Addr: addr})
+	return err
+}
@@ -1,15 +1,21 @@
 package transport_test
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/hugorut/coins-oracle/pkg/transport"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/btcsuite/btcd/rpcclient"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
@@ -73,8 +79,8 @@ var _ = Describe("BitcoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent.json", addr)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent_mempool.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265, addr)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -85,8 +91,12 @@ var _ = Describe("BitcoinClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("BTC"),
-							"Balance": Equal("0.004623"),
+							"Asset":       Equal("BTC"),
+							"Balance":     Equal("0.004623"),
+							"Confirmed":   Equal("0.004623"),
+							"Unconfirmed": Equal("0.000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -135,8 +145,8 @@ var _ = Describe("BitcoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 1, txID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction.json")),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose.json", txID, txID, 46413)),
 				ResponseCode: http.StatusOK,
 			}).Then(test.ExpectedCall{
 				Path:   "/",
@@ -145,18 +155,71 @@ var _ = Describe("BitcoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction.json", txID, txID)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 2, senderID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_sender.json", senderID, senderID)),
 				ResponseCode: http.StatusOK,
-			}).Then(test.ExpectedCall{
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx).To(PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"Transaction": MatchAllFields(Fields{
+						"ID":    Equal(txID),
+						"From":  Equal("1NULeRwToV4pm394qnmoK6F2Gyu6DGUwq4"),
+						"To":    Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"),
+						"Value": Equal("25.098818"),
+						"Confirmations": MatchAllFields(Fields{
+							"Threshold": PointTo(Equal(int64(5))),
+							"Confirmed": BeTrue(),
+							"Value":     PointTo(Equal(int64(46413))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
+						}),
+						"Transfers": BeEmpty(),
+						"Reverted":  BeFalse(),
+						"Fees":      BeNil(),
+						"Memo":      BeEmpty(),
+						"MemoType":  BeEmpty(),
+						"Coinbase":  BeFalse(),
+						"Outputs": ConsistOf(
+							MatchAllFields(Fields{
+								"Index":    Equal(0),
+								"Address":  Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("25"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+							MatchAllFields(Fields{
+								"Index":    Equal(1),
+								"Address":  Equal("1LJB8MNgNwhZ7KJPjUadSdv4eboreNoybS"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("0.09881791"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+						),
+						"DestinationTag": BeNil(),
+					}),
+				}),
+			})))
+		})
+
+		It("Should decode segwit, taproot, multisig and OP_RETURN outputs", func() {
+			txID := "4ce18f49ba153a51bcda9bb80d7f978e3de6e81b5fc326f00465464530c052f4"
+			senderID := "c88f369cfe24e402eafd97c7318183ed780baa3b92a3459fc161eb9472ea532b"
+
+			mockServer.Expect(test.ExpectedCall{
 				Path:   "/",
 				Method: "POST",
 				Headers: map[string]string{
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 3, senderID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_sender.json")),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_segwit.json", txID, txID, senderID, 3)),
 				ResponseCode: http.StatusOK,
 			}).Then(test.ExpectedCall{
 				Path:   "/",
@@ -165,63 +228,911 @@ var _ = Describe("BitcoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction_sender.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction_sender.json", senderID, senderID)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 2, senderID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_sender.json", senderID, senderID)),
 				ResponseCode: http.StatusOK,
 			})
 
 			tx, err := client.GetTransactionByHash(txID)
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(tx).To(PointTo(MatchAllFields(Fields{
-				"Data": MatchAllFields(Fields{
-					"Transaction": MatchAllFields(Fields{
-						"ID":    Equal(txID),
-						"From":  Equal("1NULeRwToV4pm394qnmoK6F2Gyu6DGUwq4"),
-						"To":    Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"),
-						"Value": Equal("25.098818"),
-						"Confirmations": MatchAllFields(Fields{
-							"Threshold": PointTo(Equal(int64(5))),
-							"Confirmed": BeTrue(),
-							"Value":     PointTo(Equal(int64(46413))),
-						}),
+			t := tx.Data.Transaction
+			Expect(t.From).To(Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"))
+			Expect(t.To).To(Equal("bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"))
+			Expect(t.Value).To(Equal("0.750100"))
+			Expect(t.Memo).To(Equal("order #12345"))
+			Expect(t.Coinbase).To(BeFalse())
+			Expect(t.Outputs).To(Equal([]transport.Output{
+				{Index: 0, Type: "nulldata", Value: "0"},
+				{Index: 1, Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Type: "p2wpkh", Value: "0.5"},
+				{Index: 2, Address: "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", Type: "p2tr", Value: "0.25"},
+				{
+					Index:    3,
+					Type:     "multisig",
+					Value:    "0.0001",
+					Required: 1,
+					PubKeys: []string{
+						"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+						"02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+					},
+				},
+			}))
+		})
+
+		It("Should mark coinbase transactions without looking up a sender", func() {
+			txID := "a3b3cd3c5e1a0a2e3a2d7bb0fa3f0a9d1a46df6f8b0b5b4a6f0d1bb8fbd7c1e2"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_coinbase.json", txID, txID, 101)),
+				ResponseCode: http.StatusOK,
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			t := tx.Data.Transaction
+			Expect(t.Coinbase).To(BeTrue())
+			Expect(t.From).To(BeEmpty())
+			Expect(t.To).To(Equal("bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"))
+			Expect(t.Value).To(Equal("6.250000"))
+			Expect(t.Memo).To(Equal("aa21a9ede2f61c3f71d1defd3fa999dfa36953755c690689799962b48bebd836974e8cf9"))
+			Expect(t.Outputs).To(HaveLen(2))
+		})
+	})
+
+	Describe("#ImportAddress", func() {
+		It("Should start an import of the address without rescanning the chain", func() {
+			addr := "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"
+			imported := make(chan struct{})
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body: MustLoad(fb.LoadFixture("bitcoin/req/importdescriptors.json", 1, "addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf", `"now"`)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					defer close(imported)
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors.json"))))
+				},
+			})
+
+			err := client.(transport.AddressImporter).ImportAddress(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(imported).Should(BeClosed())
+		})
+	})
+
+	Describe("#StartImport", func() {
+		var (
+			dir      string
+			store    *FileImportStore
+			node     *httptest.Server
+			importer *BitcoinClient
+
+			// scanning is the progress of the rescan the node's wallet reports, negative when it isn't rescanning.
+			scanning float64
+			// held is the descriptor the node's wallet holds.
+			held string
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "imports")
+			Expect(err).ToNot(HaveOccurred())
+
+			store, err = NewFileImportStore(filepath.Join(dir, "imports.json"))
+			Expect(err).ToNot(HaveOccurred())
+
+			scanning, held = 0.25, "addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"
+
+			// node answers the wallet and block lookups however often they're made, the imports
+			// being sent to the mock server on a client of their own.
+			node = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+
+				var req struct {
+					Method string `json:"method"`
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+
+				switch req.Method {
+				case "getblockhash":
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/getblockhash.json", "00000000000000000008b35ae0ed6d5e9e7e4a1bf7fcd4f8e5cf38a1c3a1c8a5"))))
+				case "getblock":
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/getblock.json", "00000000000000000008b35ae0ed6d5e9e7e4a1bf7fcd4f8e5cf38a1c3a1c8a5", 1))))
+				case "getwalletinfo":
+					if scanning < 0 {
+						_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/getwalletinfo_idle.json"))))
+						return
+					}
+
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/getwalletinfo.json", scanning))))
+				case "listdescriptors":
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/listdescriptors.json", held))))
+				default:
+					Fail("unexpected call to " + req.Method)
+				}
+			}))
+
+			u, err := url.Parse(node.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			nodeClient, err := rpcclient.New(&rpcclient.ConnConfig{
+				Host:         u.Host,
+				User:         rpcUser,
+				Pass:         rpcPass,
+				HTTPPostMode: true,
+				DisableTLS:   true,
+			}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			importer = &BitcoinClient{
+				AssetID:      BitcoinAssetID,
+				Client:       nodeClient,
+				ImportClient: client.(*BitcoinClient).Client,
+				Imports:      store,
+			}
+		})
+
+		AfterEach(func() {
+			node.Close()
+			os.RemoveAll(dir)
+		})
+
+		It("Should return the job straight away, reporting the rescan's progress until the wallet is done rescanning", func() {
+			started, release := make(chan struct{}), make(chan struct{})
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Body:   MustLoad(fb.LoadFixture("bitcoin/req/importdescriptors.json", 1, "addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf", 1568729415)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					close(started)
+					<-release
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors.json"))))
+				},
+			})
+
+			res, err := importer.StartImport(transport.ImportReq{
+				Addr:        "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+				BirthHeight: 595303,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(started).Should(BeClosed())
+
+			job := res.Data.Import
+			Expect(job.ID).ToNot(BeEmpty())
+			Expect(job.AssetID).To(Equal("BTC"))
+			Expect(job.Descriptors).To(ConsistOf("addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"))
+			Expect(job.Timestamp).To(Equal(int64(1568729415)))
+			Expect(job.Status).To(Equal(transport.ImportScanning))
+
+			res, err = importer.GetImport(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportScanning))
+			Expect(res.Data.Import.Progress).To(Equal(0.25))
+
+			close(release)
+			scanning = -1
+
+			res, err = importer.GetImport(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportComplete))
+			Expect(res.Data.Import.Progress).To(Equal(1.0))
+		})
+
+		It("Should complete the job started by another instance once the wallet is no longer rescanning", func() {
+			Expect(store.Save(transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     BitcoinAssetID,
+				Descriptors: []string{"addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"},
+				Status:      transport.ImportScanning,
+				StartedAt:   time.Now().UTC(),
+			})).To(Succeed())
+
+			shared, err := NewFileImportStore(store.Path)
+			Expect(err).ToNot(HaveOccurred())
+
+			other := &BitcoinClient{AssetID: BitcoinAssetID, Client: importer.Client, Imports: shared}
+
+			res, err := other.GetImport("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportScanning))
+			Expect(res.Data.Import.Progress).To(Equal(0.25))
+
+			scanning = -1
+
+			res, err = other.GetImport("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportComplete))
+			Expect(res.Data.Import.Progress).To(Equal(1.0))
+
+			job, err := store.Get("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(transport.ImportComplete))
+		})
+
+		It("Should not report the progress of another rescan while the wallet doesn't hold the address", func() {
+			held = "addr(1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh)#a3d2fm6j"
+
+			Expect(store.Save(transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     BitcoinAssetID,
+				Descriptors: []string{"addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"},
+				Status:      transport.ImportScanning,
+				StartedAt:   time.Now().UTC(),
+			})).To(Succeed())
+
+			res, err := importer.GetImport("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportScanning))
+			Expect(res.Data.Import.Progress).To(BeZero())
+		})
+
+		It("Should fail the job once the wallet still doesn't hold the address after the import was sent", func() {
+			held, scanning = "", -1
+
+			Expect(store.Save(transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     BitcoinAssetID,
+				Descriptors: []string{"addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf"},
+				Status:      transport.ImportScanning,
+				StartedAt:   time.Now().Add(-time.Hour).UTC(),
+			})).To(Succeed())
+
+			res, err := importer.GetImport("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportFailed))
+			Expect(res.Data.Import.Error).To(Equal("the node did not import the addresses"))
+		})
+
+		It("Should fail the job the node didn't import once it's read from the wallet", func() {
+			held, scanning = "", -1
+
+			imported := make(chan struct{})
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Body:   MustLoad(fb.LoadFixture("bitcoin/req/importdescriptors.json", 1, "addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf", 1600000000)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					defer close(imported)
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors_error.json"))))
+				},
+			})
+
+			res, err := importer.StartImport(transport.ImportReq{
+				Addr:      "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+				Timestamp: 1600000000,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportScanning))
+
+			Eventually(imported).Should(BeClosed())
+
+			defer func(timeout time.Duration) { ImportAcceptTimeout = timeout }(ImportAcceptTimeout)
+			ImportAcceptTimeout = 0
+
+			res, err = importer.GetImport(res.Data.Import.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportFailed))
+			Expect(res.Data.Import.Error).To(Equal("the node did not import the addresses"))
+		})
+
+		It("Should not find the jobs of another asset", func() {
+			imported := make(chan struct{})
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Body:   MustLoad(fb.LoadFixture("bitcoin/req/importdescriptors.json", 1, "addr(1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu)#tqnpqvsf", `"now"`)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					defer close(imported)
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors.json"))))
+				},
+			})
+
+			res, err := importer.StartImport(transport.ImportReq{Addr: "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"})
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(imported).Should(BeClosed())
+
+			_, err = (&BitcoinClient{AssetID: LitecoinAssetID, Imports: store}).GetImport(res.Data.Import.ID)
+			Expect(err).To(Equal(transport.ErrImportNotFound))
+		})
+
+		It("Should not start jobs without a store to keep them in", func() {
+			_, err := (&BitcoinClient{AssetID: BitcoinAssetID}).StartImport(transport.ImportReq{Addr: "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"})
+			Expect(err).To(Equal(ErrImportStoreNotConfigured))
+		})
+
+		It("Should import the receive and change addresses of an xpub", func() {
+			xpub := "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+			receive := "wpkh(" + xpub + "/0/*)#wvk84d79"
+			change := "wpkh(" + xpub + "/1/*)#lcnxgcwa"
+
+			imported := make(chan struct{})
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Body:   MustLoad(fb.LoadFixture("bitcoin/req/importdescriptors_xpub.json", 1, receive, change, 1600000000)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					defer close(imported)
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors.json"))))
+				},
+			})
+
+			res, err := importer.StartImport(transport.ImportReq{
+				XPub:      xpub,
+				Script:    "p2wpkh",
+				Timestamp: 1600000000,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Descriptors).To(Equal([]string{receive, change}))
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportScanning))
+
+			Eventually(imported).Should(BeClosed())
+		})
+
+		It("Should not import private keys", func() {
+			_, err := importer.StartImport(transport.ImportReq{
+				XPub: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			})
+			Expect(err).To(MatchError("xpub must be an extended public key"))
+		})
+	})
+
+	Describe("#GetBalances", func() {
+		It("Should return the Bitcoin balances of every address from a single listunspent call", func() {
+			addr1 := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+			addr2 := "1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent_many.json", addr1, addr2)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent_many.json", addr1, addr1)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balances, err := client.(transport.BatchBalanceGetter).GetBalances([]string{addr1, addr2})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balances).To(MatchAllKeys(Keys{
+				addr1: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("BTC"),
+								"Balance":     Equal("0.750000"),
+								"Confirmed":   Equal("0.750000"),
+								"Unconfirmed": Equal("0.000000"),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
+					}),
+				})),
+				addr2: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("BTC"),
+								"Balance":     Equal("0.000000"),
+								"Confirmed":   Equal("0.000000"),
+								"Unconfirmed": Equal("0.000000"),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
+					}),
+				})),
+			}))
+		})
+	})
+
+	Describe("#ListTransactions", func() {
+		It("Should return every transaction received by the address, including those since spent", func() {
+			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+			spent := "8f2334f4037a945a0101408b5eacf657639d31548d22ef0f627f65eb00f0d36d"
+			pending := "1a2f2b5d3c7e2d6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listsinceblock.json", 1, "", 1)),
+				MustLoad(fb.LoadFixture("bitcoin/res/listsinceblock.json", addr, "tip")),
+			))
+
+			res, err := client.(transport.TransactionLister).ListTransactions(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Transactions).To(ConsistOf(
+				MatchAllFields(Fields{
+					"ID":    Equal(spent),
+					"From":  BeEmpty(),
+					"To":    Equal(addr),
+					"Value": Equal("0.010000"),
+					"Confirmations": MatchAllFields(Fields{
+						"Threshold": PointTo(Equal(int64(5))),
+						"Confirmed": BeTrue(),
+						"Value":     PointTo(Equal(int64(33))),
+						"Reorged":   BeFalse(),
+						"Pending":   BeFalse(),
+					}),
+					"Transfers":      BeEmpty(),
+					"Reverted":       BeFalse(),
+					"Fees":           BeNil(),
+					"Memo":           BeEmpty(),
+					"MemoType":       BeEmpty(),
+					"Coinbase":       BeFalse(),
+					"Outputs":        BeEmpty(),
+					"DestinationTag": BeNil(),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"ID":    Equal(pending),
+					"Value": Equal("0.005000"),
+					"Confirmations": MatchFields(IgnoreExtras, Fields{
+						"Confirmed": BeFalse(),
+						"Pending":   BeTrue(),
 					}),
 				}),
+			))
+		})
+
+		It("Should return no transactions for an address the wallet hasn't received to", func() {
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listsinceblock.json", 1, "", 1)),
+				MustLoad(fb.LoadFixture("bitcoin/res/listsinceblock.json", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", "tip")),
+			))
+
+			res, err := client.(transport.TransactionLister).ListTransactions("3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Transactions).To(BeEmpty())
+		})
+	})
+
+	Describe("#ListTransactionsSince", func() {
+		It("Should list the transactions since the cursor block until they have the confirmations", func() {
+			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+			cursor := "0000000000000000000a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6"
+			next := "00000000000000000003c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70819"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listsinceblock.json", 1, cursor, 6)),
+				MustLoad(fb.LoadFixture("bitcoin/res/listsinceblock.json", addr, next)),
+			))
+
+			res, last, err := client.(transport.TransactionSinceLister).ListTransactionsSince(addr, cursor, 6)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(last).To(Equal(next))
+			Expect(res.Data.Transactions).To(HaveLen(2))
+		})
+	})
+
+	Describe("#SubscribeBlocks", func() {
+		It("Should send the chain info for every hashblock notification", func() {
+			publisher, err := test.NewZMQPublisher(1)
+			Expect(err).ToNot(HaveOccurred())
+			defer publisher.Close()
+
+			client.(*BitcoinClient).ZMQURL = publisher.Addr()
+
+			bestBlockHash := "current-block-123"
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getinfo.json")),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getinfo.json", bestBlockHash)),
+				ResponseCode: http.StatusOK,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			blocks := make(chan transport.CoinData)
+			errc := make(chan error, 1)
+			go func() {
+				errc <- client.(transport.BlockSubscriber).SubscribeBlocks(ctx, blocks)
+			}()
+
+			Expect(publisher.Publish([]byte("hashblock"), make([]byte, 32), []byte{1, 0, 0, 0})).To(Succeed())
+			Expect(publisher.Subscriptions).To(Equal([]string{"hashblock"}))
+
+			Eventually(blocks).Should(Receive(MatchAllFields(Fields{
+				"Chain":        Equal("main"),
+				"BlockHeight":  Equal(595303),
+				"CurrentBlock": Equal(bestBlockHash),
 			})))
+
+			cancel()
+			Eventually(errc).Should(Receive(Equal(context.Canceled)))
+		})
+
+		It("Should not be supported without a zmq url", func() {
+			err := client.(transport.BlockSubscriber).SubscribeBlocks(context.Background(), make(chan transport.CoinData))
+			Expect(err).To(Equal(transport.ErrSubscriptionNotSupported))
 		})
 	})
 
-	Describe("#ImportAddress", func() {
-		Context("With new address", func() {
-			It("Should call import address twice, cancelling first request", func() {
-				addr := "test-address"
-
-				mockServer.Expect(test.ExpectedCall{
-					Path:   "/",
-					Method: "POST",
-					Headers: map[string]string{
-						"Content-Type":  "Application/Json",
-						"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-					},
-					Body: MustLoad(fb.LoadFixture("bitcoin/req/importaddress.json", 1, addr)),
-					Handler: func(w http.ResponseWriter, r *http.Request) {
-						time.Sleep(transport.DefaultClientTimeout + time.Second)
-					},
-				}).Then(test.ExpectedCall{
-					Path:   "/",
-					Method: "POST",
-					Headers: map[string]string{
-						"Content-Type":  "Application/Json",
-						"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-					},
-					Body:         MustLoad(fb.LoadFixture("bitcoin/req/importaddress.json", 2, addr)),
-					Response:     MustLoad(fb.LoadFixture("bitcoin/res/importaddress.json")),
-					ResponseCode: http.StatusOK,
-				})
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the block at the height transformed to the common output", func() {
+			hash := "00000000000000000008b1fa8b7a9b3f8f4ad9d1d2c5eb1b6a1e10d0b8d6b4f1"
 
-				err := client.(transport.AddressImporter).ImportAddress(addr)
-				Expect(err).ToNot(HaveOccurred())
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getblockhash.json", 595303)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getblockhash.json", hash)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getblock.json", 2, hash)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getblock.json", hash, 2)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(595303)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":    Equal(int64(595303)),
+				"Hash":      Equal(hash),
+				"Parent":    Equal("0000000000000000000c3fe5c6de4e1e4ff2a69f3b3b5ebd0bb6a5a9b4d3c2e1"),
+				"Timestamp": Equal(int64(1568729415)),
+				"TxCount":   Equal(2),
+				"TxIDs": Equal([]string{
+					"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+					"db1e1e6f0c7ac0b1fbb4cf4aeb1b68c2e9cbb5e1a4d5bd1fd25bb2a4d1a8b3c2",
+				}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should return the block with the hash transformed to the common output", func() {
+			hash := "00000000000000000008b1fa8b7a9b3f8f4ad9d1d2c5eb1b6a1e10d0b8d6b4f1"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getblock.json", 1, hash)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getblock.json", hash, 1)),
+				ResponseCode: http.StatusOK,
 			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block.Height).To(Equal(int64(595303)))
+			Expect(block.Data.Block.Hash).To(Equal(hash))
+			Expect(block.Data.Block.TxCount).To(Equal(2))
+		})
+
+		It("Should return an error for an invalid hash without calling the node", func() {
+			_, err := client.(transport.BlockFetcher).GetBlockByHash("not-a-hash")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#GetTransactionByHash with a mempool transaction", func() {
+		It("Should report the transaction as pending", func() {
+			txID := "6f5dfa31bef79d0c8cdd58530fc9f0ed2427e7085d421755f3fe78ca6ac326ef"
+			senderID := "c88f369cfe24e402eafd97c7318183ed780baa3b92a3459fc161eb9472ea532b"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose.json", txID, txID, 0)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 2, senderID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_sender.json", senderID, senderID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx.Data.Transaction.Confirmations).To(MatchAllFields(Fields{
+				"Threshold": PointTo(Equal(int64(5))),
+				"Confirmed": BeFalse(),
+				"Value":     PointTo(Equal(int64(0))),
+				"Reorged":   BeFalse(),
+				"Pending":   BeTrue(),
+			}))
+		})
+	})
+
+	Describe("#GetMempool", func() {
+		It("Should return the mempool size with the transactions bucketed by fee rate", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getmempoolinfo.json", 1)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getmempoolinfo.json")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawmempool.json", 2)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawmempool.json")),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(transport.MempoolInspector).GetMempool()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Mempool).To(MatchAllFields(Fields{
+				"Size":   Equal(3),
+				"Bytes":  Equal(int64(617)),
+				"Queued": BeZero(),
+				"FeeHistogram": Equal([]transport.FeeBucket{
+					{FeeRate: 0},
+					{FeeRate: 1},
+					{FeeRate: 2, Count: 2, Bytes: 391},
+					{FeeRate: 5},
+					{FeeRate: 10, Count: 1, Bytes: 226},
+					{FeeRate: 20},
+					{FeeRate: 50},
+					{FeeRate: 100},
+					{FeeRate: 200},
+					{FeeRate: 500},
+					{FeeRate: 1000},
+				}),
+			}))
+		})
+	})
+
+	Describe("#ListUTXOs", func() {
+		It("Should return the unspent outputs of the address with exact satoshi amounts", func() {
+			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent_utxos.json", addr, addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(transport.UTXOLister).ListUTXOs(addr, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.UTXOs).To(ConsistOf(
+				MatchAllFields(Fields{
+					"TxID":          Equal("8f2334f4037a945a0101408b5eacf657639d31548d22ef0f627f65eb00f0d36d"),
+					"Vout":          Equal(uint32(0)),
+					"Amount":        Equal(int64(29000000)),
+					"Script":        Equal("a9148ded4add6c0a5396c2e686acfea3558601f8851687"),
+					"Confirmations": Equal(int64(33)),
+				}),
+				MatchAllFields(Fields{
+					"TxID":          Equal("6f5dfa31bef79d0c8cdd58530fc9f0ed2427e7085d421755f3fe78ca6ac326ef"),
+					"Vout":          Equal(uint32(1)),
+					"Amount":        Equal(int64(462265)),
+					"Script":        Equal("a9148ded4add6c0a5396c2e686acfea3558601f8851687"),
+					"Confirmations": Equal(int64(1)),
+				}),
+			))
+		})
+	})
+
+	Describe("#GetConfirmedBalance", func() {
+		It("Should split the balance into the outputs above and below the confirmation depth", func() {
+			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent_mempool.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent_many.json", addr, addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balance, err := client.(transport.ConfirmedBalanceGetter).GetConfirmedBalance(addr, 20)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance.Data.Assets).To(ConsistOf(
+				MatchAllFields(Fields{
+					"Asset":       Equal("BTC"),
+					"Balance":     Equal("0.250000"),
+					"Confirmed":   Equal("0.250000"),
+					"Unconfirmed": Equal("0.500000"),
+					"Locked":      BeEmpty(),
+					"Issuer":      BeEmpty(),
+				}),
+			))
+		})
+	})
+
+	Describe("#ListXPubAddresses", func() {
+		zpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+		It("Should derive addresses until gap consecutive addresses are unused", func() {
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listreceivedbyaddress.json")),
+				MustLoad(fb.LoadFixture("bitcoin/res/listreceivedbyaddress.json", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu")),
+			))
+
+			res, err := client.(transport.XPubInspector).ListXPubAddresses(zpub, 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Script).To(Equal("p2wpkh"))
+			Expect(res.Data.Addresses).To(HaveLen(5))
+			Expect(res.Data.Addresses[0]).To(Equal(transport.XPubAddress{
+				Address: "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+				Path:    "m/84'/0'/0'/0/0",
+				Index:   0,
+				Used:    true,
+			}))
+			Expect(res.Data.Addresses[1].Address).To(Equal("bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"))
+			Expect(res.Data.Addresses[1].Used).To(BeFalse())
+			Expect(res.Data.Addresses[3]).To(Equal(transport.XPubAddress{
+				Address: "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el",
+				Path:    "m/84'/0'/0'/1/0",
+				Change:  true,
+				Index:   0,
+			}))
+		})
+
+		DescribeTable("Should derive the addresses of the script of the key's version",
+			func(xpub, script, addr, path string) {
+				mockServer.Expect(test.ExpectRPCJsonSuccess(
+					MustLoad(fb.LoadFixture("bitcoin/req/listreceivedbyaddress.json")),
+					MustLoad(fb.LoadFixture("bitcoin/res/listreceivedbyaddress.json", addr)),
+				))
+
+				res, err := client.(transport.XPubInspector).ListXPubAddresses(xpub, 1)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(res.Data.Script).To(Equal(script))
+				Expect(res.Data.Addresses[0].Address).To(Equal(addr))
+				Expect(res.Data.Addresses[0].Path).To(Equal(path))
+			},
+			Entry("xpub", "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj", "p2pkh", "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", "m/44'/0'/0'/0/0"),
+			Entry("ypub", "ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP", "p2sh-p2wpkh", "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf", "m/49'/0'/0'/0/0"),
+			Entry("zpub", zpub, "p2wpkh", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "m/84'/0'/0'/0/0"),
+		)
+
+		It("Should not accept the keys of another network", func() {
+			_, err := client.(transport.XPubInspector).ListXPubAddresses("tpubD6NzVbkrYhZ4XgiXtGrdW5XDAPFCL9h7we1vwNCpn8tGbBcgfVYjXyhWo4E1xkh56hjod1RhGjxbaTLV3X4FyWuejifB9jusQ46QzG87VKp", 20)
+			Expect(err).To(MatchError("xpub is not for the mainnet network"))
+		})
+	})
+
+	Describe("#GetXPubBalance", func() {
+		It("Should sum the balances of the used addresses", func() {
+			zpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+			addr := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listreceivedbyaddress.json")),
+				MustLoad(fb.LoadFixture("bitcoin/res/listreceivedbyaddress.json", addr)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listunspent_xpub.json", addr)),
+				MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.01, addr)),
+			))
+
+			balance, err := client.(transport.XPubInspector).GetXPubBalance(zpub, 20)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance.Data.Assets).To(ConsistOf(transport.Asset{
+				Asset:       "BTC",
+				Balance:     "0.010000",
+				Confirmed:   "0.010000",
+				Unconfirmed: "0.000000",
+			}))
+		})
+	})
+
+	Describe("#ListXPubTransactions", func() {
+		It("Should list each transaction once with its value netted across the used addresses", func() {
+			zpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+			receive := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+			change := "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el"
+			payee := "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+
+			// deposit pays the receive address, spend pays the payee from it with change, consolidate moves
+			// funds between the xpub's addresses and unrelated is a payment from another address of the wallet.
+			deposit := "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+			spend := "b1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+			consolidate := "c1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+			unrelated := "d1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+			funding := "e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listreceivedbyaddress.json")),
+				MustLoad(fb.LoadFixture("bitcoin/res/listreceivedbyaddress_xpub.json", receive, change, deposit, spend, consolidate)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/listsinceblock.json", 2, "", 1)),
+				MustLoad(fb.LoadFixture("bitcoin/res/listsinceblock_xpub.json", receive, change, deposit, spend, consolidate, unrelated)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 3, deposit)),
+				MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_xpub.json", deposit, funding, 0, 0.01, receive, 0.5, payee, 3)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 4, spend)),
+				MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_xpub.json", spend, deposit, 0, 0.004, payee, 0.0059, change, 2)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 5, consolidate)),
+				MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_xpub.json", consolidate, spend, 1, 0.003, receive, 0.0028, change, 1)),
+			)).Then(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 6, unrelated)),
+				MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_xpub.json", unrelated, funding, 1, 0.1, payee, 0.2, payee, 1)),
+			))
+
+			res, err := client.(transport.XPubInspector).ListXPubTransactions(zpub, 20)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Transactions).To(HaveLen(2))
+			Expect(res.Data.Transactions[0]).To(MatchFields(IgnoreExtras, Fields{
+				"ID":    Equal(deposit),
+				"From":  BeEmpty(),
+				"To":    Equal(receive),
+				"Value": Equal("0.010000"),
+			}))
+			Expect(res.Data.Transactions[0].Confirmations.Value).To(PointTo(Equal(int64(3))))
+			Expect(res.Data.Transactions[1]).To(MatchFields(IgnoreExtras, Fields{
+				"ID":    Equal(spend),
+				"From":  Equal(receive),
+				"To":    Equal(payee),
+				"Value": Equal("-0.004100"),
+			}))
 		})
 	})
 })
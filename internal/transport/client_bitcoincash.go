@@ -11,15 +11,14 @@ type BitcoinCashClient struct {
 
 // NewBitcoincashClient returns a new client using os variables.
 func NewBitcoincashClient() (*BitcoinCashClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("BITCOINCASH_URL"))
+	return newBitcoincashClient(MainNet)
+}
+
+func newBitcoincashClient(network string) (*BitcoinCashClient, error) {
+	btcClient, err := newBTCFamilyClient(BitcoinCashAssetID, "BITCOINCASH", network, BitcoinCashNetwork)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BitcoinCashClient{
-		BitcoinClient: &BitcoinClient{
-			AssetID: BitcoinCashAssetID,
-			Client:  btcClient,
-		},
-	}, nil
+	return &BitcoinCashClient{BitcoinClient: btcClient}, nil
 }
@@ -11,16 +11,15 @@ type BitcoinGoldClient struct {
 
 // NewBitcoinGoldClient returns a new client using os variables.
 func NewBitcoinGoldClient() (*BitcoinGoldClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("BITCOINGOLD_URL"))
+	return newBitcoinGoldClient(MainNet)
+}
+
+func newBitcoinGoldClient(network string) (*BitcoinGoldClient, error) {
+	btcClient, err := newBTCFamilyClient(BitcoinGoldAssetID, "BITCOINGOLD", network, BitcoinGoldNetwork)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BitcoinGoldClient{
-		BitcoinClient: &BitcoinClient{
-			AssetID: BitcoinGoldAssetID,
-			Client:  btcClient,
-		},
-	}, nil
+	return &BitcoinGoldClient{BitcoinClient: btcClient}, nil
 }
 
@@ -55,6 +55,7 @@ var _ = Describe("BitcoinGoldClient", func() {
 			BitcoinClient: &BitcoinClient{
 				AssetID: BitcoinGoldAssetID,
 				Client:  btcClient,
+				Network: BitcoinGoldNetwork,
 			},
 		}
 	})
@@ -65,7 +66,7 @@ var _ = Describe("BitcoinGoldClient", func() {
 
 	Describe("#GetBalance", func() {
 		It("Should return the Bitcoin balance transformed to the common output", func() {
-			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+			addr := "GUfA8DYcC25YhaaBeqYSsPXYTUiTniXhen"
 
 			mockServer.Expect(test.ExpectedCall{
 				Path:   "/",
@@ -74,8 +75,8 @@ var _ = Describe("BitcoinGoldClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent.json", addr)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent_mempool.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265, addr)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -86,8 +87,12 @@ var _ = Describe("BitcoinGoldClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("BTG"),
-							"Balance": Equal("0.004623"),
+							"Asset":       Equal("BTG"),
+							"Balance":     Equal("0.004623"),
+							"Confirmed":   Equal("0.004623"),
+							"Unconfirmed": Equal("0.000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -136,8 +141,8 @@ var _ = Describe("BitcoinGoldClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 1, txID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction.json")),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose.json", txID, txID, 46413)),
 				ResponseCode: http.StatusOK,
 			}).Then(test.ExpectedCall{
 				Path:   "/",
@@ -146,28 +151,8 @@ var _ = Describe("BitcoinGoldClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction.json", txID, txID)),
-				ResponseCode: http.StatusOK,
-			}).Then(test.ExpectedCall{
-				Path:   "/",
-				Method: "POST",
-				Headers: map[string]string{
-					"Content-Type":  "Application/Json",
-					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 3, senderID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_sender.json")),
-				ResponseCode: http.StatusOK,
-			}).Then(test.ExpectedCall{
-				Path:   "/",
-				Method: "POST",
-				Headers: map[string]string{
-					"Content-Type":  "Application/Json",
-					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction_sender.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction_sender.json", senderID, senderID)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 2, senderID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_sender.json", senderID, senderID)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -185,7 +170,34 @@ var _ = Describe("BitcoinGoldClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(46413))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": BeEmpty(),
+						"Reverted":  BeFalse(),
+						"Fees":      BeNil(),
+						"Memo":      BeEmpty(),
+						"MemoType":  BeEmpty(),
+						"Coinbase":  BeFalse(),
+						"Outputs": ConsistOf(
+							MatchAllFields(Fields{
+								"Index":    Equal(0),
+								"Address":  Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("25"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+							MatchAllFields(Fields{
+								"Index":    Equal(1),
+								"Address":  Equal("1LJB8MNgNwhZ7KJPjUadSdv4eboreNoybS"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("0.09881791"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+						),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
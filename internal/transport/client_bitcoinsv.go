@@ -11,15 +11,14 @@ type BitcoinsvClient struct {
 
 // NewBitcoinsvClient returns a new client using os variables.
 func NewBitcoinsvClient() (*BitcoinsvClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("BITCOINSV_URL"))
+	return newBitcoinsvClient(MainNet)
+}
+
+func newBitcoinsvClient(network string) (*BitcoinsvClient, error) {
+	btcClient, err := newBTCFamilyClient(BitcoinsvAssetID, "BITCOINSV", network, BitcoinSVNetwork)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BitcoinsvClient{
-		BitcoinClient: &BitcoinClient{
-			AssetID: BitcoinsvAssetID,
-			Client:  btcClient,
-		},
-	}, nil
+	return &BitcoinsvClient{BitcoinClient: btcClient}, nil
 }
@@ -175,18 +175,25 @@ func (c CardanoClient) GetTransactionByHash(hash string) (*transport.Transaction
 		return nil, err
 	}
 
+	confirmations := transport.Confirmations{
+		Confirmed: true,
+	}
+
+	// the explorer leaves the block fields empty for transactions still in the mempool.
+	if transaction.Right.CtsBlockHash == "" {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    transaction.Right.CtsID,
-				From:  fromHeader.Account,
-				To:    toHeader.Account,
-				Value: fromHeader.Value,
-				Confirmations: transport.Confirmations{
-					Confirmed: true,
-				},
+				ID:            transaction.Right.CtsID,
+				From:          fromHeader.Account,
+				To:            toHeader.Account,
+				Value:         fromHeader.Value,
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
@@ -66,8 +66,12 @@ var _ = Describe("CardanoClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("ADA"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("ADA"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -124,7 +128,17 @@ var _ = Describe("CardanoClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
@@ -94,6 +94,18 @@ type DecredAddressResponse struct {
 	TxApperances            int     `json:"txApperances"`
 }
 
+// DecredUTXOResponse represents an unspent output in a successful address utxo response.
+type DecredUTXOResponse struct {
+	Address       string  `json:"address"`
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+	Height        int64   `json:"height"`
+	Amount        float64 `json:"amount"`
+	Satoshis      int64   `json:"satoshis"`
+	Confirmations int64   `json:"confirmations"`
+}
+
 // DecredClient is the Decred implementation of the CoinClient
 type DecredClient struct {
 	transport.BaseClient
@@ -151,6 +163,34 @@ func (d DecredClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
+// ListUTXOs returns the unspent outputs of the address with at least minConf confirmations.
+func (d DecredClient) ListUTXOs(addr string, minConf int) (*transport.UTXOsResp, error) {
+	var unspent []DecredUTXOResponse
+
+	if err := d.GET("/insight/api/addr/"+addr+"/utxo", nil, &unspent); err != nil {
+		return nil, err
+	}
+
+	res := &transport.UTXOsResp{}
+	res.Data.UTXOs = []transport.UTXO{}
+
+	for _, value := range unspent {
+		if value.Confirmations < int64(minConf) {
+			continue
+		}
+
+		res.Data.UTXOs = append(res.Data.UTXOs, transport.UTXO{
+			TxID:          value.TxID,
+			Vout:          value.Vout,
+			Amount:        value.Satoshis,
+			Script:        value.ScriptPubKey,
+			Confirmations: value.Confirmations,
+		})
+	}
+
+	return res, nil
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (d DecredClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var tx DecredTXResponse
@@ -159,20 +199,27 @@ func (d DecredClient) GetTransactionByHash(hash string) (*transport.TransactionR
 		return nil, err
 	}
 
+	confirmations := transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: tx.Confirmations > *transport.ConfirmThresholdValue,
+		Value:     transport.NewInt64(tx.Confirmations),
+	}
+
+	// insight reports no confirmations for transactions still in the mempool.
+	if tx.Confirmations == 0 {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  tx.Vin[0].Addr,
-				To:    tx.Vout[0].ScriptPubKey.Addresses[0],
-				Value: fmt.Sprintf("%f", tx.ValueOut),
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: tx.Confirmations > *transport.ConfirmThresholdValue,
-					Value:     transport.NewInt64(tx.Confirmations),
-				},
+				ID:            hash,
+				From:          tx.Vin[0].Addr,
+				To:            tx.Vout[0].ScriptPubKey.Addresses[0],
+				Value:         fmt.Sprintf("%f", tx.ValueOut),
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
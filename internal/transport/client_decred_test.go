@@ -97,8 +97,12 @@ var _ = Describe("DecredClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("DCR"),
-							"Balance": Equal("3500.740258"),
+							"Asset":       Equal("DCR"),
+							"Balance":     Equal("3500.740258"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -131,10 +135,46 @@ var _ = Describe("DecredClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(8))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#ListUTXOs", func() {
+		It("Should return the unspent outputs with enough confirmations", func() {
+			addr := "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/insight/api/addr/" + addr + "/utxo",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("decred/res/getutxos.json", addr, addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(UTXOLister).ListUTXOs(addr, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.UTXOs).To(ConsistOf(
+				MatchAllFields(Fields{
+					"TxID":          Equal("1f5a4c2e9b8d7f6a5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a29"),
+					"Vout":          Equal(uint32(0)),
+					"Amount":        Equal(int64(1234567891)),
+					"Script":        Equal("76a914f2d1c0b9a8f7e6d5c4b3a29180706f5e4d3c2b1a88ac"),
+					"Confirmations": Equal(int64(10)),
+				}),
+			))
+		})
+	})
 })
@@ -11,15 +11,14 @@ type DogecoinClient struct {
 
 // NewDogecoinClient returns a new client using os variables.
 func NewDogecoinClient() (*DogecoinClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("DOGECOIN_URL"))
+	return newDogecoinClient(MainNet)
+}
+
+func newDogecoinClient(network string) (*DogecoinClient, error) {
+	btcClient, err := newBTCFamilyClient(DogecoinAssetID, "DOGECOIN", network, DogecoinNetwork)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DogecoinClient{
-		BitcoinClient: &BitcoinClient{
-			AssetID: DogecoinAssetID,
-			Client:  btcClient,
-		},
-	}, nil
+	return &DogecoinClient{BitcoinClient: btcClient}, nil
 }
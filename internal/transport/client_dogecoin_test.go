@@ -2,10 +2,13 @@ package transport_test
 
 import (
 	"github.com/hugorut/coins-oracle/pkg/transport"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"time"
 
 	"github.com/btcsuite/btcd/rpcclient"
 	. "github.com/onsi/ginkgo"
@@ -55,6 +58,7 @@ var _ = Describe("DogecoinClient", func() {
 			BitcoinClient: &BitcoinClient{
 				AssetID: DogecoinAssetID,
 				Client:  btcClient,
+				Network: DogecoinNetwork,
 			},
 		}
 	})
@@ -65,7 +69,7 @@ var _ = Describe("DogecoinClient", func() {
 
 	Describe("#GetBalance", func() {
 		It("Should return the Bitcoin balance transformed to the common output", func() {
-			addr := "3EdTTxcfptcBziNR1YH3pdcWdQ923jSXaR"
+			addr := "DFxLFMAJWaNYA7TVTUstzPMFRSevAwTSLq"
 
 			mockServer.Expect(test.ExpectedCall{
 				Path:   "/",
@@ -74,8 +78,8 @@ var _ = Describe("DogecoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent.json", addr)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/listunspent_mempool.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/listunspent.json", 0.00462265, addr)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -86,8 +90,12 @@ var _ = Describe("DogecoinClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("DOGE"),
-							"Balance": Equal("0.004623"),
+							"Asset":       Equal("DOGE"),
+							"Balance":     Equal("0.004623"),
+							"Confirmed":   Equal("0.004623"),
+							"Unconfirmed": Equal("0.000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -96,8 +104,8 @@ var _ = Describe("DogecoinClient", func() {
 	})
 
 	Describe("#GetInfo", func() {
-		It("Should return the Bitcoin node information transformed to the common output", func() {
-			bestBlockHash := "current-block-123"
+		It("Should return the node information from getinfo transformed to the common output", func() {
+			bestBlockHash := "a5eb5b4ab5bd8ad5ab4b8fa2f8a1c0b4e3ba0fde3bd0a2b5b0b32e0d8ab42e81"
 
 			mockServer.Expect(test.ExpectedCall{
 				Path:   "/",
@@ -106,8 +114,18 @@ var _ = Describe("DogecoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getinfo.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getinfo.json", bestBlockHash)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getinfo_legacy.json")),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getinfo_legacy.json")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type":  "Application/Json",
+					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
+				},
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getbestblockhash.json", 2)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getbestblockhash.json", bestBlockHash)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -118,7 +136,7 @@ var _ = Describe("DogecoinClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Chain":        Equal("main"),
 					"CurrentBlock": Equal(bestBlockHash),
-					"BlockHeight":  Equal(595303),
+					"BlockHeight":  Equal(4371298),
 				}),
 			})))
 		})
@@ -136,28 +154,8 @@ var _ = Describe("DogecoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 1, txID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction.json")),
-				ResponseCode: http.StatusOK,
-			}).Then(test.ExpectedCall{
-				Path:   "/",
-				Method: "POST",
-				Headers: map[string]string{
-					"Content-Type":  "Application/Json",
-					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction.json", txID, txID)),
-				ResponseCode: http.StatusOK,
-			}).Then(test.ExpectedCall{
-				Path:   "/",
-				Method: "POST",
-				Headers: map[string]string{
-					"Content-Type":  "Application/Json",
-					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
-				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction.json", 3, senderID)),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_sender.json")),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 1, txID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose.json", txID, txID, 46413)),
 				ResponseCode: http.StatusOK,
 			}).Then(test.ExpectedCall{
 				Path:   "/",
@@ -166,8 +164,8 @@ var _ = Describe("DogecoinClient", func() {
 					"Content-Type":  "Application/Json",
 					"Authorization": "Basic " + test.BasicAuth(rpcUser, rpcPass),
 				},
-				Body:         MustLoad(fb.LoadFixture("bitcoin/req/decoderawtransaction_sender.json")),
-				Response:     MustLoad(fb.LoadFixture("bitcoin/res/decoderawtransaction_sender.json", senderID, senderID)),
+				Body:         MustLoad(fb.LoadFixture("bitcoin/req/getrawtransaction_verbose.json", 2, senderID)),
+				Response:     MustLoad(fb.LoadFixture("bitcoin/res/getrawtransaction_verbose_sender.json", senderID, senderID)),
 				ResponseCode: http.StatusOK,
 			})
 
@@ -185,10 +183,115 @@ var _ = Describe("DogecoinClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(46413))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": BeEmpty(),
+						"Reverted":  BeFalse(),
+						"Fees":      BeNil(),
+						"Memo":      BeEmpty(),
+						"MemoType":  BeEmpty(),
+						"Coinbase":  BeFalse(),
+						"Outputs": ConsistOf(
+							MatchAllFields(Fields{
+								"Index":    Equal(0),
+								"Address":  Equal("1Hb1xsuhehKYcvkTRjWUxkF4Lh75kifZZh"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("25"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+							MatchAllFields(Fields{
+								"Index":    Equal(1),
+								"Address":  Equal("1LJB8MNgNwhZ7KJPjUadSdv4eboreNoybS"),
+								"Type":     Equal("p2pkh"),
+								"Value":    Equal("0.09881791"),
+								"Required": BeZero(),
+								"PubKeys":  BeEmpty(),
+							}),
+						),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#StartImport", func() {
+		var (
+			dir   string
+			store *FileImportStore
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "imports")
+			Expect(err).ToNot(HaveOccurred())
+
+			store, err = NewFileImportStore(filepath.Join(dir, "imports.json"))
+			Expect(err).ToNot(HaveOccurred())
+
+			client.(DogecoinClient).BitcoinClient.Imports = store
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("Should import the address with importmulti as dogecoin nodes have no descriptor wallets", func() {
+			addr := "DFxLFMAJWaNYA7TVTUstzPMFRSevAwTSLq"
+			imported := make(chan struct{})
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Body:   MustLoad(fb.LoadFixture("bitcoin/req/importmulti.json", 1, addr, 1600000000)),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					defer close(imported)
+					_, _ = w.Write([]byte(MustLoad(fb.LoadFixture("bitcoin/res/importdescriptors.json"))))
+				},
+			})
+
+			res, err := client.(transport.ImportScheduler).StartImport(transport.ImportReq{
+				Addr:      addr,
+				Timestamp: 1600000000,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Descriptors).To(ConsistOf("addr(DFxLFMAJWaNYA7TVTUstzPMFRSevAwTSLq)#2sfml30d"))
+
+			Eventually(imported).Should(BeClosed())
+		})
+
+		It("Should complete the job once the wallet watches the address", func() {
+			addr := "DFxLFMAJWaNYA7TVTUstzPMFRSevAwTSLq"
+
+			Expect(store.Save(transport.ImportJob{
+				ID:          "5f0c",
+				AssetID:     DogecoinAssetID,
+				Descriptors: []string{"addr(" + addr + ")#2sfml30d"},
+				Status:      transport.ImportScanning,
+				StartedAt:   time.Now().UTC(),
+			})).To(Succeed())
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/getwalletinfo.json", 1)),
+				MustLoad(fb.LoadFixture("bitcoin/res/getwalletinfo_legacy.json")),
+			))
+			mockServer.Expect(test.ExpectRPCJsonSuccess(
+				MustLoad(fb.LoadFixture("bitcoin/req/validateaddress.json", 2, addr)),
+				MustLoad(fb.LoadFixture("bitcoin/res/validateaddress_watchonly.json", addr)),
+			))
+
+			res, err := client.(transport.ImportScheduler).GetImport("5f0c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Data.Import.Status).To(Equal(transport.ImportComplete))
+		})
+
+		It("Should not import xpubs", func() {
+			_, err := client.(transport.ImportScheduler).StartImport(transport.ImportReq{
+				XPub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+			})
+			Expect(err).To(MatchError("DOGE nodes can't import xpubs"))
+		})
+	})
 })
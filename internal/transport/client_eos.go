@@ -1,28 +1,46 @@
 package transport
 
 import (
-	"fmt"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/pkg/errors"
 
 	"github.com/hugorut/coins-oracle/pkg/transport"
 )
 
 var (
 	EosAssetID = "EOS"
+
+	// EosTokenContract is the contract of the EOS token, which is always read along with the configured contracts.
+	EosTokenContract = "eosio.token"
 )
 
 // EosClient is the Eos implementation of the CoinClient
 type EosClient struct {
 	Client *eos.API
+	// TokenContracts are the accounts of the token contracts balances and transfers are read from besides eosio.token.
+	TokenContracts []string
 }
 
-// NewEosClient returns a new client using os variables.
+// NewEosClient returns a new client using os variables. EOS_TOKEN_CONTRACTS holds a comma separated
+// list of the token contracts to read besides eosio.token.
 func NewEosClient() (*EosClient, error) {
 	api := eos.New(getNodeURL("EOS_URL"))
 
+	var contracts []string
+	for _, contract := range strings.Split(os.Getenv("EOS_TOKEN_CONTRACTS"), ",") {
+		if contract = strings.TrimSpace(contract); contract != "" {
+			contracts = append(contracts, contract)
+		}
+	}
+
 	return &EosClient{
-		Client: api,
+		Client:         api,
+		TokenContracts: contracts,
 	}, nil
 }
 
@@ -42,21 +60,51 @@ func (e EosClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
-// GetBalance returns the balance of the address.
+// GetBalance returns the balance of the address for every token it holds in the token contracts.
 func (e EosClient) GetBalance(addr string) (*transport.Balance, error) {
 	name := eos.AccountName(addr)
-	code := eos.AccountName("eosio.token")
 
-	balance, err := e.Client.GetCurrencyBalance(name, "", code)
+	var balances []eos.Asset
+	var contracts []string
+	for _, contract := range e.contracts() {
+		balance, err := e.Client.GetCurrencyBalance(name, "", eos.AccountName(contract))
+		if err != nil && contract == EosTokenContract {
+			return nil, err
+		}
+
+		// a misconfigured or unreachable token contract shouldn't keep the other balances from being returned.
+		if err != nil {
+			log.Printf("[EOS CLIENT] skipping balance of token contract: %s for account: %s, err: %s", contract, addr, err)
+			continue
+		}
+
+		for _, a := range balance {
+			balances = append(balances, a)
+			contracts = append(contracts, contract)
+		}
+	}
+
+	account, err := e.Client.GetAccount(name)
 	if err != nil {
 		return nil, err
 	}
 
-	assets := make([]transport.Asset, len(balance))
-	for key, a := range balance {
+	// tokens the account staked to itself for cpu and net are locked until they are unstaked. The bandwidth is
+	// null for accounts which staked nothing, so the precision is taken from the EOS balance.
+	bandwidth := account.SelfDelegatedBandwidth
+	staked := bandwidth.CPUWeight.Amount + bandwidth.NetWeight.Amount
+
+	assets := make([]transport.Asset, len(balances))
+	for key, a := range balances {
 		assets[key] = transport.Asset{
 			Asset:   a.Symbol.Symbol,
-			Balance: fmt.Sprintf("%d", a.Amount),
+			Balance: eosAmount(a),
+		}
+
+		if isEosToken(contracts[key], a.Symbol.Symbol) {
+			assets[key].Locked = eosAmount(eos.Asset{Amount: staked, Symbol: a.Symbol})
+		} else {
+			assets[key].Issuer = contracts[key]
 		}
 	}
 
@@ -67,24 +115,96 @@ func (e EosClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
-// GetTransactionByHash returns the transaction stored at the given hash.
+// GetResources returns the cpu, net and ram of the account, along with the EOS staked for its cpu and net.
+func (e EosClient) GetResources(addr string) (*transport.ResourcesResp, error) {
+	account, err := e.Client.GetAccount(eos.AccountName(addr))
+	if err != nil {
+		return nil, err
+	}
+
+	ramAvailable := int64(-1)
+	if account.RAMQuota >= 0 {
+		ramAvailable = int64(account.RAMQuota - account.RAMUsage)
+	}
+
+	res := &transport.ResourcesResp{}
+	res.Data.Resources = []transport.Resource{
+		{
+			Resource:  "cpu",
+			Unit:      "us",
+			Used:      int64(account.CPULimit.Used),
+			Available: int64(account.CPULimit.Available),
+			Max:       int64(account.CPULimit.Max),
+			Staked:    eosStaked(account.TotalResources.CPUWeight),
+		},
+		{
+			Resource:  "net",
+			Unit:      "bytes",
+			Used:      int64(account.NetLimit.Used),
+			Available: int64(account.NetLimit.Available),
+			Max:       int64(account.NetLimit.Max),
+			Staked:    eosStaked(account.TotalResources.NetWeight),
+		},
+		{
+			// ram is bought rather than staked for.
+			Resource:  "ram",
+			Unit:      "bytes",
+			Used:      int64(account.RAMUsage),
+			Available: ramAvailable,
+			Max:       int64(account.RAMQuota),
+		},
+	}
+
+	return res, nil
+}
+
+// eosTransfer represents the data of the transfer action of a token contract.
+type eosTransfer struct {
+	From     eos.AccountName `json:"from"`
+	To       eos.AccountName `json:"to"`
+	Quantity eos.Asset       `json:"quantity"`
+	Memo     string          `json:"memo"`
+}
+
+// GetTransactionByHash returns the transaction stored at the given hash, with a transfer for every transfer
+// action of the token contracts it executed, inline actions included.
 func (e EosClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	t, err := e.Client.GetTransaction(hash)
 	if err != nil {
 		return nil, err
 	}
 
-	var from string
-	var to string
-	var value string
-	for _, a := range t.Transaction.Transaction.Actions {
-		if a.Name == eos.ActionName("transfer") {
-			m := a.Data.(map[string]interface{})
+	actions := eosExecutedActions(t.Traces)
+	if len(t.Traces) == 0 {
+		// nodes which don't keep traces only return the actions of the transaction itself.
+		actions = t.Transaction.Transaction.Actions
+	}
+
+	transfers := []transport.Transfer{}
+	var memo string
+	for _, a := range actions {
+		if a.Name != eos.ActionName("transfer") || !e.isTokenContract(string(a.Account)) {
+			continue
+		}
+
+		transfer, err := decodeEosTransfer(a)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(transfers) == 0 {
+			memo = transfer.Memo
+		}
+
+		transfers = append(transfers, transport.Transfer{
+			From:  string(transfer.From),
+			To:    string(transfer.To),
+			Value: eosAmount(transfer.Quantity),
+		})
 
-			from = fmt.Sprintf("%s", m["from"])
-			to = fmt.Sprintf("%s", m["to"])
-			value = fmt.Sprintf("%s", m["quantity"])
-			break
+		if !isEosToken(string(a.Account), transfer.Quantity.Symbol.Symbol) {
+			transfers[len(transfers)-1].Asset = transfer.Quantity.Symbol.Symbol
+			transfers[len(transfers)-1].Issuer = string(a.Account)
 		}
 	}
 
@@ -95,21 +215,121 @@ func (e EosClient) GetTransactionByHash(hash string) (*transport.TransactionResp
 
 	included := transport.NewInt64(int64(info.HeadBlockNum) - int64(t.BlockNum))
 
+	transaction := transport.Transaction{
+		ID: t.ID.String(),
+		Confirmations: transport.Confirmations{
+			Threshold: transport.ConfirmThresholdValue,
+			Confirmed: *transport.ConfirmThresholdValue <= *included,
+			Value:     included,
+		},
+		Transfers: transfers,
+		Memo:      memo,
+	}
+
+	if len(transfers) > 0 {
+		transaction.From = transfers[0].From
+		transaction.To = transfers[0].To
+		transaction.Value = transfers[0].Value
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
-			Transaction: transport.Transaction{
-				From:  from,
-				ID:    t.ID.String(),
-				To:    to,
-				Value: value,
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: *transport.ConfirmThresholdValue <= *included,
-					Value:     included,
-				},
-			},
+			Transaction: transaction,
 		},
 	}, nil
 }
+
+// contracts returns eosio.token followed by the other token contracts configured.
+func (e EosClient) contracts() []string {
+	contracts := []string{EosTokenContract}
+	for _, contract := range e.TokenContracts {
+		if contract != EosTokenContract {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	return contracts
+}
+
+func (e EosClient) isTokenContract(account string) bool {
+	for _, contract := range e.contracts() {
+		if contract == account {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eosExecutedActions flattens the traces, and the traces of the inline actions they sent, into the actions
+// executed in the order they were. Traces of the notifications sent to the other accounts of an action are
+// left out so each action is only returned once.
+func eosExecutedActions(traces []eos.ActionTrace) []*eos.Action {
+	var (
+		actions []*eos.Action
+		seen    = map[eos.Uint64]bool{}
+		walk    func(trace *eos.ActionTrace)
+	)
+
+	walk = func(trace *eos.ActionTrace) {
+		// nodes can list an inline trace both on its own and under its parent, but its global sequence is unique.
+		seq := trace.Receipt.GlobalSequence
+		if trace.Action == nil || (seq != 0 && seen[seq]) {
+			return
+		}
+
+		seen[seq] = true
+		if trace.Receipt.Receiver == trace.Action.Account {
+			actions = append(actions, trace.Action)
+		}
+
+		for _, inline := range trace.InlineTraces {
+			walk(inline)
+		}
+	}
+
+	for i := range traces {
+		walk(&traces[i])
+	}
+
+	return actions
+}
+
+// decodeEosTransfer decodes the data of the transfer action, which is decoded from json into a map unless
+// an abi was registered for the action.
+func decodeEosTransfer(a *eos.Action) (eosTransfer, error) {
+	var transfer eosTransfer
+
+	b, err := json.Marshal(a.Data)
+	if err != nil {
+		return transfer, errors.Wrap(err, "error encoding eos transfer")
+	}
+
+	if err := json.Unmarshal(b, &transfer); err != nil {
+		return transfer, errors.Wrap(err, "error decoding eos transfer")
+	}
+
+	return transfer, nil
+}
+
+// eosAmount returns the amount of the asset as a decimal with the precision of its symbol.
+func eosAmount(a eos.Asset) string {
+	return strings.TrimSuffix(a.String(), " "+a.Symbol.Symbol)
+}
+
+// eosStaked returns the amount staked for a resource, or nothing when the node returned no stake for the account,
+// as it does for privileged accounts whose resources are unlimited.
+func eosStaked(a eos.Asset) string {
+	if a.Symbol.Symbol == "" {
+		return ""
+	}
+
+	return eosAmount(a)
+}
+
+// isEosToken reports whether the symbol of the contract is the chain's own EOS rather than a token of the same name.
+func isEosToken(contract, symbol string) bool {
+	return contract == EosTokenContract && symbol == EosAssetID
+}
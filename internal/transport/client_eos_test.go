@@ -52,6 +52,12 @@ var _ = Describe("EosClient", func() {
 				Body:         MustLoad(fb.LoadFixture("eos/req/getcurrencybalance.json", addr)),
 				Response:     MustLoad(fb.LoadFixture("eos/res/getcurrencybalance.json", balR)),
 				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:         "/v1/chain/get_account",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getaccount.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getaccount.json", addr, addr, addr, addr)),
+				ResponseCode: http.StatusOK,
 			})
 
 			balance, err := client.GetBalance(addr)
@@ -61,8 +67,12 @@ var _ = Describe("EosClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("EOS"),
-							"Balance": Equal(balR),
+							"Asset":       Equal("EOS"),
+							"Balance":     Equal(balR),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("3"),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -70,6 +80,125 @@ var _ = Describe("EosClient", func() {
 		})
 	})
 
+	Describe("#GetBalance with token contracts", func() {
+		It("Should return the balance of every configured token with the precision of its symbol", func() {
+			addr := "eospaceioeos"
+			client = &EosClient{
+				Client:         eos.New(mockServer.HttpTest.URL),
+				TokenContracts: []string{"eosdtsttoken"},
+			}
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/v1/chain/get_currency_balance",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getcurrencybalance.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getcurrencybalance_token.json", "12.3400 EOS")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:         "/v1/chain/get_currency_balance",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getcurrencybalance_token.json", "eosdtsttoken", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getcurrencybalance_token.json", "0.050000000 EOSDT")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:         "/v1/chain/get_account",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getaccount.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getaccount_resources.json", addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balance, err := client.GetBalance(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance.Data.Assets).To(Equal([]transport.Asset{
+				{Asset: "EOS", Balance: "12.3400", Locked: "1.5000"},
+				{Asset: "EOSDT", Balance: "0.050000000", Issuer: "eosdtsttoken"},
+			}))
+		})
+	})
+
+	Describe("#GetBalance with a failing token contract", func() {
+		It("Should skip the contract and return the EOS staked with the precision of the EOS balance", func() {
+			addr := "eospaceioeos"
+			client = &EosClient{
+				Client:         eos.New(mockServer.HttpTest.URL),
+				TokenContracts: []string{"eosdtsttoken"},
+			}
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/v1/chain/get_currency_balance",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getcurrencybalance.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getcurrencybalance_token.json", "12.3400 EOS")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:         "/v1/chain/get_currency_balance",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getcurrencybalance_token.json", "eosdtsttoken", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getcurrencybalance_error.json")),
+				ResponseCode: http.StatusInternalServerError,
+			}).Then(test.ExpectedCall{
+				Path:         "/v1/chain/get_account",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getaccount.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getaccount_unstaked.json", addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balance, err := client.GetBalance(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance.Data.Assets).To(Equal([]transport.Asset{
+				{Asset: "EOS", Balance: "12.3400", Locked: "0.0000"},
+			}))
+		})
+	})
+
+	Describe("#GetResources", func() {
+		It("Should return the cpu, net and ram of the account", func() {
+			addr := "eospaceioeos"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/v1/chain/get_account",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getaccount.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getaccount_resources.json", addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(*EosClient).GetResources(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Resources).To(Equal([]transport.Resource{
+				{Resource: "cpu", Unit: "us", Used: 3837, Available: 14098, Max: 17935, Staked: "2.5000"},
+				{Resource: "net", Unit: "bytes", Used: 128, Available: 94716, Max: 94844, Staked: "1.0000"},
+				{Resource: "ram", Unit: "bytes", Used: 3446, Available: 2021, Max: 5467},
+			}))
+		})
+
+		It("Should return unlimited resources of a privileged account as -1 without a stake", func() {
+			addr := "eosio"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/v1/chain/get_account",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("eos/req/getaccount.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("eos/res/getaccount_unstaked.json", addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(*EosClient).GetResources(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Resources).To(Equal([]transport.Resource{
+				{Resource: "cpu", Unit: "us", Used: -1, Available: -1, Max: -1},
+				{Resource: "net", Unit: "bytes", Used: -1, Available: -1, Max: -1},
+				{Resource: "ram", Unit: "bytes", Used: 1832957, Available: -1, Max: -1},
+			}))
+		})
+	})
+
 	Describe("#GetInfo", func() {
 		It("Should return the Eos node information transformed to the common output", func() {
 			bestBlockHash := "0024d87f2415c674f166389ba9abba4152053ac8100767ac4a06d9a8c4ab905a"
@@ -121,15 +250,66 @@ var _ = Describe("EosClient", func() {
 						"ID":    Equal(txID),
 						"From":  Equal("cryptkeeper"),
 						"To":    Equal("brandon"),
-						"Value": Equal("42.0000 EOS"),
+						"Value": Equal("42.0000"),
 						"Confirmations": MatchAllFields(Fields{
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(15))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
+						}),
+						"Transfers": Equal([]transport.Transfer{
+							{From: "cryptkeeper", To: "brandon", Value: "42.0000"},
 						}),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           Equal("the grasshopper lies heavy"),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
+
+		Context("With inline actions", func() {
+			It("Should return every transfer executed by the token contracts", func() {
+				txID := "9a1c0c2e3c2d6f0e4b6a7d1f3b5e8c0a2d4f6b8e0c2a4e6f8b0d2c4e6a8f0b2d"
+				client = &EosClient{
+					Client:         eos.New(mockServer.HttpTest.URL),
+					TokenContracts: []string{"eosdtsttoken"},
+				}
+
+				mockServer.Expect(test.ExpectedCall{
+					Path:         "/v1/history/get_transaction",
+					Method:       "POST",
+					Body:         MustLoad(fb.LoadFixture("eos/req/gettransaction.json", txID)),
+					Response:     MustLoad(fb.LoadFixture("eos/res/gettransaction_traces.json", txID)),
+					ResponseCode: http.StatusOK,
+				}).Then(test.ExpectedCall{
+					Path:         "/v1/chain/get_info",
+					Method:       "POST",
+					Response:     MustLoad(fb.LoadFixture("eos/res/getinfo.json", "0024d87f2415c674f166389ba9abba4152053ac8100767ac4a06d9a8c4ab905a")),
+					ResponseCode: http.StatusOK,
+				})
+
+				tx, err := client.GetTransactionByHash(txID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tx.Data.Transaction).To(MatchFields(IgnoreExtras, Fields{
+					"From":  Equal("dappcontract"),
+					"To":    Equal("cryptkeeper"),
+					"Value": Equal("1.2500"),
+					"Memo":  Equal("withdrawal 1042"),
+				}))
+
+				// the notification of the receiver, the repeated trace and the transfer of an unknown contract are left out.
+				Expect(tx.Data.Transaction.Transfers).To(Equal([]transport.Transfer{
+					{From: "dappcontract", To: "cryptkeeper", Value: "1.2500"},
+					{From: "dappcontract", To: "cryptkeeper", Value: "100.000000000", Asset: "EOSDT", Issuer: "eosdtsttoken"},
+				}))
+			})
+		})
 	})
 })
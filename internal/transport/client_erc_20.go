@@ -1,23 +1,15 @@
 package transport
 
 import (
-	"bytes"
 	"context"
-	"encoding/hex"
 	"fmt"
-	"log"
 	"math/big"
-	"net/http"
-	"net/url"
-	"os"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
@@ -41,77 +33,91 @@ var (
 		TetherAssetID: {
 			AssetID:      TetherAssetID,
 			ContractAddr: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Decimals:     6,
 		},
 		OxAssetID: {
 			AssetID:      OxAssetID,
 			ContractAddr: "0xE41d2489571d322189246DaFA5ebDe1F4699F498",
+			Decimals:     18,
 		},
 		BATAssetID: {
 			AssetID:      BATAssetID,
 			ContractAddr: "0x0D8775F648430679A709E98d2b0Cb6250d2887EF",
+			Decimals:     18,
 		},
 		ChainLinkAssetID: {
 			AssetID:      ChainLinkAssetID,
 			ContractAddr: "0x514910771AF9Ca656af840dff83E8264EcF986CA",
+			Decimals:     18,
 		},
 		IconAssetID: {
 			AssetID:      IconAssetID,
 			ContractAddr: "0xb5a5f22694352c15b00323844ad545abb2b11028",
+			Decimals:     18,
 		},
 		MakerAssetID: {
 			AssetID:      MakerAssetID,
 			ContractAddr: "0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2",
+			Decimals:     18,
 		},
 		OmiseGoAssetID: {
 			AssetID:      OmiseGoAssetID,
 			ContractAddr: "0xd26114cd6EE289AccF82350c8d8487fedB8A0C07",
+			Decimals:     18,
 		},
 		VeChainAssetID: {
 			AssetID:      VeChainAssetID,
 			ContractAddr: "0xd850942ef8811f2a866692a623011bde52a462c1",
+			Decimals:     18,
 		},
 		ZilliqaAssetID: {
 			AssetID:      ZilliqaAssetID,
 			ContractAddr: "0x05f4a42e251f2d52b8ed15E9FEdAacFcEF1FAD27",
+			Decimals:     12,
 		},
 	}
 
-	// this is the same as writing balanceOf(address) for the contract
-	balanceOfEncStr = "0x70a08231"
-	etherScan, _    = url.Parse("https://api.etherscan.io")
+	// erc20ABI is the standard ERC20 interface shared by every token contract, used to decode transfers
+	// without looking up the ABI of each contract.
+	erc20ABI = mustParseABI(erc20ABIJSON)
 )
 
+const erc20ABIJSON = `[
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},
+	{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},
+	{"constant":false,"inputs":[{"name":"_from","type":"address"},{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},
+	{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"payable":false,"stateMutability":"view","type":"function"},
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"payable":false,"stateMutability":"view","type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_from","type":"address"},{"indexed":true,"name":"_to","type":"address"},{"indexed":false,"name":"_value","type":"uint256"}],"name":"Transfer","type":"event"}
+]`
+
 // ERC20Config defines a struct to hold run parameters for an erc 20 coin.
 type ERC20Config struct {
-	AssetID      string
-	ContractAddr string
+	AssetID      string `json:"assetId"`
+	ContractAddr string `json:"contractAddr"`
+	// Decimals is the number of decimals the token contract uses to represent a single token.
+	Decimals int `json:"decimals"`
+	// Chain is the chain the contract is deployed on, either ethereum or ethereumclassic. Empty means ethereum.
+	Chain string `json:"chain,omitempty"`
+	// Network is the network of the chain the contract is deployed on, e.g. testnet. Empty means mainnet.
+	Network string `json:"network,omitempty"`
 }
 
 // ERC20ContractTxData holds information about the contract token transfer
 // this is decoded from a transaction input.
 type ERC20ContractTxData struct {
-	Token  common.Address
-	To     common.Address
-	Value  *big.Int
-	Amount *big.Int
+	From  common.Address
+	To    common.Address
+	Value *big.Int
 }
 
 // ERC20Client is the ERC20 implementation of the CoinClient
 type ERC20Client struct {
 	AssetID      string
 	ContractAddr *common.Address
-	EthClient    *ethclient.Client
-	ABIClient    transport.BaseClient
-
-	ABIMap map[string]abi.ABI
-	MU     *sync.Mutex
-}
-
-// ABIResult maps the JSON result from an ABI lookup on etherscan.
-type ABIResult struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  string `json:"result"`
+	// Decimals is the number of decimals balances are formatted with, those of the token's config.
+	Decimals  int
+	EthClient *ethclient.Client
 }
 
 // NewERC20Client returns a new client using os variables.
@@ -121,7 +127,12 @@ func NewERC20Client(token string) (*ERC20Client, error) {
 		return nil, fmt.Errorf("ERC20 token: %s not found, please add to config map", token)
 	}
 
-	ethRpc, err := ethclient.Dial(getNodeURL("ETHEREUM_URL"))
+	env, ok := erc20NodeEnv[erc20Chain(config)]
+	if !ok {
+		return nil, fmt.Errorf("ERC20 token: %s is deployed on unknown chain: %s", token, config.Chain)
+	}
+
+	ethRpc, err := ethclient.Dial(getNodeURL(networkEnv(env, erc20Network(config)) + "_URL"))
 	if err != nil {
 		return nil, errors.Wrap(err, "error initializing base ethereum client for erc20 client")
 	}
@@ -131,22 +142,21 @@ func NewERC20Client(token string) (*ERC20Client, error) {
 	return &ERC20Client{
 		AssetID:      config.AssetID,
 		ContractAddr: &addr,
+		Decimals:     config.Decimals,
 		EthClient:    ethRpc,
-		ABIClient: transport.BaseClient{
-			BaseURL: etherScan,
-			Client:  &http.Client{Timeout: time.Second * time.Duration(10)},
-			Log:     log.New(os.Stdout, "", log.LstdFlags),
-		},
-		ABIMap: map[string]abi.ABI{},
-		MU:     &sync.Mutex{},
 	}, nil
 }
 
-// GetInfo attempts to get standardised coin info from multiple rpc calls.
+// GetInfo reports the network of the token's node.
 func (e ERC20Client) GetInfo() (*transport.CoinState, error) {
+	chain, err := e.EthClient.NetworkID(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching network id for erc20 node")
+	}
+
 	return &transport.CoinState{
 		Data: transport.CoinData{
-			Chain:        "main",
+			Chain:        chain.String(),
 			CurrentBlock: "ERC20",
 		},
 	}, nil
@@ -154,33 +164,36 @@ func (e ERC20Client) GetInfo() (*transport.CoinState, error) {
 
 // GetBalance returns the balance of the address.
 func (e ERC20Client) GetBalance(addr string) (*transport.Balance, error) {
-	data, _ := hexutil.Decode(balanceOfEncStr + "000000000000000000000000" + transport.StripHex(addr))
-	msg := ethereum.CallMsg{
-		To:   e.ContractAddr,
-		Data: data,
-	}
+	return e.balanceAt(addr, nil)
+}
 
-	b, err := e.EthClient.CallContract(context.Background(), msg, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error fetching contract balance for contract")
-	}
+// GetBalanceAt returns the balance of the address at the end of the block at height by calling the contract
+// against that block's state.
+func (e ERC20Client) GetBalanceAt(addr string, height int64) (*transport.Balance, error) {
+	return e.balanceAt(addr, big.NewInt(height))
+}
 
-	if len(b) == 0 {
-		return nil, errors.New("error fetching contract balance, returned nil bytes slice")
+// GetBlockTime returns the time the block at height was mined.
+func (e ERC20Client) GetBlockTime(height int64) (time.Time, error) {
+	header, err := e.EthClient.HeaderByNumber(context.Background(), big.NewInt(height))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "error getting header of block: %d", height)
 	}
 
-	buf := bytes.NewBuffer([]byte("0x"))
-	hexString := hexutil.Bytes(b).String()
+	return time.Unix(int64(header.Time), 0).UTC(), nil
+}
 
-	for _, value := range transport.StripHex(hexString) {
-		if value != 48 {
-			buf.WriteRune(value)
-		}
+// balanceAt calls balanceOf on the contract at the given block number, nil being the latest block, formatting
+// the balance in whole tokens.
+func (e ERC20Client) balanceAt(addr string, number *big.Int) (*transport.Balance, error) {
+	var res *big.Int
+	if err := callERC20(e.EthClient, *e.ContractAddr, number, &res, "balanceOf", common.HexToAddress(addr)); err != nil {
+		return nil, errors.Wrapf(err, "error fetching contract balance of addr: %s", addr)
 	}
 
-	res, err := hexutil.DecodeBig(buf.String())
+	balance, err := transport.FormatUnits(res.String(), e.Decimals)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error decoding balance string: %s, original hex: %s", buf.String(), hexString)
+		return nil, err
 	}
 
 	return &transport.Balance{
@@ -188,115 +201,191 @@ func (e ERC20Client) GetBalance(addr string) (*transport.Balance, error) {
 			Assets: []transport.Asset{
 				{
 					Asset:   e.AssetID,
-					Balance: res.String(),
+					Balance: balance,
 				},
 			},
 		},
 	}, nil
 }
 
-// GetTransactionByHash returns the transaction stored at the given hash.
+// GetTransactionByHash returns the transfers of the token in the transaction stored at the given hash, decoded
+// from the Transfer events in its receipt. This covers transferFrom, contract wallets and any other transaction
+// which moves the token indirectly. Pending transactions have no receipt so their input is decoded instead.
 func (e *ERC20Client) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
-	block, err := e.EthClient.BlockByNumber(context.Background(), nil)
-	if err != nil {
-		return nil, err
-	}
+	ctx := context.Background()
 
-	tx, _, err := e.EthClient.TransactionByHash(context.Background(), common.HexToHash(hash))
+	txHash := common.HexToHash(hash)
+	tx, isPending, err := e.EthClient.TransactionByHash(ctx, txHash)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting transaction for hash: %s", hash)
 	}
 
-	chainId, _ := e.EthClient.ChainID(context.Background())
-	msg, err := tx.AsMessage(types.NewEIP155Signer(chainId))
+	if isPending {
+		transfer, err := e.decodeTransferCall(tx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding pending transaction: %s", hash)
+		}
+
+		return erc20TransactionResp(hash, []transport.Transfer{*transfer}, transport.PendingConfirmations()), nil
+	}
+
+	// the receipt is looked up by the requested hash, tx.Hash() is re-derived from the decoded fields and
+	// doesn't match for transaction types the client can't encode the way the node does.
+	r, err := e.EthClient.TransactionReceipt(ctx, txHash)
 	if err != nil {
-		return nil, errors.Wrap(err, "error converting eth transaction to message")
+		return nil, errors.Wrapf(err, "error getting receipt for hash: %s", hash)
 	}
 
-	r, err := e.EthClient.TransactionReceipt(context.Background(), tx.Hash())
+	transfers, err := e.decodeTransferLogs(r.Logs)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "error decoding transfer logs of transaction: %s", hash)
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("transaction: %s has no %s transfers", hash, e.AssetID)
 	}
 
-	txABI, err := e.findABI(tx.To())
+	head, err := e.EthClient.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "error getting latest block header")
 	}
 
-	confirmed := block.Number().Int64() - r.BlockNumber.Int64()
+	confirmed := head.Number.Int64() - r.BlockNumber.Int64()
 
-	txInput := hexutil.Bytes(tx.Data()).String()
-	decodedSig, _ := hex.DecodeString(txInput[2:10])
+	return erc20TransactionResp(hash, transfers, transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: confirmed >= *transport.ConfirmThresholdValue,
+		Value:     &confirmed,
+	}), nil
+}
+
+// decodeTransferLogs returns the transfers of the client's token from the Transfer events in logs, events
+// emitted by other contracts and ERC721 transfers, which index the token id, are skipped.
+func (e *ERC20Client) decodeTransferLogs(logs []*types.Log) ([]transport.Transfer, error) {
+	event := erc20ABI.Events["Transfer"]
 
-	method, err := txABI.MethodById(decodedSig)
+	var transfers []transport.Transfer
+	for _, l := range logs {
+		if l.Address != *e.ContractAddr || len(l.Topics) != 3 || l.Topics[0] != event.ID {
+			continue
+		}
+
+		var data ERC20ContractTxData
+		if err := erc20ABI.UnpackIntoInterface(&data, "Transfer", l.Data); err != nil {
+			return nil, errors.Wrapf(err, "error unpacking transfer log: %d", l.Index)
+		}
+
+		transfers = append(transfers, transport.Transfer{
+			From:  common.BytesToAddress(l.Topics[1].Bytes()).String(),
+			To:    common.BytesToAddress(l.Topics[2].Bytes()).String(),
+			Value: data.Value.String(),
+		})
+	}
+
+	return transfers, nil
+}
+
+// decodeTransferCall decodes a transfer or transferFrom call made directly to the client's token contract.
+func (e *ERC20Client) decodeTransferCall(tx *types.Transaction) (*transport.Transfer, error) {
+	if tx.To() == nil || *tx.To() != *e.ContractAddr || len(tx.Data()) < 4 {
+		return nil, fmt.Errorf("transaction is not a call to the %s contract", e.AssetID)
+	}
+
+	method, err := erc20ABI.MethodById(tx.Data()[:4])
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting abi method by id")
 	}
 
-	decodedData, _ := hex.DecodeString(txInput[10:])
-
-	var data ERC20ContractTxData
-	err = method.Inputs.Unpack(&data, decodedData)
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
 	if err != nil {
 		return nil, errors.Wrap(err, "error unpacking transaction data")
 	}
 
-	to := data.To.String()
-	if len(data.To.Bytes()) == 0 {
-		to = data.Token.String()
+	var data ERC20ContractTxData
+	if err := method.Inputs.Copy(&data, args); err != nil {
+		return nil, errors.Wrap(err, "error copying transaction data")
 	}
 
-	var value string
-	if data.Value == nil {
-		value = data.Amount.String()
-	} else {
-		value = data.Value.String()
+	switch method.Name {
+	case "transfer":
+		chainID, err := e.EthClient.ChainID(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting chain id")
+		}
+
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error recovering transaction sender")
+		}
+
+		data.From = sender
+	case "transferFrom":
+	default:
+		return nil, fmt.Errorf("method: %s does not transfer tokens", method.Name)
 	}
 
+	return &transport.Transfer{
+		From:  data.From.String(),
+		To:    data.To.String(),
+		Value: data.Value.String(),
+	}, nil
+}
+
+func erc20TransactionResp(hash string, transfers []transport.Transfer, confirmations transport.Confirmations) *transport.TransactionResp {
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  msg.From().String(),
-				To:    to,
-				Value: value,
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: confirmed >= *transport.ConfirmThresholdValue,
-					Value:     &confirmed,
-				},
+				ID:            hash,
+				From:          transfers[0].From,
+				To:            transfers[0].To,
+				Value:         transfers[0].Value,
+				Confirmations: confirmations,
+				Transfers:     transfers,
 			},
 		},
-	}, nil
+	}
 }
 
-func (e *ERC20Client) findABI(contract *common.Address) (abi.ABI, error) {
-	e.MU.Lock()
-	defer e.MU.Unlock()
+// callERC20 calls the ERC20 method on the token contract at the given block number, nil being the latest block,
+// unpacking its abi encoded output into out.
+func callERC20(client *ethclient.Client, token common.Address, number *big.Int, out interface{}, method string, args ...interface{}) error {
+	return callContract(client, erc20ABI, token, number, out, method, args...)
+}
 
-	addr := contract.String()
+// callContract calls the method of contractABI on the contract, unpacking its output into out.
+func callContract(client *ethclient.Client, contractABI abi.ABI, contract common.Address, number *big.Int, out interface{}, method string, args ...interface{}) error {
+	res, err := rawCallContract(client, contractABI, contract, number, method, args...)
+	if err != nil {
+		return err
+	}
 
-	if txABI, ok := e.ABIMap[addr]; ok {
-		return txABI, nil
+	// calls to an address without code succeed with no output.
+	if len(res) == 0 {
+		return fmt.Errorf("%s call returned no output, contract: %s may not exist", method, contract.String())
 	}
 
-	var abiRes ABIResult
-	err := e.ABIClient.GET("/api", map[string]string{
-		"module":  "contract",
-		"action":  "getabi",
-		"address": addr,
-	}, &abiRes)
+	return contractABI.UnpackIntoInterface(out, method, res)
+}
+
+func rawCallContract(client *ethclient.Client, contractABI abi.ABI, contract common.Address, number *big.Int, method string, args ...interface{}) ([]byte, error) {
+	data, err := contractABI.Pack(method, args...)
 	if err != nil {
-		return abi.ABI{}, errors.Wrap(err, "error getting abi for contract")
+		return nil, errors.Wrapf(err, "error packing %s call", method)
 	}
 
-	txABI, err := abi.JSON(strings.NewReader(abiRes.Result))
+	return client.CallContract(context.Background(), ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, number)
+}
+
+func mustParseABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
 	if err != nil {
-		return abi.ABI{}, errors.Wrap(err, "error parsing ABI json")
+		panic(err)
 	}
 
-	e.ABIMap[addr] = txABI
-	return txABI, nil
+	return parsed
 }
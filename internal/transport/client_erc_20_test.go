@@ -1,20 +1,20 @@
 package transport_test
 
 import (
+	"fmt"
 	"math/big"
+	"math/rand"
 	"net/http"
-	"net/url"
 	"os"
 	"path"
-	"sync"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
@@ -27,8 +27,7 @@ var _ = Describe("ERC20Client", func() {
 		fb     *test.FixtureBox
 		client transport.CoinClient
 
-		mockServer      *test.Server
-		etherScanServer *test.Server
+		mockServer *test.Server
 	)
 
 	BeforeEach(func() {
@@ -40,7 +39,6 @@ var _ = Describe("ERC20Client", func() {
 		}
 
 		mockServer = test.NewTestServer(GinkgoT())
-		etherScanServer = test.NewTestServer(GinkgoT())
 
 		ethC, err := ethclient.Dial(mockServer.HttpTest.URL)
 		Expect(err).ToNot(HaveOccurred())
@@ -48,34 +46,28 @@ var _ = Describe("ERC20Client", func() {
 		address := common.HexToAddress(ERC20Tokens[TetherAssetID].ContractAddr)
 		Expect(err).ToNot(HaveOccurred())
 
-		u, _ := url.Parse(etherScanServer.HttpTest.URL)
-
 		client = &ERC20Client{
 			AssetID:      ERC20Tokens[TetherAssetID].AssetID,
 			ContractAddr: &address,
+			Decimals:     ERC20Tokens[TetherAssetID].Decimals,
 			EthClient:    ethC,
-			ABIClient: transport.BaseClient{
-				BaseURL: u,
-				Client:  http.DefaultClient,
-			},
-			ABIMap: map[string]abi.ABI{},
-			MU:     &sync.Mutex{},
 		}
 	})
 
 	AfterEach(func() {
 		mockServer.Close()
-		etherScanServer.Close()
 	})
 
 	Describe("#GetInfo", func() {
 		It("Should return the Tether node information transformed to the common output", func() {
+			mockServer.Expect(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/net_version.json")), MustLoad(fb.LoadFixture("ethereum/res/net_version.json"))))
+
 			info, err := client.GetInfo()
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(info).To(PointTo(MatchAllFields(Fields{
 				"Data": MatchAllFields(Fields{
-					"Chain":        Equal("main"),
+					"Chain":        Equal("3"),
 					"CurrentBlock": Equal("ERC20"),
 					"BlockHeight":  BeZero(),
 				}),
@@ -98,7 +90,7 @@ var _ = Describe("ERC20Client", func() {
 				Headers: map[string]string{
 					"Content-Type": "Application/Json",
 				},
-				Body:         MustLoad(fb.LoadFixture("erc20/req/getbalance.json", contractAddr, data)),
+				Body:         MustLoad(fb.LoadFixture("erc20/req/getbalance.json", contractAddr, data, 1)),
 				Response:     MustLoad(fb.LoadFixture("erc20/res/getbalance.json", balRes)),
 				ResponseCode: http.StatusOK,
 			})
@@ -110,8 +102,12 @@ var _ = Describe("ERC20Client", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("USDT"),
-							"Balance": Equal("150840600"),
+							"Asset":       Equal("USDT"),
+							"Balance":     Equal("150.8406"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -126,22 +122,10 @@ var _ = Describe("ERC20Client", func() {
 			currentBlockHeight := big.NewInt(8027418)
 			to := "0xdac17f958d2ee523a2206206994597c13d831ec7"
 
-			mockServer.Expect(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getBlockByNumber.json", 1)), MustLoad(fb.LoadFixture("erc20/res/eth_getBlockByNumber.json", hexutil.EncodeBig(currentBlockHeight))))).
-				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionByHash.json", txID)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionByHash.json", txID, to)))).
-				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_chainId.json")), MustLoad(fb.LoadFixture("erc20/res/eth_chainId.json")))).
-				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionReceipt.json", txID)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionReceipt.json", hexutil.EncodeBig(blockNumber)))))
-
-			etherScanServer.Expect(test.ExpectedCall{
-				Path:         "/api",
-				Method:       http.MethodGet,
-				QueryParams: map[string]string{
-					"module":  "contract",
-					"action":  "getabi",
-					"address": to,
-				},
-				Response:     MustLoad(fb.LoadFixture("erc20/res/contract_abi.json")),
-				ResponseCode: http.StatusOK,
-			})
+			mockServer.Expect(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionByHash.json", txID, 1)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionByHash.json", txID, to)))).
+				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionReceipt.json", txID, 2)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionReceipt.json", hexutil.EncodeBig(blockNumber))))).
+				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getBlockByNumber.json", 3)), MustLoad(fb.LoadFixture("erc20/res/eth_getBlockByNumber.json", hexutil.EncodeBig(currentBlockHeight)))))
+
 			tx, err := client.GetTransactionByHash(txID)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -156,10 +140,156 @@ var _ = Describe("ERC20Client", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(6))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": ConsistOf(
+							MatchAllFields(Fields{
+								"From":    Equal("0x59C9cBb043aE0c437676cCfB2c143073c2E2B359"),
+								"To":      Equal("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+								"Value":   Equal("5000000"),
+								"Asset":   BeEmpty(),
+								"TokenID": BeEmpty(),
+								"Issuer":  BeEmpty(),
+							}),
+						),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetTransactionByHash with several transfers", func() {
+		It("Should return every transfer of the token emitted in the transaction", func() {
+			txID := "0xeeb74ccde78183e6468376f76d7670f1a8eeaa1f13ae2152f7c8afe6b5f51125"
+			blockNumber := big.NewInt(8027412)
+			currentBlockHeight := big.NewInt(8027418)
+			wallet := "0x1111111111111111111111111111111111111111"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionByHash.json", txID, 1)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionByHash.json", txID, wallet)))).
+				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionReceipt.json", txID, 2)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionReceipt_multi.json", hexutil.EncodeBig(blockNumber))))).
+				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getBlockByNumber.json", 3)), MustLoad(fb.LoadFixture("erc20/res/eth_getBlockByNumber.json", hexutil.EncodeBig(currentBlockHeight)))))
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx.Data.Transaction.From).To(Equal("0x59C9cBb043aE0c437676cCfB2c143073c2E2B359"))
+			Expect(tx.Data.Transaction.To).To(Equal(wallet))
+			Expect(tx.Data.Transaction.Value).To(Equal("5000000"))
+			Expect(tx.Data.Transaction.Transfers).To(Equal([]transport.Transfer{
+				{
+					From:  "0x59C9cBb043aE0c437676cCfB2c143073c2E2B359",
+					To:    wallet,
+					Value: "5000000",
+				},
+				{
+					From:  wallet,
+					To:    "0x2222222222222222222222222222222222222222",
+					Value: "1000000",
+				},
+			}))
+		})
+	})
+
+	Describe("#GetTransactionByHash with a pending transaction", func() {
+		It("Should decode the transfer from the transaction input", func() {
+			txID := "0xeeb74ccde78183e6468376f76d7670f1a8eeaa1f13ae2152f7c8afe6b5f51125"
+			to := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+
+			mockServer.Expect(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_getTransactionByHash.json", txID, 1)), MustLoad(fb.LoadFixture("erc20/res/eth_getTransactionByHash_pending.json", txID, to)))).
+				Then(test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("erc20/req/eth_chainId.json", 2)), MustLoad(fb.LoadFixture("erc20/res/eth_chainId.json"))))
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx.Data.Transaction.From).To(Equal("0x59C9cBb043aE0c437676cCfB2c143073c2E2B359"))
+			Expect(tx.Data.Transaction.To).To(Equal("0xdAC17F958D2ee523a2206206994597C13D831ec7"))
+			Expect(tx.Data.Transaction.Value).To(Equal("5000000"))
+			Expect(tx.Data.Transaction.Confirmations).To(Equal(transport.PendingConfirmations()))
+		})
+	})
+
+	Describe("#GetBalance decoding", func() {
+		addr := "0xd6bd8F134262109E36EA70ee89548B0Bc8bF6D0c"
+		contractAddr := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+		data := "0x70a08231000000000000000000000000d6bd8f134262109e36ea70ee89548b0bc8bf6d0c"
+
+		maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+		expectBalances := func(balances ...*big.Int) {
+			for i, b := range balances {
+				mockServer.Expect(test.ExpectedCall{
+					Path:   "/",
+					Method: "POST",
+					Headers: map[string]string{
+						"Content-Type": "Application/Json",
+					},
+					Body:         MustLoad(fb.LoadFixture("erc20/req/getbalance.json", contractAddr, data, i+1)),
+					Response:     MustLoad(fb.LoadFixture("erc20/res/getbalance.json", fmt.Sprintf("0x%064x", b))),
+					ResponseCode: http.StatusOK,
+				})
+			}
+		}
+
+		DescribeTable("Should decode the uint256 returned by balanceOf",
+			func(balance *big.Int, expected string) {
+				expectBalances(balance)
+
+				res, err := client.GetBalance(addr)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Data.Assets[0].Balance).To(Equal(expected))
+			},
+			Entry("zero", big.NewInt(0), "0"),
+			Entry("interior zero digits", big.NewInt(0x100), "0.000256"),
+			Entry("trailing zero digits", big.NewInt(0x10203000), "270.544896"),
+			Entry("larger than int64", new(big.Int).Lsh(big.NewInt(1), 100), "1267650600228229401496703.205376"),
+			Entry("max uint256", maxUint256, "115792089237316195423570985008687907853269984665640564039457584007913129.639935"),
+		)
+
+		It("Should round trip random balances", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+
+			balances := make([]*big.Int, 50)
+			for i := range balances {
+				// spread the balances over every size of uint256 rather than mostly huge numbers.
+				limit := new(big.Int).Lsh(big.NewInt(1), uint(r.Intn(256)+1))
+				balances[i] = new(big.Int).Rand(r, limit)
+			}
+
+			expectBalances(balances...)
+
+			for _, b := range balances {
+				res, err := client.GetBalance(addr)
+				Expect(err).ToNot(HaveOccurred())
+
+				// the balance is formatted in whole tokens and parses back to the amount the contract returned.
+				raw, err := transport.ParseUnits(res.Data.Assets[0].Balance, ERC20Tokens[TetherAssetID].Decimals)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(raw).To(Equal(b.String()))
+			}
+		})
+
+		It("Should error when the contract returns no output", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("erc20/req/getbalance.json", contractAddr, data, 1)),
+				Response:     MustLoad(fb.LoadFixture("erc20/res/getbalance.json", "0x")),
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.GetBalance(addr)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
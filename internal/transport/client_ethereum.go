@@ -2,32 +2,67 @@ package transport
 
 import (
 	"context"
+	"math/big"
+	"os"
+	"regexp"
+	"time"
+
 	"github.com/hugorut/coins-oracle/pkg/transport"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 )
 
 var (
 	EthereumAssetID = "ETH"
+
+	ethHashReg = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
 )
 
 // EthereumClient is the ethereum implementation of the CoinClient
 type EthereumClient struct {
 	AssetID string
 	Client  *ethclient.Client
+	// RPC is the raw rpc connection underlying Client, used for calls ethclient doesn't expose.
+	RPC *rpc.Client
+	// WSURL is the node's websocket endpoint used for subscriptions, which aren't available over http.
+	WSURL string
+	// TraceMode opts in to tracing transactions for the value moved by the calls they make, see TraceModeDebug
+	// and TraceModeParity. It is empty by default as tracing is expensive and not every node serves it.
+	TraceMode string
 }
 
 // NewEthereumClient returns a new client using the rpc endpoint given in os.
 func NewEthereumClient() (*EthereumClient, error) {
-	ethRpc, err := ethclient.Dial(getNodeURL("ETHEREUM_URL"))
+	return newEthereumClient(EthereumAssetID, "ETHEREUM", MainNet)
+}
+
+// newEthereumClient returns a client of the asset using the node configured by the os variables
+// prefixed with the coin and network.
+func newEthereumClient(assetID, coin, network string) (*EthereumClient, error) {
+	env := networkEnv(coin, network)
+
+	traceMode := os.Getenv(env + "_TRACE_MODE")
+	if err := validTraceMode(traceMode); err != nil {
+		return nil, err
+	}
+
+	rpcClient, err := rpc.Dial(getNodeURL(env + "_URL"))
 	if err != nil {
 		return nil, err
 	}
 
-	return &EthereumClient{AssetID: EthereumAssetID, Client: ethRpc}, nil
+	return &EthereumClient{
+		AssetID:   assetID,
+		Client:    ethclient.NewClient(rpcClient),
+		RPC:       rpcClient,
+		WSURL:     os.Getenv(env + "_WS_URL"),
+		TraceMode: traceMode,
+	}, nil
 }
 
 // GetInfo attempts to get standardised coin info from multiple rpc calls.
@@ -37,25 +72,134 @@ func (e EthereumClient) GetInfo() (*transport.CoinState, error) {
 		return nil, errors.Wrap(err, "error fetching network id for ethereum node")
 	}
 
-	current, err := e.Client.BlockByNumber(context.Background(), nil)
+	current, err := e.Client.HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "error fetching latest block number for ethereum node")
 	}
 
-	hash := current.ReceiptHash().String()
 	return &transport.CoinState{
 		Data: transport.CoinData{
 			Chain:        chain.String(),
-			BlockHeight:  int(current.Number().Int64()),
-			CurrentBlock: hash,
+			BlockHeight:  int(current.Number.Int64()),
+			CurrentBlock: current.Hash().String(),
 		},
 	}, nil
 }
 
+// SubscribeBlocks subscribes to new chain heads over the node's websocket endpoint,
+// sending the chain info for every new block.
+func (e EthereumClient) SubscribeBlocks(ctx context.Context, blocks chan<- transport.CoinData) error {
+	if e.WSURL == "" {
+		return transport.ErrSubscriptionNotSupported
+	}
+
+	client, err := ethclient.DialContext(ctx, e.WSURL)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to ethereum websocket endpoint")
+	}
+	defer client.Close()
+
+	chain, err := client.NetworkID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error fetching network id for ethereum node")
+	}
+
+	heads := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return errors.Wrap(err, "error subscribing to new ethereum heads")
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case head := <-heads:
+			select {
+			case blocks <- transport.CoinData{
+				Chain:        chain.String(),
+				BlockHeight:  int(head.Number.Int64()),
+				CurrentBlock: head.Hash().String(),
+			}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err := <-sub.Err():
+			return errors.Wrap(err, "ethereum head subscription failed")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GetBalance returns the balance of the address at the latest block, along with the change pending in the txpool.
 func (e EthereumClient) GetBalance(addr string) (*transport.Balance, error) {
-	am, err := e.Client.BalanceAt(context.Background(), common.HexToAddress(addr), nil)
+	return e.GetConfirmedBalance(addr, 1)
+}
+
+// GetConfirmedBalance returns the balance of the address at the block minConf - 1 below the latest one, the
+// difference to the pending balance is returned as unconfirmed. A minConf of 0 returns the pending balance.
+func (e EthereumClient) GetConfirmedBalance(addr string, minConf int) (*transport.Balance, error) {
+	ctx := context.Background()
+	account := common.HexToAddress(addr)
+
+	// a nil block number is the latest block.
+	var number *big.Int
+	if minConf > 1 {
+		head, err := e.Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting latest block header")
+		}
+
+		number = new(big.Int).Sub(head.Number, big.NewInt(int64(minConf-1)))
+		if number.Sign() < 0 {
+			number.SetInt64(0)
+		}
+	}
+
+	var confirmed *big.Int
+	if minConf > 0 {
+		am, err := e.Client.BalanceAt(ctx, account, number)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting balance for addr: %s", addr)
+		}
+
+		confirmed = am
+	}
+
+	pending, err := e.Client.PendingBalanceAt(ctx, account)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting pending balance for addr: %s", addr)
+	}
+
+	if confirmed == nil {
+		confirmed = pending
+	}
+
+	assetID := e.AssetID
+	if assetID == "" {
+		assetID = EthereumAssetID
+	}
+
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{
+				{
+					Asset:       assetID,
+					Balance:     confirmed.String(),
+					Confirmed:   confirmed.String(),
+					Unconfirmed: new(big.Int).Sub(pending, confirmed).String(),
+				},
+			},
+		},
+	}, nil
+}
+
+// GetBalanceAt returns the balance of the address at the end of the block at height, which needs an archive node
+// for blocks older than the node's pruning window.
+func (e EthereumClient) GetBalanceAt(addr string, height int64) (*transport.Balance, error) {
+	am, err := e.Client.BalanceAt(context.Background(), common.HexToAddress(addr), big.NewInt(height))
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting balance for addr: %s", addr)
+		return nil, errors.Wrapf(err, "error getting balance for addr: %s at block: %d", addr, height)
 	}
 
 	assetID := e.AssetID
@@ -75,45 +219,272 @@ func (e EthereumClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
+// GetBlockTime returns the time the block at height was mined.
+func (e EthereumClient) GetBlockTime(height int64) (time.Time, error) {
+	header, err := e.Client.HeaderByNumber(context.Background(), big.NewInt(height))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "error getting header of block: %d", height)
+	}
+
+	return time.Unix(int64(header.Time), 0).UTC(), nil
+}
+
+// GetBalances returns the balances of all the addresses using a single JSON-RPC batch request. Like GetBalance
+// every address has its latest balance confirmed and the change pending in the txpool unconfirmed.
+func (e EthereumClient) GetBalances(addrs []string) (map[string]*transport.Balance, error) {
+	if e.RPC == nil {
+		return nil, errors.New("ethereum client has no rpc client configured for batch calls")
+	}
+
+	// every address has a latest balance call followed by a pending one.
+	results := make([]hexutil.Big, len(addrs)*2)
+	batch := make([]rpc.BatchElem, len(addrs)*2)
+	for key, addr := range addrs {
+		for i, block := range []string{"latest", "pending"} {
+			batch[key*2+i] = rpc.BatchElem{
+				Method: "eth_getBalance",
+				Args:   []interface{}{common.HexToAddress(addr), block},
+				Result: &results[key*2+i],
+			}
+		}
+	}
+
+	if err := e.RPC.BatchCallContext(context.Background(), batch); err != nil {
+		return nil, errors.Wrap(err, "error executing batch balance call")
+	}
+
+	assetID := e.AssetID
+	if assetID == "" {
+		assetID = EthereumAssetID
+	}
+
+	balances := make(map[string]*transport.Balance, len(addrs))
+	for key, addr := range addrs {
+		// addresses which errored are left out and reported as missing by the caller.
+		if batch[key*2].Error != nil || batch[key*2+1].Error != nil {
+			continue
+		}
+
+		confirmed := (*big.Int)(&results[key*2])
+		pending := (*big.Int)(&results[key*2+1])
+
+		balances[addr] = &transport.Balance{
+			Data: transport.BalanceData{
+				Assets: []transport.Asset{
+					{
+						Asset:       assetID,
+						Balance:     confirmed.String(),
+						Confirmed:   confirmed.String(),
+						Unconfirmed: new(big.Int).Sub(pending, confirmed).String(),
+					},
+				},
+			},
+		}
+	}
+
+	return balances, nil
+}
+
+// ethBlock represents a block returned by eth_getBlockByNumber or eth_getBlockByHash with only its transaction hashes.
+type ethBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         string         `json:"hash"`
+	ParentHash   string         `json:"parentHash"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	Transactions []string       `json:"transactions"`
+}
+
+// GetBlockByHeight returns the block at the given height of the main chain.
+func (e EthereumClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	return e.getBlock("eth_getBlockByNumber", hexutil.EncodeBig(big.NewInt(height)))
+}
+
+// GetBlockByHash returns the block with the given hash.
+func (e EthereumClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	if !ethHashReg.MatchString(hash) {
+		return nil, errors.Errorf("block hash: %s is not a 32 byte hex string", hash)
+	}
+
+	return e.getBlock("eth_getBlockByHash", hash)
+}
+
+// getBlock asks for the transaction hashes of the block rather than the transactions, which ethclient would decode
+// and fail on for transaction types it doesn't know.
+func (e EthereumClient) getBlock(method string, id string) (*transport.BlockResp, error) {
+	if e.RPC == nil {
+		return nil, errors.New("ethereum client has no rpc client configured for block calls")
+	}
+
+	var block *ethBlock
+	if err := e.RPC.CallContext(context.Background(), &block, method, id, false); err != nil {
+		return nil, errors.Wrapf(err, "error getting block: %s", id)
+	}
+
+	if block == nil {
+		return nil, errors.Errorf("block: %s was not found", id)
+	}
+
+	res := &transport.BlockResp{}
+	res.Data.Block = transport.Block{
+		Height:    int64(block.Number),
+		Hash:      block.Hash,
+		Parent:    block.ParentHash,
+		Timestamp: int64(block.Timestamp),
+		TxCount:   len(block.Transactions),
+		TxIDs:     block.Transactions,
+	}
+
+	return res, nil
+}
+
+// EthTxPoolStatus represents the result of a txpool_status call.
+type EthTxPoolStatus struct {
+	Pending hexutil.Uint `json:"pending"`
+	Queued  hexutil.Uint `json:"queued"`
+}
+
+// GetMempool returns the number of pending and queued transactions in the node's transaction pool.
+// Bucketing gas prices would mean listing the whole pool with txpool_content, so no fee histogram is reported.
+func (e EthereumClient) GetMempool() (*transport.MempoolResp, error) {
+	if e.RPC == nil {
+		return nil, errors.New("ethereum client has no rpc client configured for txpool calls")
+	}
+
+	var status EthTxPoolStatus
+	if err := e.RPC.CallContext(context.Background(), &status, "txpool_status"); err != nil {
+		return nil, errors.Wrap(err, "error getting txpool status")
+	}
+
+	res := &transport.MempoolResp{}
+	res.Data.Mempool = transport.Mempool{
+		Size:   int(status.Pending),
+		Queued: int(status.Queued),
+	}
+
+	return res, nil
+}
+
+// GetTransactionByHash returns the transaction with its gas fees, the sender being recovered with the latest
+// signer of the chain so that access list and dynamic fee transactions are supported alongside legacy ones.
+// Mined transactions which reverted are marked as such, as they moved none of their value. When the client has
+// a TraceMode the value moved by calls made within the transaction, e.g. by contract wallets, is listed too.
 func (e EthereumClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
-	block, err := e.Client.BlockByNumber(context.Background(), nil)
+	ctx := context.Background()
+
+	head, err := e.Client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "error getting latest block header")
 	}
 
-	tx, _, err := e.Client.TransactionByHash(context.Background(), common.HexToHash(hash))
+	txHash := common.HexToHash(hash)
+	tx, pending, err := e.Client.TransactionByHash(ctx, txHash)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting transaction for hash: %s", hash)
 	}
 
-	chainId, _ := e.Client.ChainID(context.Background())
-	msg, err := tx.AsMessage(types.NewEIP155Signer(chainId))
+	chainID, err := e.Client.ChainID(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "error converting eth transaction to message")
+		return nil, errors.Wrap(err, "error getting chain id")
 	}
 
-	r, err := e.Client.TransactionReceipt(context.Background(), tx.Hash())
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "error recovering transaction sender")
+	}
+
+	transaction := transport.Transaction{
+		ID:            hash,
+		From:          from.String(),
+		Value:         tx.Value().String(),
+		Confirmations: transport.PendingConfirmations(),
+		Fees:          gasFees(tx),
+	}
+
+	if tx.To() != nil {
+		transaction.To = tx.To().String()
+	}
+
+	// pending transactions have no receipt until they are mined.
+	if pending {
+		return ethereumTransactionResp(transaction), nil
+	}
+
+	r, err := e.Client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting receipt for transaction: %s", hash)
+	}
+
+	// the block's base fee sets the price paid by dynamic fee transactions.
+	block, err := e.Client.HeaderByNumber(ctx, r.BlockNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting header of block: %s", r.BlockNumber.String())
+	}
+
+	transaction.Reverted = r.Status == types.ReceiptStatusFailed
+	if !transaction.Reverted {
+		transaction.Transfers, err = e.internalTransfers(ctx, txHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting internal transfers of transaction: %s", hash)
+		}
+	}
+
+	nfts, err := decodeNFTTransfers(r.Logs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding nft transfers of transaction: %s", hash)
+	}
+
+	transaction.Transfers = append(transaction.Transfers, nfts...)
+
+	if tx.To() == nil {
+		transaction.To = r.ContractAddress.String()
+	}
+
+	transaction.Fees.GasUsed = r.GasUsed
+
+	if block.BaseFee != nil {
+		tip, err := tx.EffectiveGasTip(block.BaseFee)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting priority fee of transaction: %s", hash)
+		}
+
+		transaction.Fees.GasPrice = new(big.Int).Add(block.BaseFee, tip).String()
+		transaction.Fees.BaseFee = block.BaseFee.String()
+		transaction.Fees.PriorityFee = tip.String()
+	}
+
+	confirmed := head.Number.Int64() - r.BlockNumber.Int64()
+	transaction.Confirmations = transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: confirmed >= *transport.ConfirmThresholdValue,
+		Value:     &confirmed,
+	}
+
+	return ethereumTransactionResp(transaction), nil
+}
+
+// gasFees returns the gas pricing bid by the transaction. Legacy and access list transactions pay the gas price
+// they bid, whereas dynamic fee transactions only pay once the base fee of their block is known.
+func gasFees(tx *types.Transaction) *transport.GasFees {
+	fees := &transport.GasFees{
+		Type: int(tx.Type()),
 	}
 
-	confirmed := block.Number().Int64() - r.BlockNumber.Int64()
+	if tx.Type() == types.DynamicFeeTxType {
+		fees.MaxFee = tx.GasFeeCap().String()
+		fees.MaxPriorityFee = tx.GasTipCap().String()
+		return fees
+	}
+
+	fees.GasPrice = tx.GasPrice().String()
+	return fees
+}
 
+func ethereumTransactionResp(tx transport.Transaction) *transport.TransactionResp {
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
-			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  msg.From().String(),
-				To:    tx.To().String(),
-				Value: tx.Value().String(),
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: confirmed >= *transport.ConfirmThresholdValue,
-					Value:     &confirmed,
-				},
-			},
+			Transaction: tx,
 		},
-	}, nil
+	}
 }
@@ -10,12 +10,14 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
 	. "github.com/hugorut/coins-oracle/internal/transport"
 	"github.com/hugorut/coins-oracle/pkg/test"
+	"github.com/hugorut/coins-oracle/pkg/transport"
 )
 
 var _ = Describe("EthereumClient", func() {
@@ -25,6 +27,7 @@ var _ = Describe("EthereumClient", func() {
 		testAddr               = crypto.PubkeyToAddress(testKey.PublicKey)
 		testBalance            = big.NewInt(2e10)
 		fixtureTransactionHash = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+		fixtureBlockHash       = "0xd0e2bd0306856f12ca3bea5d203a43329e999c4866793414fb04f789d70ca07f"
 		resultingBlockHash     = "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"
 	)
 
@@ -39,11 +42,7 @@ var _ = Describe("EthereumClient", func() {
 
 	Describe("#GetBalance", func() {
 		It("Should conform rpc output to standard balance rep", func() {
-			reqBody, err := fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()))
-			Expect(err).ToNot(HaveOccurred())
-
-			resBody, err := fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(testBalance).String())
-			Expect(err).ToNot(HaveOccurred())
+			pendingBalance := new(big.Int).Add(testBalance, big.NewInt(5e9))
 
 			server := test.NewTestServer(GinkgoT(), test.ExpectedCall{
 				Path:   "/",
@@ -51,8 +50,17 @@ var _ = Describe("EthereumClient", func() {
 				Headers: map[string]string{
 					"Content-Type": "Application/Json",
 				},
-				Body:         reqBody,
-				Response:     resBody,
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()), "latest", 1)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(testBalance).String())),
+				ResponseCode: http.StatusOK,
+			}, test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()), "pending", 2)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(pendingBalance).String())),
 				ResponseCode: http.StatusOK,
 			})
 			defer server.Close()
@@ -71,8 +79,12 @@ var _ = Describe("EthereumClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("ETH"),
-							"Balance": Equal(testBalance.String()),
+							"Asset":       Equal("ETH"),
+							"Balance":     Equal(testBalance.String()),
+							"Confirmed":   Equal(testBalance.String()),
+							"Unconfirmed": Equal("5000000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -98,7 +110,7 @@ var _ = Describe("EthereumClient", func() {
 					Headers: map[string]string{
 						"Content-Type": "Application/Json",
 					},
-					Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", 2)),
+					Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 2)),
 					Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json")),
 					ResponseCode: http.StatusOK,
 				},
@@ -118,7 +130,7 @@ var _ = Describe("EthereumClient", func() {
 			Expect(b).To(PointTo(MatchAllFields(Fields{
 				"Data": MatchAllFields(Fields{
 					"Chain":        Equal("3"),
-					"CurrentBlock": Equal(fixtureTransactionHash),
+					"CurrentBlock": Equal(fixtureBlockHash),
 					"BlockHeight":  Equal(15061313),
 				}),
 			})))
@@ -129,10 +141,11 @@ var _ = Describe("EthereumClient", func() {
 		It("Should return a transaction in standardised format", func() {
 			server := test.NewTestServer(
 				GinkgoT(),
-				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
 				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash.json", fixtureTransactionHash))),
 				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
-				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt.json", "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt.json", fixtureTransactionHash, resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
 			)
 			defer server.Close()
 
@@ -157,10 +170,311 @@ var _ = Describe("EthereumClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(15061302))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": BeEmpty(),
+						"Reverted":  BeFalse(),
+						"Fees": PointTo(MatchAllFields(Fields{
+							"Type":           Equal(0),
+							"GasUsed":        Equal(uint64(1244)),
+							"GasPrice":       Equal("20000000000"),
+							"BaseFee":        BeEmpty(),
+							"PriorityFee":    BeEmpty(),
+							"MaxFee":         BeEmpty(),
+							"MaxPriorityFee": BeEmpty(),
+						})),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
+					}),
+				}),
+			})))
+		})
+	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the block with the hashes of its transactions", func() {
+			hash := fixtureBlockHash
+
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xe5d141", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber_hashes.json", hash, "0xe5d141"))),
+			)
+			defer server.Close()
+
+			rpcClient, err := rpc.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: ethclient.NewClient(rpcClient),
+				RPC:    rpcClient,
+			}
+
+			res, err := ec.GetBlockByHeight(15061313)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Block).To(Equal(transport.Block{
+				Height:    15061313,
+				Hash:      hash,
+				Parent:    "0x701bc7632e80976d1a2c408ffa58e4f11aa3ed3c5a030d1125930a9d944e4343",
+				Timestamp: 1689641427,
+				TxCount:   2,
+				TxIDs: []string{
+					"0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060",
+					"0x03fb1b4f5b8d3a2b2a1e3c7c5f9c9dbcd3bfb1e3c5d7f9a1b3c5d7e9f1a3b5c7",
+				},
+			}))
+		})
+	})
+
+	Describe("#GetBalances", func() {
+		It("Should fetch every confirmed and pending balance in a single batch call", func() {
+			otherAddr := "0x0000000000000000000000000000000000000000"
+			pendingBalance := new(big.Int).Add(testBalance, big.NewInt(5e9))
+
+			server := test.NewTestServer(GinkgoT(), test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance_batch.json", strings.ToLower(testAddr.String()), otherAddr)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance_batch.json", (*hexutil.Big)(testBalance).String(), (*hexutil.Big)(pendingBalance).String())),
+				ResponseCode: http.StatusOK,
+			})
+			defer server.Close()
+
+			rpcClient, err := rpc.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: ethclient.NewClient(rpcClient),
+				RPC:    rpcClient,
+			}
+
+			b, err := ec.GetBalances([]string{testAddr.String(), otherAddr})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(b).To(MatchAllKeys(Keys{
+				testAddr.String(): PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("ETH"),
+								"Balance":     Equal(testBalance.String()),
+								"Confirmed":   Equal(testBalance.String()),
+								"Unconfirmed": Equal("5000000000"),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
 					}),
+				})),
+			}))
+		})
+	})
+
+	Describe("#GetTransactionByHash with a pending transaction", func() {
+		It("Should report the transaction as pending without fetching its receipt", func() {
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash_pending.json", fixtureTransactionHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: client,
+			}
+
+			tran, err := ec.GetTransactionByHash(fixtureTransactionHash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.Confirmations).To(MatchAllFields(Fields{
+				"Threshold": PointTo(Equal(int64(5))),
+				"Confirmed": BeFalse(),
+				"Value":     PointTo(Equal(int64(0))),
+				"Reorged":   BeFalse(),
+				"Pending":   BeTrue(),
+			}))
+		})
+	})
+
+	Describe("#GetMempool", func() {
+		It("Should return the pending and queued transaction counts of the txpool", func() {
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/txpool_status.json")), MustLoad(fb.LoadFixture("ethereum/res/txpool_status.json"))),
+			)
+			defer server.Close()
+
+			rpcClient, err := rpc.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: ethclient.NewClient(rpcClient),
+				RPC:    rpcClient,
+			}
+
+			res, err := ec.GetMempool()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.Mempool).To(MatchAllFields(Fields{
+				"Size":         Equal(16),
+				"Bytes":        BeZero(),
+				"Queued":       Equal(7),
+				"FeeHistogram": BeEmpty(),
+			}))
+		})
+	})
+
+	Describe("#GetBalanceAt", func() {
+		It("Should request the balance at the given block", func() {
+			server := test.NewTestServer(GinkgoT(), test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()), "0x7a1200", 1)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(testBalance).String())),
+				ResponseCode: http.StatusOK,
+			})
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: client,
+			}
+
+			b, err := ec.GetBalanceAt(testAddr.String(), 8000000)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(b).To(PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"Assets": ConsistOf(
+						MatchAllFields(Fields{
+							"Asset":       Equal("ETH"),
+							"Balance":     Equal(testBalance.String()),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
+						}),
+					),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetTransactionByHash with a dynamic fee transaction", func() {
+		It("Should recover the sender and report the fees paid at the block's base fee", func() {
+			hash := "0x8d7b7de0b18cd42afa9b8346a9bf0d15a6a8d2aef4f869a450c9128d737f91f8"
+
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber_london.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash_1559.json", hash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt.json", hash, resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber_london.json"))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: client,
+			}
+
+			tran, err := ec.GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.From).To(Equal("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"))
+			Expect(tran.Data.Transaction.Value).To(Equal("4290000000000000"))
+			Expect(tran.Data.Transaction.Reverted).To(BeFalse())
+			Expect(tran.Data.Transaction.Fees).To(PointTo(MatchAllFields(Fields{
+				"Type":           Equal(2),
+				"GasUsed":        Equal(uint64(1244)),
+				"GasPrice":       Equal("12000000000"),
+				"BaseFee":        Equal("10000000000"),
+				"PriorityFee":    Equal("2000000000"),
+				"MaxFee":         Equal("50000000000"),
+				"MaxPriorityFee": Equal("2000000000"),
+			})))
+		})
+
+		It("Should only report the fees bid while the transaction is pending", func() {
+			hash := "0x8d7b7de0b18cd42afa9b8346a9bf0d15a6a8d2aef4f869a450c9128d737f91f8"
+
+			// a pending transaction has no block number.
+			res := strings.Replace(MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash_1559.json", hash)), `"blockNumber": "0xb"`, `"blockNumber": null`, 1)
+
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber_london.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", hash)), res),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: client,
+			}
+
+			tran, err := ec.GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.Confirmations.Pending).To(BeTrue())
+			Expect(tran.Data.Transaction.Fees).To(PointTo(MatchAllFields(Fields{
+				"Type":           Equal(2),
+				"GasUsed":        BeZero(),
+				"GasPrice":       BeEmpty(),
+				"BaseFee":        BeEmpty(),
+				"PriorityFee":    BeEmpty(),
+				"MaxFee":         Equal("50000000000"),
+				"MaxPriorityFee": Equal("2000000000"),
+			})))
+		})
+	})
+
+	Describe("#GetTransactionByHash with a reverted transaction", func() {
+		It("Should mark the transaction as reverted", func() {
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash.json", fixtureTransactionHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt_reverted.json", fixtureTransactionHash, resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				Client: client,
+			}
+
+			tran, err := ec.GetTransactionByHash(fixtureTransactionHash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.Reverted).To(BeTrue())
+			Expect(tran.Data.Transaction.Confirmations.Confirmed).To(BeTrue())
+		})
+	})
 })
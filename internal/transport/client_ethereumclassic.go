@@ -1,9 +1,5 @@
 package transport
 
-import (
-	"github.com/ethereum/go-ethereum/ethclient"
-)
-
 var (
 	EthereumclassicAssetID = "ETC"
 )
@@ -15,15 +11,14 @@ type EthereumClassicClient struct {
 
 // NewEthereumClassicClient returns a new client using os variables.
 func NewEthereumClassicClient() (*EthereumClassicClient, error) {
-	ethRpc, err := ethclient.Dial(getNodeURL("ETHEREUMCLASSIC_URL"))
+	return newEthereumClassicClient(MainNet)
+}
+
+func newEthereumClassicClient(network string) (*EthereumClassicClient, error) {
+	ethClient, err := newEthereumClient(EthereumclassicAssetID, "ETHEREUMCLASSIC", network)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EthereumClassicClient{
-		EthereumClient: &EthereumClient{
-			AssetID: EthereumclassicAssetID,
-			Client:  ethRpc,
-		},
-	}, nil
+	return &EthereumClassicClient{EthereumClient: ethClient}, nil
 }
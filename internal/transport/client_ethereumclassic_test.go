@@ -25,6 +25,7 @@ var _ = Describe("EthereumClassicClient", func() {
 		testAddr               = crypto.PubkeyToAddress(testKey.PublicKey)
 		testBalance            = big.NewInt(2e10)
 		fixtureTransactionHash = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+		fixtureBlockHash       = "0xd0e2bd0306856f12ca3bea5d203a43329e999c4866793414fb04f789d70ca07f"
 		resultingBlockHash     = "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"
 	)
 
@@ -39,11 +40,7 @@ var _ = Describe("EthereumClassicClient", func() {
 
 	Describe("#GetBalance", func() {
 		It("Should conform rpc output to standard balance rep", func() {
-			reqBody, err := fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()))
-			Expect(err).ToNot(HaveOccurred())
-
-			resBody, err := fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(testBalance).String())
-			Expect(err).ToNot(HaveOccurred())
+			pendingBalance := new(big.Int).Add(testBalance, big.NewInt(5e9))
 
 			server := test.NewTestServer(GinkgoT(), test.ExpectedCall{
 				Path:   "/",
@@ -51,8 +48,17 @@ var _ = Describe("EthereumClassicClient", func() {
 				Headers: map[string]string{
 					"Content-Type": "Application/Json",
 				},
-				Body:         reqBody,
-				Response:     resBody,
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()), "latest", 1)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(testBalance).String())),
+				ResponseCode: http.StatusOK,
+			}, test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBalance.json", strings.ToLower(testAddr.String()), "pending", 2)),
+				Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBalance.json", (*hexutil.Big)(pendingBalance).String())),
 				ResponseCode: http.StatusOK,
 			})
 			defer server.Close()
@@ -71,8 +77,12 @@ var _ = Describe("EthereumClassicClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("ETC"),
-							"Balance": Equal(testBalance.String()),
+							"Asset":       Equal("ETC"),
+							"Balance":     Equal(testBalance.String()),
+							"Confirmed":   Equal(testBalance.String()),
+							"Unconfirmed": Equal("5000000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -98,7 +108,7 @@ var _ = Describe("EthereumClassicClient", func() {
 					Headers: map[string]string{
 						"Content-Type": "Application/Json",
 					},
-					Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", 2)),
+					Body:         MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 2)),
 					Response:     MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json")),
 					ResponseCode: http.StatusOK,
 				},
@@ -118,7 +128,7 @@ var _ = Describe("EthereumClassicClient", func() {
 			Expect(b).To(PointTo(MatchAllFields(Fields{
 				"Data": MatchAllFields(Fields{
 					"Chain":        Equal("3"),
-					"CurrentBlock": Equal(fixtureTransactionHash),
+					"CurrentBlock": Equal(fixtureBlockHash),
 					"BlockHeight":  Equal(15061313),
 				}),
 			})))
@@ -129,10 +139,11 @@ var _ = Describe("EthereumClassicClient", func() {
 		It("Should return a transaction in standardised format", func() {
 			server := test.NewTestServer(
 				GinkgoT(),
-				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
 				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash.json", fixtureTransactionHash))),
 				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
-				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt.json", "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", fixtureTransactionHash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt.json", fixtureTransactionHash, resultingBlockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
 			)
 			defer server.Close()
 
@@ -157,7 +168,25 @@ var _ = Describe("EthereumClassicClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(15061302))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": BeEmpty(),
+						"Reverted":  BeFalse(),
+						"Fees": PointTo(MatchAllFields(Fields{
+							"Type":           Equal(0),
+							"GasUsed":        Equal(uint64(1244)),
+							"GasPrice":       Equal("20000000000"),
+							"BaseFee":        BeEmpty(),
+							"PriorityFee":    BeEmpty(),
+							"MaxFee":         BeEmpty(),
+							"MaxPriorityFee": BeEmpty(),
+						})),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
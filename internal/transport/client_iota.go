@@ -180,18 +180,24 @@ func (b IotaClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 
 	attachment := bundle.Attachments[0]
 
+	confirmations := transport.Confirmations{
+		Confirmed: attachment.Status == "confirmed",
+	}
+
+	if attachment.Status == "pending" {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  attachment.Inputs[0].Address,
-				To:    attachment.Outputs[0].Address,
-				Value: fmt.Sprintf("%d", tx.Value),
-				Confirmations: transport.Confirmations{
-					Confirmed: attachment.Status == "confirmed",
-				},
+				ID:            hash,
+				From:          attachment.Inputs[0].Address,
+				To:            attachment.Outputs[0].Address,
+				Value:         fmt.Sprintf("%d", tx.Value),
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
@@ -91,8 +91,12 @@ var _ = Describe("IotaClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("MIOTA"),
-							"Balance": Equal("6662"),
+							"Asset":       Equal("MIOTA"),
+							"Balance":     Equal("6662"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -131,7 +135,17 @@ var _ = Describe("IotaClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
@@ -3,6 +3,7 @@ package transport
 import (
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -11,6 +12,11 @@ import (
 
 var (
 	LiskAssetID = "LSK"
+
+	// liskEpoch is the unix time of the genesis block, which lisk timestamps are relative to.
+	liskEpoch int64 = 1464109200
+	// liskMaxLimit is the largest page size accepted by the lisk api.
+	liskMaxLimit = "100"
 )
 
 // LiskMeta is a struct representing the json meta data in a response.
@@ -134,6 +140,65 @@ func (b LiskClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
+// GetBlockByHeight returns the block at the given height.
+func (b LiskClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	return b.getBlock(map[string]string{
+		"height": strconv.FormatInt(height, 10),
+		"limit":  "1",
+	})
+}
+
+// GetBlockByHash returns the block with the given id.
+func (b LiskClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	return b.getBlock(map[string]string{
+		"blockId": hash,
+		"limit":   "1",
+	})
+}
+
+func (b LiskClient) getBlock(query map[string]string) (*transport.BlockResp, error) {
+	var res LiskGetBlocksResponse
+
+	err := b.GET("/api/blocks", query, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting lisk block")
+	}
+
+	if len(res.Data) == 0 {
+		return nil, errors.New("lisk block not found")
+	}
+
+	data := res.Data[0]
+
+	var txs LiskGetTXResponse
+
+	// blocks hold at most 25 transactions so a single page is enough to list them.
+	err = b.GET("/api/transactions", map[string]string{
+		"blockId": data.ID,
+		"limit":   liskMaxLimit,
+	}, &txs)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting lisk transactions for block")
+	}
+
+	ids := make([]string, len(txs.Data))
+	for key, value := range txs.Data {
+		ids[key] = value.ID
+	}
+
+	block := &transport.BlockResp{}
+	block.Data.Block = transport.Block{
+		Height:    int64(data.Height),
+		Hash:      data.ID,
+		Parent:    data.PreviousBlockID,
+		Timestamp: liskEpoch + int64(data.Timestamp),
+		TxCount:   data.NumberOfTransactions,
+		TxIDs:     ids,
+	}
+
+	return block, nil
+}
+
 // GetBalance returns the balance of the address.
 func (b LiskClient) GetBalance(addr string) (*transport.Balance, error) {
 	var res LiskGetAccountResponse
@@ -170,6 +235,11 @@ func (b LiskClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 		return nil, errors.Wrap(err, "error getting latest lisk transaction")
 	}
 
+	// transactions waiting in the pool are only listed by the node's unconfirmed transactions.
+	if len(res.Data) == 0 {
+		return b.getUnconfirmedTransaction(hash)
+	}
+
 	tx := res.Data[0]
 	return &transport.TransactionResp{
 		Data: struct {
@@ -189,3 +259,34 @@ func (b LiskClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 		},
 	}, nil
 }
+
+func (b LiskClient) getUnconfirmedTransaction(hash string) (*transport.TransactionResp, error) {
+	var res LiskGetTXResponse
+
+	err := b.GET("/api/node/transactions/unconfirmed", map[string]string{
+		"id":    hash,
+		"limit": "1",
+	}, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting unconfirmed lisk transaction")
+	}
+
+	if len(res.Data) == 0 {
+		return nil, errors.Errorf("lisk transaction not found: %s", hash)
+	}
+
+	tx := res.Data[0]
+	return &transport.TransactionResp{
+		Data: struct {
+			Transaction transport.Transaction `json:"transaction"`
+		}{
+			Transaction: transport.Transaction{
+				ID:            hash,
+				From:          tx.SenderID,
+				To:            tx.RecipientID,
+				Value:         tx.Amount,
+				Confirmations: transport.PendingConfirmations(),
+			},
+		},
+	}, nil
+}
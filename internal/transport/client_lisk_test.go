@@ -98,8 +98,12 @@ var _ = Describe("LiskClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("LSK"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("LSK"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -136,10 +140,191 @@ var _ = Describe("LiskClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(205))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the Lisk block transformed to the common output", func() {
+			blockID := "9181329057331339714"
+			txID := "10153999893155125334"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path: "/api/blocks",
+				QueryParams: map[string]string{
+					"height": "10406788",
+					"limit":  "1",
+				},
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("lisk/res/getblock.json", blockID)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path: "/api/transactions",
+				QueryParams: map[string]string{
+					"blockId": blockID,
+					"limit":   "100",
+				},
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction.json", txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(10406788)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":       Equal(int64(10406788)),
+				"Hash":         Equal(blockID),
+				"Parent":       Equal("4309828569772746114"),
+				"Timestamp":    Equal(int64(1570700580)),
+				"TxCount":      Equal(1),
+				"TxIDs":        Equal([]string{txID}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+
+		It("Should return an error if the block does not exist", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path: "/api/blocks",
+				QueryParams: map[string]string{
+					"height": "99999999",
+					"limit":  "1",
+				},
+				Method:       http.MethodGet,
+				Response:     `{"meta": {"offset": 0, "limit": 1}, "data": [], "links": {}}`,
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.(transport.BlockFetcher).GetBlockByHeight(99999999)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should return the Lisk block with the id", func() {
+			blockID := "9181329057331339714"
+			txID := "10153999893155125334"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path: "/api/blocks",
+				QueryParams: map[string]string{
+					"blockId": blockID,
+					"limit":   "1",
+				},
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("lisk/res/getblock.json", blockID)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path: "/api/transactions",
+				QueryParams: map[string]string{
+					"blockId": blockID,
+					"limit":   "100",
+				},
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction.json", txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHash(blockID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block.Hash).To(Equal(blockID))
+			Expect(block.Data.Block.TxIDs).To(Equal([]string{txID}))
+		})
+	})
+
+	Describe("#GetTransactionByHash with an unconfirmed transaction", func() {
+		It("Should look the transaction up in the node's pool and report it as pending", func() {
+			txID := "6980013695783136273"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/api/transactions",
+				Method: http.MethodGet,
+				QueryParams: map[string]string{
+					"id":    txID,
+					"limit": "1",
+				},
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction_empty.json")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/api/node/transactions/unconfirmed",
+				Method: http.MethodGet,
+				QueryParams: map[string]string{
+					"id":    txID,
+					"limit": "1",
+				},
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction_unconfirmed.json", txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx).To(PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"Transaction": MatchAllFields(Fields{
+						"ID":    Equal(txID),
+						"From":  Equal("7714731151444318219L"),
+						"To":    Equal("1186872597084592226L"),
+						"Value": Equal("33300000000"),
+						"Confirmations": MatchAllFields(Fields{
+							"Threshold": PointTo(Equal(int64(5))),
+							"Confirmed": BeFalse(),
+							"Value":     PointTo(Equal(int64(0))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeTrue(),
+						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
+					}),
+				}),
+			})))
+		})
+
+		It("Should error when the transaction is neither confirmed nor pending", func() {
+			txID := "6980013695783136273"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/api/transactions",
+				Method: http.MethodGet,
+				QueryParams: map[string]string{
+					"id":    txID,
+					"limit": "1",
+				},
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction_empty.json")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/api/node/transactions/unconfirmed",
+				Method: http.MethodGet,
+				QueryParams: map[string]string{
+					"id":    txID,
+					"limit": "1",
+				},
+				Response:     MustLoad(fb.LoadFixture("lisk/res/gettransaction_empty.json")),
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.GetTransactionByHash(txID)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
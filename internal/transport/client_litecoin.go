@@ -11,15 +11,14 @@ type LitecoinClient struct {
 
 // NewLitecoinClient returns a new client using os variables.
 func NewLitecoinClient() (*LitecoinClient, error) {
-	btcClient, err := newBTCClient(getNodeURL("LITECOIN_URL"))
+	return newLitecoinClient(MainNet)
+}
+
+func newLitecoinClient(network string) (*LitecoinClient, error) {
+	btcClient, err := newBTCFamilyClient(LitecoinAssetID, "LITECOIN", network, LitecoinNetwork)
 	if err != nil {
 		return nil, err
 	}
 
-	return &LitecoinClient{
-		BitcoinClient: &BitcoinClient{
-			AssetID: LitecoinAssetID,
-			Client:  btcClient,
-		},
-	}, nil
+	return &LitecoinClient{BitcoinClient: btcClient}, nil
 }
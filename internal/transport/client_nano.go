@@ -29,6 +29,8 @@ type NanoAccountResponse struct {
 	BlockCount          string `json:"block_count"`
 	AccountVersion      string `json:"account_version"`
 	ConfirmationHeight  string `json:"confirmation_height"`
+	// Pending is only returned when requested, it holds the receivable amount not yet pocketed.
+	Pending string `json:"pending"`
 }
 
 // NanoBlockResponse is a struct representing the json from a successful block_info call.
@@ -44,6 +46,8 @@ type NanoBlockResponse struct {
 	Link           string    `json:"link"`
 	LinkAsAccount  string    `json:"link_as_account"`
 	Balance        string    `json:"balance"`
+	// Confirmed is "false" while the block hasn't been cemented, older nodes don't report it.
+	Confirmed string `json:"confirmed"`
 }
 
 // NanoBlockInfoRequest is a struct to hold the block_info json action request.
@@ -57,6 +61,21 @@ type NanoBlockInfoRequest struct {
 type NanoAccountInfoRequest struct {
 	Action  string `json:"action"`
 	Account string `json:"account"`
+	Pending string `json:"pending,omitempty"`
+}
+
+// NanoAccountsBalancesRequest is a struct to hold the accounts_balances json action request.
+type NanoAccountsBalancesRequest struct {
+	Action   string   `json:"action"`
+	Accounts []string `json:"accounts"`
+}
+
+// NanoAccountsBalancesResponse is a struct representing the json from a successful accounts_balances call.
+type NanoAccountsBalancesResponse struct {
+	Balances map[string]struct {
+		Balance string `json:"balance"`
+		Pending string `json:"pending"`
+	} `json:"balances"`
 }
 
 // NanoActionRequest is a struct to hold the base json action request.
@@ -110,7 +129,7 @@ func (n NanoClient) GetInfo() (*transport.CoinState, error) {
 func (n NanoClient) GetBalance(addr string) (*transport.Balance, error) {
 	var acc NanoAccountResponse
 
-	if err := n.POST(NanoAccountInfoRequest{Action: "account_info", Account: addr}, "/", &acc); err != nil {
+	if err := n.POST(NanoAccountInfoRequest{Action: "account_info", Account: addr, Pending: "true"}, "/", &acc); err != nil {
 		return nil, err
 	}
 
@@ -118,14 +137,41 @@ func (n NanoClient) GetBalance(addr string) (*transport.Balance, error) {
 		Data: transport.BalanceData{
 			Assets: []transport.Asset{
 				{
-					Asset:   NanoAssetID,
-					Balance: acc.Balance,
+					Asset:       NanoAssetID,
+					Balance:     acc.Balance,
+					Unconfirmed: acc.Pending,
 				},
 			},
 		},
 	}, nil
 }
 
+// GetBalances returns the balances of all the addresses using a single accounts_balances call.
+func (n NanoClient) GetBalances(addrs []string) (map[string]*transport.Balance, error) {
+	var res NanoAccountsBalancesResponse
+
+	if err := n.POST(NanoAccountsBalancesRequest{Action: "accounts_balances", Accounts: addrs}, "/", &res); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*transport.Balance, len(res.Balances))
+	for addr, acc := range res.Balances {
+		balances[addr] = &transport.Balance{
+			Data: transport.BalanceData{
+				Assets: []transport.Asset{
+					{
+						Asset:       NanoAssetID,
+						Balance:     acc.Balance,
+						Unconfirmed: acc.Pending,
+					},
+				},
+			},
+		}
+	}
+
+	return balances, nil
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (n NanoClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var block NanoBlockResponse
@@ -138,19 +184,25 @@ func (n NanoClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 		return nil, err
 	}
 
+	confirmations := transport.Confirmations{
+		// if block is present in the local node and not reported as unconfirmed it is confirmed
+		Confirmed: true,
+	}
+
+	if block.Confirmed == "false" {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  block.Account,
-				To:    block.LinkAsAccount,
-				Value: block.Amount,
-				Confirmations: transport.Confirmations{
-					// if block is present in the local node it is confirmed
-					Confirmed: true,
-				},
+				ID:            hash,
+				From:          block.Account,
+				To:            block.LinkAsAccount,
+				Value:         block.Amount,
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
@@ -98,8 +98,12 @@ var _ = Describe("NanoClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("NANO"),
-							"Balance": Equal("325586539664609129644855132177"),
+							"Asset":       Equal("NANO"),
+							"Balance":     Equal("325586539664609129644855132177"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": Equal("1000000000000000000000000"),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -136,10 +140,72 @@ var _ = Describe("NanoClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBalances", func() {
+		It("Should return the Nano balances of every account from a single call", func() {
+			addr1 := "xrb_3t6k35gi95xu6tergt6p69ck76ogmitsa8mnijtpxm9fkcm736xtoncuohr3"
+			addr2 := "xrb_3i1aq1cchnmbn9x5rsbap8b15akfh7wj7pwskuzi7ahz8oq6cobd99d4r3b7"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("nano/req/accounts_balances.json", addr1, addr2)),
+				Response:     MustLoad(fb.LoadFixture("nano/res/accounts_balances.json", addr1, "325586539664609129644855132177", addr2, "0")),
+				ResponseCode: http.StatusOK,
+			})
+
+			balances, err := client.(transport.BatchBalanceGetter).GetBalances([]string{addr1, addr2})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balances).To(MatchAllKeys(Keys{
+				addr1: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("NANO"),
+								"Balance":     Equal("325586539664609129644855132177"),
+								"Confirmed":   BeEmpty(),
+								"Unconfirmed": Equal("0"),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
+					}),
+				})),
+				addr2: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("NANO"),
+								"Balance":     Equal("0"),
+								"Confirmed":   BeEmpty(),
+								"Unconfirmed": Equal("2309370929000000000000000000000000"),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
+					}),
+				})),
+			}))
+		})
+	})
 })
@@ -6,11 +6,16 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/hugorut/coins-oracle/pkg/transport"
 )
 
 var (
 	NemAssetID = "XEM"
+
+	// nemEpoch is the unix time of the nemesis block, which NEM timestamps are relative to.
+	nemEpoch int64 = 1427587585
 )
 
 type NemGetLastBlockResponse struct {
@@ -38,6 +43,22 @@ type NemGetLastBlockResponse struct {
 	Height  int64  `json:"height"`
 }
 
+// NemBlocksAfterResponse represents the json returned from a blocks-after request.
+type NemBlocksAfterResponse struct {
+	Data []struct {
+		Block NemGetLastBlockResponse `json:"block"`
+		Hash  string                  `json:"hash"`
+		Txes  []struct {
+			Hash string `json:"hash"`
+		} `json:"txes"`
+	} `json:"data"`
+}
+
+// NemBlockHeightReq represents a json body for the blocks-after request.
+type NemBlockHeightReq struct {
+	Height int64 `json:"height"`
+}
+
 type NemAccountResponse struct {
 	Meta struct {
 		Cosignatories []interface{} `json:"cosignatories"`
@@ -123,6 +144,44 @@ func (n NemClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
+// GetBlockByHeight returns the block at the given height. NIS only returns the hashes of a block and its
+// transactions from the local blocks-after endpoint, so the block after the previous height is fetched.
+func (n NemClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	var res NemBlocksAfterResponse
+
+	if err := n.POST(NemBlockHeightReq{Height: height - 1}, "/local/chain/blocks-after", &res); err != nil {
+		return nil, errors.Wrap(err, "error getting nem blocks after height")
+	}
+
+	if len(res.Data) == 0 || res.Data[0].Block.Height != height {
+		return nil, errors.Errorf("nem block at height: %d not found", height)
+	}
+
+	data := res.Data[0]
+
+	ids := make([]string, len(data.Txes))
+	for key, value := range data.Txes {
+		ids[key] = value.Hash
+	}
+
+	block := &transport.BlockResp{}
+	block.Data.Block = transport.Block{
+		Height:    data.Block.Height,
+		Hash:      data.Hash,
+		Parent:    data.Block.PrevBlockHash.Data,
+		Timestamp: nemEpoch + int64(data.Block.TimeStamp),
+		TxCount:   len(ids),
+		TxIDs:     ids,
+	}
+
+	return block, nil
+}
+
+// GetBlockByHash is not supported as NIS can't look up blocks by hash.
+func (n NemClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	return nil, transport.ErrBlockHashNotSupported
+}
+
 // GetBalance returns the balance of the address.
 func (n NemClient) GetBalance(addr string) (*transport.Balance, error) {
 	var account NemAccountResponse
@@ -92,8 +92,12 @@ var _ = Describe("NemClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("XEM"),
-							"Balance": Equal(fmt.Sprintf("%d", balRes)),
+							"Asset":       Equal("XEM"),
+							"Balance":     Equal(fmt.Sprintf("%d", balRes)),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -143,10 +147,68 @@ var _ = Describe("NemClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(6))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the Nem block transformed to the common output", func() {
+			txHash := "a1ec3fc5bd4acb5e4ec0e6e5e4b9d6e1d4d5bfb1a2f1e8d1f0d7c3e6a0b9c8d7"
+			blockHash := "9a0f1e4e2b2c4d3f8bd5be6b0a7a48a66d8f1a1d6bb9d0db2e0d88cc3b0c0b2e"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/local/chain/blocks-after",
+				Method:       http.MethodPost,
+				Body:         MustLoad(fb.LoadFixture("nem/req/getblocksafter.json", 2355046)),
+				Response:     MustLoad(fb.LoadFixture("nem/res/getblocksafter.json", txHash, blockHash)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(2355047)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":       Equal(int64(2355047)),
+				"Hash":         Equal(blockHash),
+				"Parent":       Equal("4439eebb0f32a20e18f3bcb4632fd6de6b7ee2207c39ed961b7cabed06d5635d"),
+				"Timestamp":    Equal(int64(1437909147)),
+				"TxCount":      Equal(1),
+				"TxIDs":        Equal([]string{txHash}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+
+		It("Should return an error if the chain has not reached the height", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/local/chain/blocks-after",
+				Method:       http.MethodPost,
+				Body:         MustLoad(fb.LoadFixture("nem/req/getblocksafter.json", 99999998)),
+				Response:     `{"data": []}`,
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.(transport.BlockFetcher).GetBlockByHeight(99999999)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should not be supported", func() {
+			_, err := client.(transport.BlockFetcher).GetBlockByHash("9a0f1e4e")
+			Expect(err).To(Equal(transport.ErrBlockHashNotSupported))
+		})
+	})
 })
@@ -19,10 +19,17 @@ type NeoRPCRequest struct {
 	ID      int      `json:"id"`
 }
 
+// NeoRPCError represents the error object of a failed JSON-RPC call.
+type NeoRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
 // NeoAccountResponse represents the JSON returned from a successful get account call.
 type NeoAccountResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	ID      int    `json:"id"`
+	Jsonrpc string       `json:"jsonrpc"`
+	ID      int          `json:"id"`
+	Error   *NeoRPCError `json:"error,omitempty"`
 	Result  struct {
 		Version    int           `json:"version"`
 		ScriptHash string        `json:"script_hash"`
@@ -51,8 +58,9 @@ type NeoBlockCountResponse struct {
 
 // NeoTXResponse represents the JSON returned from a successful get transaction call.
 type NeoTXResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	ID      int    `json:"id"`
+	Jsonrpc string       `json:"jsonrpc"`
+	ID      int          `json:"id"`
+	Error   *NeoRPCError `json:"error,omitempty"`
 	Result  struct {
 		Txid       string        `json:"Txid"`
 		Size       int           `json:"Size"`
@@ -168,6 +176,50 @@ func (n NeoClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
+// GetBalances returns the balances of all the addresses using a single JSON-RPC batch request.
+func (n NeoClient) GetBalances(addrs []string) (map[string]*transport.Balance, error) {
+	reqs := make([]NeoRPCRequest, len(addrs))
+	for key, addr := range addrs {
+		reqs[key] = NeoRPCRequest{
+			JsonRPC: transport.RPCVersion,
+			Method:  "getaccountstate",
+			Params: []string{
+				addr,
+			},
+			ID: key + 1,
+		}
+	}
+
+	var res []NeoAccountResponse
+	if err := n.POST(reqs, "/", &res); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*transport.Balance, len(res))
+	for _, info := range res {
+		// skip responses we can't map back to an address or that errored, callers treat these as missing.
+		if info.ID < 1 || info.ID > len(addrs) || info.Error != nil {
+			continue
+		}
+
+		assets := make([]transport.Asset, len(info.Result.Balances))
+		for key, value := range info.Result.Balances {
+			assets[key] = transport.Asset{
+				Asset:   transport.StripHex(value.Asset),
+				Balance: value.Value,
+			}
+		}
+
+		balances[addrs[info.ID-1]] = &transport.Balance{
+			Data: transport.BalanceData{
+				Assets: assets,
+			},
+		}
+	}
+
+	return balances, nil
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (n NeoClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var tx NeoTXResponse
@@ -195,21 +247,112 @@ func (n NeoClient) GetTransactionByHash(hash string) (*transport.TransactionResp
 		return nil, err
 	}
 
+	confirmations := transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: tx.Result.Confirmations >= *transport.ConfirmThresholdValue,
+		Value:     &tx.Result.Confirmations,
+	}
+
+	// confirmations are omitted for transactions still in the mempool.
+	if tx.Result.Confirmations == 0 {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  sendingTx.Result.Vout[sender.Vout].Address,
-				To:    tx.Result.Vout[0].Address,
-				Value: tx.Result.Vout[0].Value,
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: tx.Result.Confirmations >= *transport.ConfirmThresholdValue,
-					Value:     &tx.Result.Confirmations,
-				},
+				ID:            hash,
+				From:          sendingTx.Result.Vout[sender.Vout].Address,
+				To:            tx.Result.Vout[0].Address,
+				Value:         tx.Result.Vout[0].Value,
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
 }
+
+// GetTransactionsByHash returns the transactions stored at the given hashes. The transactions and their
+// sending transactions are each fetched with a single JSON-RPC batch request.
+func (n NeoClient) GetTransactionsByHash(hashes []string) (map[string]*transport.TransactionResp, error) {
+	reqs := make([]NeoRPCRequest, len(hashes))
+	for key, hash := range hashes {
+		reqs[key] = NeoRPCRequest{
+			JsonRPC: transport.RPCVersion,
+			Method:  "getrawtransaction",
+			Params: []string{
+				hash,
+				"1",
+			},
+			ID: key + 1,
+		}
+	}
+
+	var txs []NeoTXResponse
+	if err := n.POST(reqs, "/", &txs); err != nil {
+		return nil, err
+	}
+
+	found := make(map[int]NeoTXResponse, len(txs))
+	var senderReqs []NeoRPCRequest
+	for _, tx := range txs {
+		if tx.ID < 1 || tx.ID > len(hashes) || tx.Error != nil || len(tx.Result.Vin) == 0 {
+			continue
+		}
+
+		found[tx.ID] = tx
+		senderReqs = append(senderReqs, NeoRPCRequest{
+			JsonRPC: transport.RPCVersion,
+			Method:  "getrawtransaction",
+			Params: []string{
+				transport.StripHex(tx.Result.Vin[0].Txid),
+				"1",
+			},
+			ID: tx.ID,
+		})
+	}
+
+	results := make(map[string]*transport.TransactionResp, len(found))
+	if len(senderReqs) == 0 {
+		return results, nil
+	}
+
+	var senders []NeoTXResponse
+	if err := n.POST(senderReqs, "/", &senders); err != nil {
+		return nil, err
+	}
+
+	for _, sendingTx := range senders {
+		tx, ok := found[sendingTx.ID]
+		if !ok || sendingTx.Error != nil {
+			continue
+		}
+
+		sender := tx.Result.Vin[0]
+		if sender.Vout >= len(sendingTx.Result.Vout) || len(tx.Result.Vout) == 0 {
+			continue
+		}
+
+		confirmations := tx.Result.Confirmations
+		results[hashes[tx.ID-1]] = &transport.TransactionResp{
+			Data: struct {
+				Transaction transport.Transaction `json:"transaction"`
+			}{
+				Transaction: transport.Transaction{
+					ID:    hashes[tx.ID-1],
+					From:  sendingTx.Result.Vout[sender.Vout].Address,
+					To:    tx.Result.Vout[0].Address,
+					Value: tx.Result.Vout[0].Value,
+					Confirmations: transport.Confirmations{
+						Threshold: transport.ConfirmThresholdValue,
+						Confirmed: confirmations >= *transport.ConfirmThresholdValue,
+						Value:     &confirmations,
+					},
+				},
+			},
+		}
+	}
+
+	return results, nil
+}
@@ -108,8 +108,12 @@ var _ = Describe("NeoClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -156,10 +160,117 @@ var _ = Describe("NeoClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(144))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBalances", func() {
+		It("Should return the Neo balances from a single batch call, skipping errored accounts", func() {
+			addr1 := "AJBENSwajTzQtwyJFkiJSv7MAaaMc7DsRz"
+			addr2 := "invalid"
+			balRes := "94"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("neo/req/getbalances.json", addr1, addr2)),
+				Response:     MustLoad(fb.LoadFixture("neo/res/getbalances.json", balRes)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balances, err := client.(transport.BatchBalanceGetter).GetBalances([]string{addr1, addr2})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balances).To(MatchAllKeys(Keys{
+				addr1: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Assets": ConsistOf(
+							MatchAllFields(Fields{
+								"Asset":       Equal("c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b"),
+								"Balance":     Equal(balRes),
+								"Confirmed":   BeEmpty(),
+								"Unconfirmed": BeEmpty(),
+								"Locked":      BeEmpty(),
+								"Issuer":      BeEmpty(),
+							}),
+						),
+					}),
+				})),
+			}))
+		})
+	})
+
+	Describe("#GetTransactionsByHash", func() {
+		It("Should return the Neo transactions using batch calls for the transactions and senders", func() {
+			txID := "f4250dab094c38d8265acc15c366dc508d2e14bf5699e12d9df26577ed74d657"
+			senderID := "abe82713f756eaeebf6fa6440057fca7c36b6c157700738bc34d3634cb765819"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         "[" + MustLoad(fb.LoadFixture("neo/req/gettransaction.json", txID)) + "]",
+				Response:     "[" + MustLoad(fb.LoadFixture("neo/res/gettransaction.json")) + "]",
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         "[" + MustLoad(fb.LoadFixture("neo/req/gettransaction.json", senderID)) + "]",
+				Response:     "[" + MustLoad(fb.LoadFixture("neo/res/gettransaction_sender.json")) + "]",
+				ResponseCode: http.StatusOK,
+			})
+
+			txs, err := client.(transport.BatchTransactionGetter).GetTransactionsByHash([]string{txID})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(txs).To(MatchAllKeys(Keys{
+				txID: PointTo(MatchAllFields(Fields{
+					"Data": MatchAllFields(Fields{
+						"Transaction": MatchAllFields(Fields{
+							"ID":    Equal(txID),
+							"From":  Equal("ALDCagdWUVV4wYoEzCcJ4dtHqtWhsNEEaR"),
+							"To":    Equal("AHCNSDkh2Xs66SzmyKGdoDKY752uyeXDrt"),
+							"Value": Equal("2950"),
+							"Confirmations": MatchAllFields(Fields{
+								"Threshold": PointTo(Equal(int64(5))),
+								"Confirmed": BeTrue(),
+								"Value":     PointTo(Equal(int64(144))),
+								"Reorged":   BeFalse(),
+								"Pending":   BeFalse(),
+							}),
+							"Transfers":      BeEmpty(),
+							"Reverted":       BeFalse(),
+							"Fees":           BeNil(),
+							"Memo":           BeEmpty(),
+							"MemoType":       BeEmpty(),
+							"Coinbase":       BeFalse(),
+							"Outputs":        BeEmpty(),
+							"DestinationTag": BeNil(),
+						}),
+					}),
+				})),
+			}))
+		})
+	})
 })
@@ -52,6 +52,30 @@ type ONTGetTransactionResponse struct {
 	Version string `json:"Version"`
 }
 
+// ONTGetBlockResponse represents a block details response.
+type ONTGetBlockResponse struct {
+	Action string `json:"Action"`
+	Desc   string `json:"Desc"`
+	Error  int    `json:"Error"`
+	Result struct {
+		Hash   string `json:"Hash"`
+		Size   int    `json:"Size"`
+		Header struct {
+			Version          int    `json:"Version"`
+			PrevBlockHash    string `json:"PrevBlockHash"`
+			TransactionsRoot string `json:"TransactionsRoot"`
+			BlockRoot        string `json:"BlockRoot"`
+			Timestamp        int64  `json:"Timestamp"`
+			Height           int64  `json:"Height"`
+			Hash             string `json:"Hash"`
+		} `json:"Header"`
+		Transactions []struct {
+			Hash string `json:"Hash"`
+		} `json:"Transactions"`
+	} `json:"Result"`
+	Version string `json:"Version"`
+}
+
 // ONTGetBalanceResponse represents a json balance response
 type ONTGetBalanceResponse struct {
 	Action string `json:"Action"`
@@ -109,6 +133,51 @@ func (b OntologyClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
+// GetBlockByHeight returns the block at the given height.
+func (b OntologyClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	var block ONTGetBlockResponse
+	if err := b.GET(fmt.Sprintf("/api/v1/block/details/height/%d", height), nil, &block); err != nil {
+		return nil, errors.Wrap(err, "error getting ontology block by height")
+	}
+
+	return b.block(block)
+}
+
+// GetBlockByHash returns the block with the given hash.
+func (b OntologyClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	var block ONTGetBlockResponse
+	if err := b.GET("/api/v1/block/details/hash/"+hash, nil, &block); err != nil {
+		return nil, errors.Wrap(err, "error getting ontology block by hash")
+	}
+
+	return b.block(block)
+}
+
+func (b OntologyClient) block(block ONTGetBlockResponse) (*transport.BlockResp, error) {
+	if block.Error != 0 {
+		return nil, errors.Errorf("error getting ontology block: %s", block.Desc)
+	}
+
+	ids := make([]string, len(block.Result.Transactions))
+	for key, value := range block.Result.Transactions {
+		ids[key] = value.Hash
+	}
+
+	header := block.Result.Header
+
+	res := &transport.BlockResp{}
+	res.Data.Block = transport.Block{
+		Height:    header.Height,
+		Hash:      block.Result.Hash,
+		Parent:    header.PrevBlockHash,
+		Timestamp: header.Timestamp,
+		TxCount:   len(ids),
+		TxIDs:     ids,
+	}
+
+	return res, nil
+}
+
 // GetBalance returns the balance of the address.
 func (b OntologyClient) GetBalance(addr string) (*transport.Balance, error) {
 	var balance ONTGetBalanceResponse
@@ -96,8 +96,12 @@ var _ = Describe("OntologyClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("ONT"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("ONT"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -130,10 +134,79 @@ var _ = Describe("OntologyClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the Ontology block transformed to the common output", func() {
+			hash := "5b24c6d342f527adbce455d69970be823ce81a533109c4723c73716575322d1c"
+			txID := "0c1f1eb7a1c4f4b4a9bfa0e4c4d8cd8c1b7f8d1d4ab87f3b6cc8a5c4d8b3a2f1"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/api/v1/block/details/height/6810623",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("ontology/res/getblock.json", hash, hash, txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(6810623)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":       Equal(int64(6810623)),
+				"Hash":         Equal(hash),
+				"Parent":       Equal("7d6bde8e4e1c8f6e0f08c5b8b52dbd3f1e0bb0e0fd3f5c2ec1a20f3de0e3c7d4"),
+				"Timestamp":    Equal(int64(1570702898)),
+				"TxCount":      Equal(1),
+				"TxIDs":        Equal([]string{txID}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should return the Ontology block with the hash", func() {
+			hash := "5b24c6d342f527adbce455d69970be823ce81a533109c4723c73716575322d1c"
+			txID := "0c1f1eb7a1c4f4b4a9bfa0e4c4d8cd8c1b7f8d1d4ab87f3b6cc8a5c4d8b3a2f1"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/api/v1/block/details/hash/" + hash,
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("ontology/res/getblock.json", hash, hash, txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block.Height).To(Equal(int64(6810623)))
+			Expect(block.Data.Block.TxIDs).To(Equal([]string{txID}))
+		})
+
+		It("Should return an error if the node can't find the block", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/api/v1/block/details/hash/unknown",
+				Method:       http.MethodGet,
+				Response:     `{"Action": "getblockbyhash", "Desc": "UNKNOWN BLOCK", "Error": 44003, "Result": "", "Version": "1.0.0"}`,
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.(transport.BlockFetcher).GetBlockByHash("unknown")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -34,6 +34,18 @@ type QtumAddressResponse struct {
 	BlocksMined      int           `json:"blocksMined"`
 }
 
+// QtumUTXOResponse represents an unspent output in a get address utxo JSON response.
+type QtumUTXOResponse struct {
+	TransactionID string `json:"transactionId"`
+	OutputIndex   uint32 `json:"outputIndex"`
+	ScriptPubKey  string `json:"scriptPubKey"`
+	Address       string `json:"address"`
+	Value         string `json:"value"`
+	IsStake       bool   `json:"isStake"`
+	BlockHeight   int64  `json:"blockHeight"`
+	Confirmations int64  `json:"confirmations"`
+}
+
 // QtumTransactionResponse represents a get tx JSON response.
 type QtumTransactionResponse struct {
 	ID        string `json:"id"`
@@ -143,14 +155,51 @@ func (b QtumClient) GetBalance(addr string) (*transport.Balance, error) {
 		Data: transport.BalanceData{
 			Assets: []transport.Asset{
 				{
-					Asset:   QtumAssetID,
-					Balance: wallet.Balance,
+					Asset:       QtumAssetID,
+					Balance:     wallet.Balance,
+					Unconfirmed: wallet.Unconfirmed,
+					// coins being staked are immature and cannot be spent
+					Locked: wallet.Staking,
 				},
 			},
 		},
 	}, nil
 }
 
+// ListUTXOs returns the unspent outputs of the address with at least minConf confirmations.
+func (b QtumClient) ListUTXOs(addr string, minConf int) (*transport.UTXOsResp, error) {
+	var unspent []QtumUTXOResponse
+	err := b.GET("/api/address/"+addr+"/utxo", nil, &unspent)
+	if err != nil {
+		return nil, errors.Wrap(err, "error making address utxo request")
+	}
+
+	res := &transport.UTXOsResp{}
+	res.Data.UTXOs = []transport.UTXO{}
+
+	for _, value := range unspent {
+		if value.Confirmations < int64(minConf) {
+			continue
+		}
+
+		// values are returned as strings of satoshis so they can be used without rounding.
+		amount, err := strconv.ParseInt(value.Value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing value of output: %s:%d", value.TransactionID, value.OutputIndex)
+		}
+
+		res.Data.UTXOs = append(res.Data.UTXOs, transport.UTXO{
+			TxID:          value.TransactionID,
+			Vout:          value.OutputIndex,
+			Amount:        amount,
+			Script:        value.ScriptPubKey,
+			Confirmations: value.Confirmations,
+		})
+	}
+
+	return res, nil
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (b QtumClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var transaction QtumTransactionResponse
@@ -161,20 +210,28 @@ func (b QtumClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 
 	output, _ := strconv.ParseFloat(transaction.OutputValue, 64)
 	output = output / qtumDiv
+
+	confirmations := transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: *transport.ConfirmThresholdValue < transaction.Confirmations,
+		Value:     transport.NewInt64(transaction.Confirmations),
+	}
+
+	// the explorer reports no confirmations for transactions still in the mempool.
+	if transaction.Confirmations == 0 {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  transaction.Inputs[0].Address,
-				To:    transaction.Outputs[0].Address,
-				Value: fmt.Sprintf("%f", output),
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: *transport.ConfirmThresholdValue < transaction.Confirmations,
-					Value:     transport.NewInt64(transaction.Confirmations),
-				},
+				ID:            hash,
+				From:          transaction.Inputs[0].Address,
+				To:            transaction.Outputs[0].Address,
+				Value:         fmt.Sprintf("%f", output),
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
@@ -95,8 +95,12 @@ var _ = Describe("QtumClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("QTUM"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("QTUM"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": Equal("-10000000"),
+							"Locked":      Equal("40000000"),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -129,10 +133,46 @@ var _ = Describe("QtumClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(686))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#ListUTXOs", func() {
+		It("Should return the unspent outputs with enough confirmations", func() {
+			addr := "QjZ8F2hR2CwhDBNLkFzjPPRnTCe7dDoUFq"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/api/address/" + addr + "/utxo",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("qtum/res/getutxos.json", addr, addr)),
+				ResponseCode: http.StatusOK,
+			})
+
+			res, err := client.(transport.UTXOLister).ListUTXOs(addr, 6)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.UTXOs).To(ConsistOf(
+				MatchAllFields(Fields{
+					"TxID":          Equal("3c7d6e4a1f0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d"),
+					"Vout":          Equal(uint32(1)),
+					"Amount":        Equal(int64(150000000)),
+					"Script":        Equal("76a914a1b2c3d4e5f60718293a4b5c6d7e8f9012345678988ac"),
+					"Confirmations": Equal(int64(25)),
+				}),
+			))
+		})
+	})
 })
@@ -1,10 +1,13 @@
 package transport
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -64,6 +67,8 @@ type RippleGetInfoResponse struct {
 			ValidatedLedger  RippleLedger `json:"validated_ledger"`
 			ClosedLedger     RippleLedger `json:"closed_ledger"`
 			ValidationQuorum int          `json:"validation_quorum"`
+			// NetworkID is omitted by mainnet nodes.
+			NetworkID int `json:"network_id"`
 		} `json:"info"`
 		Status string `json:"status"`
 	} `json:"result"`
@@ -84,7 +89,7 @@ type RippleTxResponse struct {
 		Account         string          `json:"Account"`
 		Amount          json.RawMessage `json:"Amount"`
 		Destination     string          `json:"Destination"`
-		DestinationTag  int             `json:"DestinationTag"`
+		DestinationTag  *uint32         `json:"DestinationTag"`
 		Fee             string          `json:"Fee"`
 		Flags           int64           `json:"Flags"`
 		Sequence        int             `json:"Sequence"`
@@ -116,7 +121,9 @@ type RippleTxResponse struct {
 			} `json:"AffectedNodes"`
 			TransactionIndex  int    `json:"TransactionIndex"`
 			TransactionResult string `json:"TransactionResult"`
-			DeliveredAmount   string `json:"delivered_amount"`
+			// DeliveredAmount is the amount the payment actually delivered, which is less than its Amount for
+			// partial payments. It's "unavailable" for payments validated before it was recorded.
+			DeliveredAmount json.RawMessage `json:"delivered_amount"`
 		} `json:"meta"`
 		Status    string `json:"status"`
 		Validated bool   `json:"validated"`
@@ -150,6 +157,27 @@ type RippleAccountInfoResponse struct {
 	} `json:"result"`
 }
 
+// RippleAccountLinesResponse defines a struct that represents the json response from a successful account_lines call.
+// Marker is set when the account has more trust lines than were returned.
+type RippleAccountLinesResponse struct {
+	Result struct {
+		Account string            `json:"account"`
+		Lines   []RippleTrustLine `json:"lines"`
+		Marker  json.RawMessage   `json:"marker"`
+		Status  string            `json:"status"`
+	} `json:"result"`
+}
+
+// RippleTrustLine is a trust line of an account, Account being the counterparty which issued the currency held.
+// The balance is negative when it's the account which owes the counterparty.
+type RippleTrustLine struct {
+	Account   string `json:"account"`
+	Balance   string `json:"balance"`
+	Currency  string `json:"currency"`
+	Limit     string `json:"limit"`
+	LimitPeer string `json:"limit_peer"`
+}
+
 // RippleRPCRequest defines a common rpc request json.
 type RippleRPCRequest struct {
 	Method string        `json:"method"`
@@ -162,6 +190,13 @@ type RippleGetBalanceParams struct {
 	Account string `json:"account"`
 }
 
+// RippleAccountLinesParams defines a struct that represents the json to be used under the
+// rpc params in a account_lines call.
+type RippleAccountLinesParams struct {
+	Account string          `json:"account"`
+	Marker  json.RawMessage `json:"marker,omitempty"`
+}
+
 // RippleTxParams defines a struct that represents the json to be used under the
 // rpc params in a tx call.
 type RippleTxParams struct {
@@ -175,7 +210,11 @@ type RippleClient struct {
 
 // NewRippleClient returns a new client using os variables.
 func NewRippleClient() (*RippleClient, error) {
-	u, err := url.Parse(getNodeURL("RIPPLE_URL"))
+	return newRippleClient(MainNet)
+}
+
+func newRippleClient(network string) (*RippleClient, error) {
+	u, err := url.Parse(getNodeURL(networkEnv("RIPPLE", network) + "_URL"))
 	if err != nil {
 		return nil, err
 	}
@@ -212,14 +251,29 @@ func (rc RippleClient) GetInfo() (*transport.CoinState, error) {
 
 	return &transport.CoinState{
 		Data: transport.CoinData{
-			Chain:        "main",
+			Chain:        rippleChain(info.Result.Info.NetworkID),
 			BlockHeight:  count,
 			CurrentBlock: hash,
 		},
 	}, nil
 }
 
-// GetBalance returns the balance of the address.
+// rippleChain names the network with the id reported by the node, see
+// https://xrpl.org/docs/references/protocol/transactions/common-fields#networkid-field
+func rippleChain(networkID int) string {
+	switch networkID {
+	case 0:
+		return "main"
+	case 1:
+		return "test"
+	case 2:
+		return "dev"
+	default:
+		return strconv.Itoa(networkID)
+	}
+}
+
+// GetBalance returns the XRP balance of the address, followed by the balances of its trust lines.
 func (rc RippleClient) GetBalance(addr string) (*transport.Balance, error) {
 	var info RippleAccountInfoResponse
 
@@ -240,20 +294,70 @@ func (rc RippleClient) GetBalance(addr string) (*transport.Balance, error) {
 		return nil, err
 	}
 
-	value := strconv.FormatFloat(float64(i)/float64(dropFactor), 'f', -1, 64)
+	assets := []transport.Asset{
+		{
+			Asset:   RippleAssetID,
+			Balance: format(i),
+		},
+	}
+
+	lines, err := rc.trustLines(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		value, err := rippleIOUValue(line.Balance)
+		if err != nil {
+			return nil, err
+		}
+
+		assets = append(assets, transport.Asset{
+			Asset:   rippleCurrency(line.Currency),
+			Balance: value,
+			Issuer:  line.Account,
+		})
+	}
 
 	return &transport.Balance{
 		Data: transport.BalanceData{
-			Assets: []transport.Asset{
-				{
-					Asset:   RippleAssetID,
-					Balance: value,
-				},
-			},
+			Assets: assets,
 		},
 	}, nil
 }
 
+// trustLines returns every trust line of the address, following the markers of account_lines.
+func (rc RippleClient) trustLines(addr string) ([]RippleTrustLine, error) {
+	var (
+		lines  []RippleTrustLine
+		marker json.RawMessage
+	)
+
+	for {
+		var res RippleAccountLinesResponse
+
+		err := rc.POST(&RippleRPCRequest{
+			Method: "account_lines",
+			Params: []interface{}{
+				RippleAccountLinesParams{
+					Account: addr,
+					Marker:  marker,
+				},
+			},
+		}, "/", &res)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, res.Result.Lines...)
+
+		marker = res.Result.Marker
+		if len(marker) == 0 || string(marker) == "null" {
+			return lines, nil
+		}
+	}
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (rc RippleClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var info RippleTxResponse
@@ -270,52 +374,120 @@ func (rc RippleClient) GetTransactionByHash(hash string) (*transport.Transaction
 		return nil, err
 	}
 
-	value, err := getTransactionValue(info.Result.Amount)
+	amount := info.Result.Amount
+	if delivered := info.Result.Meta.DeliveredAmount; len(delivered) > 0 && string(delivered) != `"unavailable"` {
+		amount = delivered
+	}
+
+	value, iou, err := getTransactionValue(amount)
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting ripple transaction value from raw messag")
 	}
 
+	confirmations := transport.Confirmations{
+		Confirmed: true,
+	}
+
+	// transactions which aren't in a validated ledger yet may still be dropped or reordered.
+	if !info.Result.Validated {
+		confirmations = transport.PendingConfirmations()
+	}
+
+	tx := transport.Transaction{
+		ID:             info.Result.Hash,
+		From:           info.Result.Account,
+		To:             info.Result.Destination,
+		Value:          value,
+		Confirmations:  confirmations,
+		DestinationTag: info.Result.DestinationTag,
+		// tec results are included in a ledger to claim the fee but the rest of the transaction isn't applied.
+		Reverted: strings.HasPrefix(info.Result.Meta.TransactionResult, "tec"),
+	}
+
+	if iou != nil {
+		tx.Transfers = []transport.Transfer{
+			{
+				From:   tx.From,
+				To:     tx.To,
+				Value:  value,
+				Asset:  rippleCurrency(iou.Currency),
+				Issuer: iou.Issuer,
+			},
+		}
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
-			Transaction: transport.Transaction{
-				ID:    info.Result.Hash,
-				From:  info.Result.Account,
-				To:    info.Result.Destination,
-				Value: value,
-				Confirmations: transport.Confirmations{
-					Confirmed: info.Result.Validated,
-				},
-			},
+			Transaction: tx,
 		},
 	}, nil
 }
 
-func getTransactionValue(amount json.RawMessage) (string, error) {
-	var res RippleComplexAmount
-	err := json.Unmarshal(amount, &res)
-	if err == nil {
-		i, err := strconv.ParseInt(res.Value, 10, 64)
+// getTransactionValue returns the value of the amount, which is either a string of drops of XRP or the amount of
+// an issued currency, returned along with the value. Issued currency values are decimals so aren't scaled by the
+// drop factor.
+func getTransactionValue(amount json.RawMessage) (string, *RippleComplexAmount, error) {
+	var str string
+	if err := json.Unmarshal(amount, &str); err == nil {
+		i, err := strconv.ParseInt(str, 10, 64)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 
-		return format(i), nil
+		return format(i), nil, nil
 	}
 
-	var str string
-	err = json.Unmarshal(amount, &str)
+	var res RippleComplexAmount
+	if err := json.Unmarshal(amount, &res); err != nil {
+		return "", nil, err
+	}
+
+	value, err := rippleIOUValue(res.Value)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	i, err := strconv.ParseInt(str, 10, 64)
+	return value, &res, nil
+}
+
+// rippleIOUValue normalises the value of an issued currency, which is given in scientific notation when its
+// exponent is large, e.g. "1e-20".
+func rippleIOUValue(value string) (string, error) {
+	f, ok := new(big.Float).SetString(value)
+	if !ok {
+		return "", errors.Errorf("invalid issued currency value: %s", value)
+	}
+
+	return f.Text('f', -1), nil
+}
+
+// rippleCurrency returns the code of the currency, decoding the 160 bit hex codes of nonstandard currencies
+// which hold an ascii code longer than three characters.
+func rippleCurrency(code string) string {
+	if len(code) != 40 {
+		return code
+	}
+
+	b, err := hex.DecodeString(code)
 	if err != nil {
-		return "", err
+		return code
+	}
+
+	// codes starting with a zero byte are reserved, e.g. for the standard format.
+	ascii := strings.TrimRight(string(b), "\x00")
+	if ascii == "" || b[0] == 0 {
+		return code
+	}
+
+	for _, c := range ascii {
+		if c < 0x20 || c > 0x7e {
+			return code
+		}
 	}
 
-	return format(i), nil
+	return ascii
 }
 
 func format(i int64) string {
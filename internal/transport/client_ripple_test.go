@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
 
 	. "github.com/hugorut/coins-oracle/internal/transport"
 	"github.com/hugorut/coins-oracle/pkg/test"
@@ -63,6 +64,17 @@ var _ = Describe("RippleClient", func() {
 				ResponseCode: http.StatusOK,
 			})
 
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ripple/req/accountlines.json", addr)),
+				Response:     MustLoad(fb.LoadFixture("ripple/res/accountlines_empty.json", addr)),
+				ResponseCode: http.StatusOK,
+			})
+
 			balance, err := client.GetBalance(addr)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -70,13 +82,57 @@ var _ = Describe("RippleClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("XRP"),
-							"Balance": Equal("153.881"),
+							"Asset":       Equal("XRP"),
+							"Balance":     Equal("153.881"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
 			})))
 		})
+
+		It("Should return the balances of the trust lines of the address", func() {
+			addr := "rLgBm6vum6YLS3j88Cv7F27pR3FbJssuph"
+			marker := "AB52B0A1E1AFD2D5E07B1B6C52EFB3F8C84F5A0E0B4C8AA2F9F8B4A5A2BE5B10"
+
+			for _, call := range []struct{ req, res string }{
+				{MustLoad(fb.LoadFixture("ripple/req/getbalance.json", addr)), MustLoad(fb.LoadFixture("ripple/res/getbalance.json", addr, "25000000"))},
+				{MustLoad(fb.LoadFixture("ripple/req/accountlines.json", addr)), MustLoad(fb.LoadFixture("ripple/res/accountlines.json", addr, marker))},
+				{MustLoad(fb.LoadFixture("ripple/req/accountlines_marker.json", addr, marker)), MustLoad(fb.LoadFixture("ripple/res/accountlines_marker.json", addr))},
+			} {
+				mockServer.Expect(test.ExpectedCall{
+					Path:   "/",
+					Method: "POST",
+					Headers: map[string]string{
+						"Content-Type": "Application/Json",
+					},
+					Body:         call.req,
+					Response:     call.res,
+					ResponseCode: http.StatusOK,
+				})
+			}
+
+			balance, err := client.GetBalance(addr)
+			Expect(err).ToNot(HaveOccurred())
+
+			asset := func(asset, balance, issuer string) types.GomegaMatcher {
+				return MatchFields(IgnoreExtras, Fields{
+					"Asset":   Equal(asset),
+					"Balance": Equal(balance),
+					"Issuer":  Equal(issuer),
+				})
+			}
+
+			Expect(balance.Data.Assets).To(ConsistOf(
+				asset("XRP", "25", ""),
+				asset("USD", "12.5", "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"),
+				asset("SOLO", "0.00000025", "rsoLo2S1kiGeCcn6hCUXVrCpGMWLrRrLZz"),
+				asset("EUR", "-40", "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY"),
+			))
+		})
 	})
 
 	Describe("#GetInfo", func() {
@@ -105,6 +161,23 @@ var _ = Describe("RippleClient", func() {
 				}),
 			})))
 		})
+
+		It("Should report the network the node is connected to", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("ripple/req/getinfo.json")),
+				Response:     MustLoad(fb.LoadFixture("ripple/res/getinfo_testnet.json")),
+				ResponseCode: http.StatusOK,
+			})
+
+			info, err := client.GetInfo()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Data.Chain).To(Equal("test"))
+		})
 	})
 
 	Describe("#GetTransactionByHash", func() {
@@ -136,7 +209,17 @@ var _ = Describe("RippleClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": PointTo(Equal(uint32(56985))),
 					}),
 				}),
 			})))
@@ -166,16 +249,68 @@ var _ = Describe("RippleClient", func() {
 							"ID":    Equal(txID),
 							"From":  Equal("rf3B8KcYqKMgybB2ms9KcLhcB8bWX1UDov"),
 							"To":    Equal("rf3B8KcYqKMgybB2ms9KcLhcB8bWX1UDov"),
-							"Value": Equal("0.005001"),
+							"Value": Equal("5001"),
 							"Confirmations": MatchAllFields(Fields{
 								"Threshold": BeNil(),
 								"Confirmed": BeTrue(),
 								"Value":     BeNil(),
+								"Reorged":   BeFalse(),
+								"Pending":   BeFalse(),
 							}),
+							"Transfers": ConsistOf(
+								MatchAllFields(Fields{
+									"From":    Equal("rf3B8KcYqKMgybB2ms9KcLhcB8bWX1UDov"),
+									"To":      Equal("rf3B8KcYqKMgybB2ms9KcLhcB8bWX1UDov"),
+									"Value":   Equal("5001"),
+									"Asset":   Equal("ZCN"),
+									"TokenID": BeEmpty(),
+									"Issuer":  Equal("r8HgVGenRTAiNSM5iqt9PX2D2EczFZhZr"),
+								}),
+							),
+							// tecPATH_DRY payments claim their fee without delivering anything.
+							"Reverted":       BeTrue(),
+							"Fees":           BeNil(),
+							"Memo":           BeEmpty(),
+							"MemoType":       BeEmpty(),
+							"Coinbase":       BeFalse(),
+							"Outputs":        BeEmpty(),
+							"DestinationTag": BeNil(),
 						}),
 					}),
 				})))
 			})
 		})
+
+		Context("With a partial payment", func() {
+			It("Should return the amount delivered rather than the amount sent", func() {
+				txID := "B0B7B0F3E2B8A4E0DC1F4A2D8E6A3C5F7B9D1E3A5C7E9F1B3D5F7A9C1E3B5D7F"
+
+				mockServer.Expect(test.ExpectedCall{
+					Path:   "/",
+					Method: "POST",
+					Headers: map[string]string{
+						"Content-Type": "Application/Json",
+					},
+					Body:         MustLoad(fb.LoadFixture("ripple/req/gettransaction.json", txID)),
+					Response:     MustLoad(fb.LoadFixture("ripple/res/gettransaction_partialpayment.json", txID)),
+					ResponseCode: http.StatusOK,
+				})
+
+				tx, err := client.GetTransactionByHash(txID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tx.Data.Transaction).To(MatchFields(IgnoreExtras, Fields{
+					"Value":    Equal("12.5"),
+					"Reverted": BeFalse(),
+					"Transfers": ConsistOf(MatchFields(IgnoreExtras, Fields{
+						"Value":  Equal("12.5"),
+						"Asset":  Equal("USD"),
+						"Issuer": Equal("rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"),
+					})),
+					// a tag of zero is still a tag.
+					"DestinationTag": PointTo(BeZero()),
+				}))
+			})
+		})
 	})
 })
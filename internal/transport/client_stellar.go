@@ -1,9 +1,14 @@
 package transport
 
 import (
+	"context"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	"net/http"
 
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/protocols/horizon/effects"
 	"github.com/stellar/go/protocols/horizon/operations"
 
 	"github.com/stellar/go/clients/horizonclient"
@@ -20,9 +25,13 @@ type StellarClient struct {
 
 // NewStellarClient returns a new client using os variables.
 func NewStellarClient() (*StellarClient, error) {
+	return newStellarClient(MainNet)
+}
+
+func newStellarClient(network string) (*StellarClient, error) {
 	return &StellarClient{
 		Client: &horizonclient.Client{
-			HorizonURL: getNodeURL("STELLAR_URL"),
+			HorizonURL: getNodeURL(networkEnv("STELLAR", network) + "_URL"),
 			HTTP: &http.Client{
 				Timeout: transport.DefaultClientTimeout,
 			},
@@ -32,6 +41,11 @@ func NewStellarClient() (*StellarClient, error) {
 
 // GetInfo attempts to get standardised coin info from multiple rpc calls.
 func (s StellarClient) GetInfo() (*transport.CoinState, error) {
+	chain, err := s.chain()
+	if err != nil {
+		return nil, err
+	}
+
 	info, err := s.Client.Ledgers(horizonclient.LedgerRequest{
 		Order: "desc",
 		Limit: 1,
@@ -42,13 +56,60 @@ func (s StellarClient) GetInfo() (*transport.CoinState, error) {
 
 	return &transport.CoinState{
 		Data: transport.CoinData{
-			Chain:        "main",
+			Chain:        chain,
 			BlockHeight:  int(info.Embedded.Records[0].Sequence),
 			CurrentBlock: info.Embedded.Records[0].Hash,
 		},
 	}, nil
 }
 
+// SubscribeBlocks streams new ledgers from horizon, sending the chain info for every ledger closed.
+func (s StellarClient) SubscribeBlocks(ctx context.Context, blocks chan<- transport.CoinData) error {
+	chain, err := s.chain()
+	if err != nil {
+		return err
+	}
+
+	// streaming requests are long lived so can't use the client timeout.
+	client := &horizonclient.Client{
+		HorizonURL: s.Client.HorizonURL,
+		HTTP:       &http.Client{},
+	}
+
+	err = client.StreamLedgers(ctx, horizonclient.LedgerRequest{Cursor: "now"}, func(ledger hProtocol.Ledger) {
+		select {
+		case blocks <- transport.CoinData{
+			Chain:        chain,
+			BlockHeight:  int(ledger.Sequence),
+			CurrentBlock: ledger.Hash,
+		}:
+		case <-ctx.Done():
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// chain names the network horizon is connected to from its passphrase.
+func (s StellarClient) chain() (string, error) {
+	root, err := s.Client.Root()
+	if err != nil {
+		return "", err
+	}
+
+	switch root.NetworkPassphrase {
+	case network.PublicNetworkPassphrase:
+		return "main", nil
+	case network.TestNetworkPassphrase:
+		return "test", nil
+	default:
+		return root.NetworkPassphrase, nil
+	}
+}
+
 // GetBalance returns the balance of the address.
 func (s StellarClient) GetBalance(addr string) (*transport.Balance, error) {
 	acc, err := s.Client.AccountDetail(horizonclient.AccountRequest{
@@ -68,6 +129,9 @@ func (s StellarClient) GetBalance(addr string) (*transport.Balance, error) {
 		assets[key] = transport.Asset{
 			Asset:   code,
 			Balance: balance.Balance,
+			// selling liabilities are reserved by the account's open offers.
+			Locked: balance.SellingLiabilities,
+			Issuer: balance.Asset.Issuer,
 		}
 	}
 
@@ -78,7 +142,8 @@ func (s StellarClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
-// GetTransactionByHash returns the transaction stored at the given hash.
+// GetTransactionByHash returns the transaction stored at the given hash, with a transfer for every operation
+// of the transaction which moved funds. The transfers of failed transactions are returned with a zero value.
 func (s StellarClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	tx, err := s.Client.TransactionDetail(hash)
 	if err != nil {
@@ -87,28 +152,116 @@ func (s StellarClient) GetTransactionByHash(hash string) (*transport.Transaction
 
 	ops, err := s.Client.Operations(horizonclient.OperationRequest{
 		ForTransaction: tx.ID,
+		// a transaction holds at most 100 operations.
+		Limit: 100,
+		// horizon leaves out the operations of failed transactions unless they're asked for.
+		IncludeFailed: true,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	payment := ops.Embedded.Records[0].(operations.Payment)
+	transfers := []transport.Transfer{}
+	for _, op := range ops.Embedded.Records {
+		transfer, ok, err := s.operationTransfer(op)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		// none of the operations of a failed transaction were applied, so no funds moved.
+		if !tx.Successful {
+			transfer.Value = "0"
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	transaction := transport.Transaction{
+		ID:    tx.ID,
+		From:  tx.Account,
+		Value: "0",
+		Confirmations: transport.Confirmations{
+			// if the transaction appears on the ledger it is confirmed
+			// see https://stellar.stackexchange.com/questions/1464/is-a-payment-returned-through-horizon-api-call-payments-for-account-always-c
+			Confirmed: true,
+		},
+		// failed transactions are included in the ledger to charge their fee but none of their operations are applied.
+		Reverted:  !tx.Successful,
+		Transfers: transfers,
+	}
+
+	if len(transfers) > 0 {
+		transaction.From = transfers[0].From
+		transaction.To = transfers[0].To
+		transaction.Value = transfers[0].Value
+	}
+
+	if tx.MemoType != "none" {
+		transaction.Memo = tx.Memo
+		transaction.MemoType = tx.MemoType
+	}
 
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
-			Transaction: transport.Transaction{
-				ID:    tx.ID,
-				From:  payment.From,
-				To:    payment.To,
-				Value: payment.Amount,
-				Confirmations: transport.Confirmations{
-					// if the transaction appears on the ledger it is confirmed
-					// see https://stellar.stackexchange.com/questions/1464/is-a-payment-returned-through-horizon-api-call-payments-for-account-always-c
-					Confirmed: true,
-				},
-			},
+			Transaction: transaction,
 		},
 	}, nil
 }
+
+// operationTransfer returns the funds moved by the operation, ok being false for operations which don't move any.
+func (s StellarClient) operationTransfer(op operations.Operation) (transport.Transfer, bool, error) {
+	switch o := op.(type) {
+	case operations.CreateAccount:
+		return transport.Transfer{
+			From:  o.Funder,
+			To:    o.Account,
+			Value: o.StartingBalance,
+		}, true, nil
+	case operations.Payment:
+		return stellarTransfer(o.From, o.To, o.Amount, o.Asset), true, nil
+	case operations.PathPayment:
+		return stellarTransfer(o.From, o.To, o.Amount, o.Asset), true, nil
+	case operations.PathPaymentStrictSend:
+		return stellarTransfer(o.From, o.To, o.Amount, o.Asset), true, nil
+	case operations.AccountMerge:
+		// the balance merged isn't part of the operation so is read from the account credited by it.
+		effs, err := s.Client.Effects(horizonclient.EffectRequest{
+			ForOperation: o.ID,
+		})
+		if err != nil {
+			return transport.Transfer{}, false, err
+		}
+
+		for _, eff := range effs.Embedded.Records {
+			if credit, ok := eff.(effects.AccountCredited); ok {
+				return stellarTransfer(o.Account, o.Into, credit.Amount, credit.Asset), true, nil
+			}
+		}
+
+		// merges of failed transactions credit nothing.
+		return transport.Transfer{
+			From:  o.Account,
+			To:    o.Into,
+			Value: "0",
+		}, true, nil
+	default:
+		return transport.Transfer{}, false, nil
+	}
+}
+
+// stellarTransfer returns the transfer of an amount of the asset, which is left empty for lumens.
+func stellarTransfer(from, to, amount string, asset base.Asset) transport.Transfer {
+	return transport.Transfer{
+		From:   from,
+		To:     to,
+		Value:  amount,
+		Asset:  asset.Code,
+		Issuer: asset.Issuer,
+	}
+}
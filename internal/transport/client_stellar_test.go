@@ -50,6 +50,11 @@ var _ = Describe("StellarClient", func() {
 			bestBlockHash := "15040c1d611cdc88946b47baf19ae78ab1d842487b0e61586de3848065bd3f45"
 
 			mockServer.Expect(test.ExpectedCall{
+				Path:         "/",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("stellar/res/root.json", "Public Global Stellar Network ; September 2015")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
 				Path:   "/ledgers",
 				Method: http.MethodGet,
 				QueryParams: map[string]string{
@@ -71,6 +76,28 @@ var _ = Describe("StellarClient", func() {
 				}),
 			})))
 		})
+
+		It("Should report the test network from horizon's passphrase", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("stellar/res/root.json", "Test SDF Network ; September 2015")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/ledgers",
+				Method: http.MethodGet,
+				QueryParams: map[string]string{
+					"limit": "1",
+					"order": "desc",
+				},
+				Response:     MustLoad(fb.LoadFixture("stellar/res/getinfo.json", "15040c1d611cdc88946b47baf19ae78ab1d842487b0e61586de3848065bd3f45")),
+				ResponseCode: http.StatusOK,
+			})
+
+			info, err := client.GetInfo()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Data.Chain).To(Equal("test"))
+		})
 	})
 
 	Describe("#GetBalance", func() {
@@ -91,20 +118,36 @@ var _ = Describe("StellarClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("BTC"),
-							"Balance": Equal("0.0000000"),
+							"Asset":       Equal("BTC"),
+							"Balance":     Equal("0.0000000"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("0.0000000"),
+							"Issuer":      Equal("GBVOL67TMUQBGL4TZYNMY3ZQ5WGQYFPFD5VJRWXR72VA33VFNL225PL5"),
 						}),
 						MatchAllFields(Fields{
-							"Asset":   Equal("NRV"),
-							"Balance": Equal("0.0000000"),
+							"Asset":       Equal("NRV"),
+							"Balance":     Equal("0.0000000"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("0.0000000"),
+							"Issuer":      Equal("GANRAE2FXMIU4V7CPLXFHWZNGCCSW7WEVBN2P3ZWA7FWWVED6OJSKKX2"),
 						}),
 						MatchAllFields(Fields{
-							"Asset":   Equal("ETH"),
-							"Balance": Equal("0.0000000"),
+							"Asset":       Equal("ETH"),
+							"Balance":     Equal("0.0000000"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("0.0000000"),
+							"Issuer":      Equal("GBVOL67TMUQBGL4TZYNMY3ZQ5WGQYFPFD5VJRWXR72VA33VFNL225PL5"),
 						}),
 						MatchAllFields(Fields{
-							"Asset":   Equal("XLM"),
-							"Balance": Equal("249.6635636"),
+							"Asset":       Equal("XLM"),
+							"Balance":     Equal("249.6635636"),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("12.5000000"),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -142,10 +185,112 @@ var _ = Describe("StellarClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers": ConsistOf(
+							MatchAllFields(Fields{
+								"From":    Equal("GDC35NCQORRH7DDIZ4GHR4OB3V7B35V7CRRIWXTUYNKJFBFPUTBPSCOE"),
+								"To":      Equal("GBF2RYH7OJOW63HI3CCIF5R7EPK257A3EN6ILH5OGUCJIMR4Z23U6P5V"),
+								"Value":   Equal("5.0000000"),
+								"Asset":   Equal("MFN"),
+								"TokenID": BeEmpty(),
+								"Issuer":  Equal("GC55P5MTVPOPPY7NBBS5RPRUF5K3667ZQ2GN4J5GGE6AZVLPC72S5K46"),
+							}),
+						),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           Equal("2:2478"),
+						"MemoType":       Equal("text"),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
+
+		Context("With several operations", func() {
+			var txID string
+
+			expectCalls := func(successful, effects string) {
+				mockServer.Expect(test.ExpectedCall{
+					Path:         "/transactions/" + txID,
+					Method:       http.MethodGet,
+					Response:     MustLoad(fb.LoadFixture("stellar/res/gettransaction_multiop.json", txID, successful)),
+					ResponseCode: http.StatusOK,
+				}).Then(test.ExpectedCall{
+					Path:   "/transactions/" + txID + "/operations",
+					Method: http.MethodGet,
+					QueryParams: map[string]string{
+						"limit":          "100",
+						"include_failed": "true",
+					},
+					Response:     MustLoad(fb.LoadFixture("stellar/res/operations_multiop.json", txID, successful)),
+					ResponseCode: http.StatusOK,
+				}).Then(test.ExpectedCall{
+					Path:         "/operations/112478677722025988/effects",
+					Method:       http.MethodGet,
+					Response:     MustLoad(fb.LoadFixture(effects)),
+					ResponseCode: http.StatusOK,
+				})
+			}
+
+			BeforeEach(func() {
+				txID = "8c1f7a0e3b2d4c5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6"
+			})
+
+			It("Should return a transfer for every operation which moved funds", func() {
+				expectCalls("true", "stellar/res/effects_merge.json")
+
+				tx, err := client.GetTransactionByHash(txID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tx.Data.Transaction).To(MatchFields(IgnoreExtras, Fields{
+					"ID":       Equal(txID),
+					"From":     Equal("GDC35NCQORRH7DDIZ4GHR4OB3V7B35V7CRRIWXTUYNKJFBFPUTBPSCOE"),
+					"To":       Equal("GBF2RYH7OJOW63HI3CCIF5R7EPK257A3EN6ILH5OGUCJIMR4Z23U6P5V"),
+					"Value":    Equal("10.0000000"),
+					"Reverted": BeFalse(),
+					"Memo":     Equal("3141592653"),
+					"MemoType": Equal("id"),
+				}))
+
+				Expect(tx.Data.Transaction.Transfers).To(Equal([]transport.Transfer{
+					{
+						From:  "GDC35NCQORRH7DDIZ4GHR4OB3V7B35V7CRRIWXTUYNKJFBFPUTBPSCOE",
+						To:    "GBF2RYH7OJOW63HI3CCIF5R7EPK257A3EN6ILH5OGUCJIMR4Z23U6P5V",
+						Value: "10.0000000",
+					},
+					{
+						From:   "GDC35NCQORRH7DDIZ4GHR4OB3V7B35V7CRRIWXTUYNKJFBFPUTBPSCOE",
+						To:     "GBF2RYH7OJOW63HI3CCIF5R7EPK257A3EN6ILH5OGUCJIMR4Z23U6P5V",
+						Value:  "41.2500000",
+						Asset:  "MFN",
+						Issuer: "GC55P5MTVPOPPY7NBBS5RPRUF5K3667ZQ2GN4J5GGE6AZVLPC72S5K46",
+					},
+					{
+						From:  "GA4MTF3WRJE7I6TSP66PYXBEIS3PPUZEHJYTBMBNAPBBL3TWQWLAZZDW",
+						To:    "GDC35NCQORRH7DDIZ4GHR4OB3V7B35V7CRRIWXTUYNKJFBFPUTBPSCOE",
+						Value: "249.6635236",
+					},
+				}))
+			})
+
+			It("Should return a failed transaction as reverted", func() {
+				expectCalls("false", "stellar/res/effects_empty.json")
+
+				tx, err := client.GetTransactionByHash(txID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tx.Data.Transaction.Reverted).To(BeTrue())
+				Expect(tx.Data.Transaction.Confirmations.Confirmed).To(BeTrue())
+				Expect(tx.Data.Transaction.Value).To(Equal("0"))
+				Expect(tx.Data.Transaction.Transfers).To(HaveLen(3))
+				for _, transfer := range tx.Data.Transaction.Transfers {
+					Expect(transfer.Value).To(Equal("0"))
+				}
+			})
+		})
 	})
 })
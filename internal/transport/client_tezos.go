@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -107,6 +108,13 @@ type TezosGetBalanceResponse struct {
 	Counter string `json:"counter"`
 }
 
+// TezosBlockHeaderResponse is a struct representing a block header JSON response.
+type TezosBlockHeaderResponse struct {
+	Hash      string    `json:"hash"`
+	Level     int       `json:"level"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // TezosGetTransactionResponse is a struct representing a tx JSON response.
 type TezosGetTransactionResponse []struct {
 	Tx struct {
@@ -221,6 +229,37 @@ func (b TezosClient) GetBalance(addr string) (*transport.Balance, error) {
 	}, nil
 }
 
+// GetBalanceAt returns the balance of the address from the context of the block at the given level.
+func (b TezosClient) GetBalanceAt(addr string, height int64) (*transport.Balance, error) {
+	var balance TezosGetBalanceResponse
+
+	if err := b.GET(fmt.Sprintf("/chains/main/blocks/%d/context/contracts/%s", height, addr), nil, &balance); err != nil {
+		return nil, errors.Wrapf(err, "error getting tezos balance at level: %d", height)
+	}
+
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{
+				{
+					Asset:   TezosAssetID,
+					Balance: balance.Balance,
+				},
+			},
+		},
+	}, nil
+}
+
+// GetBlockTime returns the time the block at the given level was baked.
+func (b TezosClient) GetBlockTime(height int64) (time.Time, error) {
+	var header TezosBlockHeaderResponse
+
+	if err := b.GET(fmt.Sprintf("/chains/main/blocks/%d/header", height), nil, &header); err != nil {
+		return time.Time{}, errors.Wrapf(err, "error getting tezos block header at level: %d", height)
+	}
+
+	return header.Timestamp, nil
+}
+
 // GetTransactionByHash returns the transaction stored at the given hash.
 func (b TezosClient) GetTransactionByHash(hash string) (*transport.TransactionResp, error) {
 	var txs TezosGetTransactionResponse
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -95,8 +96,12 @@ var _ = Describe("TezosClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("XTZ"),
-							"Balance": Equal(balRes),
+							"Asset":       Equal("XTZ"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -132,10 +137,67 @@ var _ = Describe("TezosClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBalanceAt", func() {
+		It("Should return the Tezos balance from the context of the given level", func() {
+			addr := "address"
+			balRes := "88348"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/chains/main/blocks/800000/context/contracts/" + addr,
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("tezos/res/getbalance.json", balRes)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balance, err := client.(transport.HistoricalBalanceGetter).GetBalanceAt(addr, 800000)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance).To(PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"Assets": ConsistOf(
+						MatchAllFields(Fields{
+							"Asset":       Equal("XTZ"),
+							"Balance":     Equal(balRes),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      BeEmpty(),
+							"Issuer":      BeEmpty(),
+						}),
+					),
+				}),
+			})))
+		})
+	})
+
+	Describe("#GetBlockTime", func() {
+		It("Should return the timestamp of the block header at the given level", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/chains/main/blocks/800000/header",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("tezos/res/getblockheader.json", 800000, "2020-02-09T12:13:17Z")),
+				ResponseCode: http.StatusOK,
+			})
+
+			t, err := client.(transport.HistoricalBalanceGetter).GetBlockTime(800000)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2020, time.February, 9, 12, 13, 17, 0, time.UTC)))
+		})
+	})
 })
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
 
 	"github.com/hugorut/coins-oracle/pkg/transport"
 )
@@ -69,6 +70,14 @@ type TronGetInfoResponse struct {
 	} `json:"block_header"`
 }
 
+// TronGetBlockResponse represents a successful json response from the getblockbynum and getblockbyid endpoints.
+type TronGetBlockResponse struct {
+	TronGetInfoResponse
+	Transactions []struct {
+		TxID string `json:"txID"`
+	} `json:"transactions"`
+}
+
 // TronGetBalanceResponse represents a successful json response getaccount endpoint.
 type TronGetBalanceResponse struct {
 	AccountName string `json:"account_name"`
@@ -114,6 +123,14 @@ type TronGetTXReq struct {
 	Value string `json:"value"`
 }
 
+// TronGetBlockByNumReq represents a json body for the getblockbynum.
+type TronGetBlockByNumReq struct {
+	Num int64 `json:"num"`
+}
+
+// tronMainnetGenesis is the id of the first block of mainnet, test networks each having their own.
+const tronMainnetGenesis = "00000000000000001ebf88508a03865c71d452e25f4d51194196a1d22b6653dc"
+
 // TronClient is the Tron implementation of the CoinClient
 type TronClient struct {
 	transport.BaseClient
@@ -121,7 +138,11 @@ type TronClient struct {
 
 // NewTronClient returns a new client using os variables.
 func NewTronClient() (*TronClient, error) {
-	u, err := url.Parse(getNodeURL("TRON_URL"))
+	return newTronClient(MainNet)
+}
+
+func newTronClient(network string) (*TronClient, error) {
+	u, err := url.Parse(getNodeURL(networkEnv("TRON", network) + "_URL"))
 	if err != nil {
 		return nil, err
 	}
@@ -145,15 +166,72 @@ func (t TronClient) GetInfo() (*transport.CoinState, error) {
 		return nil, err
 	}
 
+	// nodes don't report their network so it's told apart by the genesis block.
+	var genesis TronGetInfoResponse
+	if err := t.POST(TronGetBlockByNumReq{Num: 0}, "/wallet/getblockbynum", &genesis); err != nil {
+		return nil, err
+	}
+
+	chain := "main"
+	if genesis.BlockID != tronMainnetGenesis {
+		chain = "test"
+	}
+
 	return &transport.CoinState{
 		Data: transport.CoinData{
-			Chain:        "main",
+			Chain:        chain,
 			BlockHeight:  info.BlockHeader.RawData.Number,
 			CurrentBlock: info.BlockID,
 		},
 	}, nil
 }
 
+// GetBlockByHeight returns the block at the given height.
+func (t TronClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	var block TronGetBlockResponse
+	if err := t.POST(TronGetBlockByNumReq{Num: height}, "/wallet/getblockbynum", &block); err != nil {
+		return nil, err
+	}
+
+	return t.block(block)
+}
+
+// GetBlockByHash returns the block with the given id.
+func (t TronClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	var block TronGetBlockResponse
+	if err := t.POST(TronGetTXReq{Value: hash}, "/wallet/getblockbyid", &block); err != nil {
+		return nil, err
+	}
+
+	return t.block(block)
+}
+
+func (t TronClient) block(block TronGetBlockResponse) (*transport.BlockResp, error) {
+	// the node returns an empty object when the block does not exist.
+	if block.BlockID == "" {
+		return nil, errors.New("block not found")
+	}
+
+	ids := make([]string, len(block.Transactions))
+	for key, value := range block.Transactions {
+		ids[key] = value.TxID
+	}
+
+	header := block.BlockHeader.RawData
+
+	res := &transport.BlockResp{}
+	res.Data.Block = transport.Block{
+		Height:    int64(header.Number),
+		Hash:      block.BlockID,
+		Parent:    header.ParentHash,
+		Timestamp: header.Timestamp / 1000,
+		TxCount:   len(ids),
+		TxIDs:     ids,
+	}
+
+	return res, nil
+}
+
 // GetBalance returns the balance of the address.
 func (t TronClient) GetBalance(addr string) (*transport.Balance, error) {
 	var acc TronGetBalanceResponse
@@ -162,12 +240,19 @@ func (t TronClient) GetBalance(addr string) (*transport.Balance, error) {
 		return nil, err
 	}
 
+	// frozen balances are staked for bandwidth or energy and can't be spent until they expire.
+	var frozen int
+	for _, value := range acc.Frozen {
+		frozen += value.FrozenBalance
+	}
+
 	return &transport.Balance{
 		Data: transport.BalanceData{
 			Assets: []transport.Asset{
 				{
 					Asset:   TronAssetID,
 					Balance: fmt.Sprintf("%d", acc.Balance),
+					Locked:  fmt.Sprintf("%d", frozen),
 				},
 			},
 		},
@@ -194,20 +279,27 @@ func (t TronClient) GetTransactionByHash(hash string) (*transport.TransactionRes
 	txData := tx.RawData.Contract[0].Parameter.Value
 	included := transport.NewInt64(int64(latest.BlockHeader.RawData.Number) - int64(info.BlockNumber))
 
+	confirmations := transport.Confirmations{
+		Threshold: transport.ConfirmThresholdValue,
+		Confirmed: *included >= *transport.ConfirmThresholdValue,
+		Value:     included,
+	}
+
+	// the node returns an empty info object until the transaction is included in a block.
+	if info.BlockNumber == 0 {
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    tx.TxID,
-				From:  hexToBase58(txData.OwnerAddress),
-				To:    hexToBase58(txData.ToAddress),
-				Value: fmt.Sprintf("%d", txData.Amount),
-				Confirmations: transport.Confirmations{
-					Threshold: transport.ConfirmThresholdValue,
-					Confirmed: *included >= *transport.ConfirmThresholdValue,
-					Value:     included,
-				},
+				ID:            tx.TxID,
+				From:          hexToBase58(txData.OwnerAddress),
+				To:            hexToBase58(txData.ToAddress),
+				Value:         fmt.Sprintf("%d", txData.Amount),
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
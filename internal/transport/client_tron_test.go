@@ -72,8 +72,12 @@ var _ = Describe("TronClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("TRX"),
-							"Balance": Equal(fmt.Sprintf("%d", balRes)),
+							"Asset":       Equal("TRX"),
+							"Balance":     Equal(fmt.Sprintf("%d", balRes)),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("7000000"),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -93,6 +97,15 @@ var _ = Describe("TronClient", func() {
 				},
 				Response:     MustLoad(fb.LoadFixture("tron/res/getinfo.json", bestBlockHash)),
 				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/wallet/getblockbynum",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/getblockbynum.json", 0)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/getblock.json", "00000000000000001ebf88508a03865c71d452e25f4d51194196a1d22b6653dc")),
+				ResponseCode: http.StatusOK,
 			})
 
 			info, err := client.GetInfo()
@@ -106,6 +119,33 @@ var _ = Describe("TronClient", func() {
 				}),
 			})))
 		})
+
+		It("Should report a test network when the genesis block isn't mainnet's", func() {
+			bestBlockHash := "0000000000006a5011fe7c20bf354549138002e77f1035d6b301dc20757ba8c4"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/wallet/getnowblock",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Response:     MustLoad(fb.LoadFixture("tron/res/getinfo.json", bestBlockHash)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/wallet/getblockbynum",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/getblockbynum.json", 0)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/getblock.json", "0000000000000000d698d4192c56cb6be724a558448e2684802de4d6cd8690dc")),
+				ResponseCode: http.StatusOK,
+			})
+
+			info, err := client.GetInfo()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Data.Chain).To(Equal("test"))
+		})
 	})
 
 	Describe("#GetTransactionByHash", func() {
@@ -155,10 +195,132 @@ var _ = Describe("TronClient", func() {
 							"Threshold": PointTo(Equal(int64(5))),
 							"Confirmed": BeTrue(),
 							"Value":     PointTo(Equal(int64(19))),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the Tron block transformed to the common output", func() {
+			blockID := "0000000000006a50d1f0b2f0d2a5b5b7c5b2a6a1a2e3b3d4c4b6a7a8a9e0f1f2"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/wallet/getblockbynum",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/getblockbynum.json", 27216)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/getblock.json", blockID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(27216)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":       Equal(int64(27216)),
+				"Hash":         Equal(blockID),
+				"Parent":       Equal("0000000000006a4fc3bb4fc54ec07747c7edfd93ff029a5b8721a7227629dcd7"),
+				"Timestamp":    Equal(int64(1529973210)),
+				"TxCount":      Equal(1),
+				"TxIDs":        Equal([]string{"2a7d53c3b4fa4c0ee5c3f1bd2b5e5a40dd1bd54b15a2ffb0ec87fb7bcb9b1d5b"}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+
+		It("Should return an error if the block does not exist", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/wallet/getblockbynum",
+				Method:       "POST",
+				Body:         MustLoad(fb.LoadFixture("tron/req/getblockbynum.json", 99999999)),
+				Response:     "{}",
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.(transport.BlockFetcher).GetBlockByHeight(99999999)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should return the Tron block with the id", func() {
+			blockID := "0000000000006a50d1f0b2f0d2a5b5b7c5b2a6a1a2e3b3d4c4b6a7a8a9e0f1f2"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/wallet/getblockbyid",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/gettransaction.json", blockID)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/getblock.json", blockID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHash(blockID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block.Hash).To(Equal(blockID))
+			Expect(block.Data.Block.Height).To(Equal(int64(27216)))
+		})
+	})
+
+	Describe("#GetTransactionByHash with an unconfirmed transaction", func() {
+		It("Should report the transaction as pending", func() {
+			txID := "0ab16c340e85d52c1179aceca5133e711a5850f4ea42d5bac2e8929a3330551d"
+			bestBlockHash := "0000000000006a5011fe7c20bf354549138002e77f1035d6b301dc20757ba8c4"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:   "/wallet/gettransactionbyid",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/gettransaction.json", txID)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/gettransaction.json", txID)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/wallet/gettransactioninfobyid",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Body:         MustLoad(fb.LoadFixture("tron/req/gettransactioninfo.json", txID)),
+				Response:     MustLoad(fb.LoadFixture("tron/res/gettransactioninfo_pending.json")),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:   "/wallet/getnowblock",
+				Method: "POST",
+				Headers: map[string]string{
+					"Content-Type": "Application/Json",
+				},
+				Response:     MustLoad(fb.LoadFixture("tron/res/getinfo.json", bestBlockHash)),
+				ResponseCode: http.StatusOK,
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx.Data.Transaction.Confirmations).To(MatchAllFields(Fields{
+				"Threshold": PointTo(Equal(int64(5))),
+				"Confirmed": BeFalse(),
+				"Value":     PointTo(Equal(int64(0))),
+				"Reorged":   BeFalse(),
+				"Pending":   BeTrue(),
+			}))
+		})
+	})
 })
@@ -23,14 +23,23 @@ type WavesGetBalanceResponse struct {
 	Effective  int64  `json:"effective"`
 }
 
+// WavesGetConfirmedBalanceResponse represents the json returned from a balance at confirmations call.
+type WavesGetConfirmedBalanceResponse struct {
+	Address       string `json:"address"`
+	Confirmations int    `json:"confirmations"`
+	Balance       int64  `json:"balance"`
+}
+
 // WavesGetBlockResponse represents the json returned from a blocks latest call.
 type WavesGetBlockResponse struct {
-	Blocksize    int           `json:"blocksize"`
-	Reward       int           `json:"reward"`
-	Signature    string        `json:"signature"`
-	Fee          int           `json:"fee"`
-	Generator    string        `json:"generator"`
-	Transactions []interface{} `json:"transactions"`
+	Blocksize    int    `json:"blocksize"`
+	Reward       int    `json:"reward"`
+	Signature    string `json:"signature"`
+	Fee          int    `json:"fee"`
+	Generator    string `json:"generator"`
+	Transactions []struct {
+		ID string `json:"id"`
+	} `json:"transactions"`
 	Version      int           `json:"version"`
 	Reference    string        `json:"reference"`
 	Features     []interface{} `json:"features"`
@@ -106,6 +115,53 @@ func (w WavesClient) GetInfo() (*transport.CoinState, error) {
 	}, nil
 }
 
+// GetBlockByHeight returns the block at the given height.
+func (w WavesClient) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	var res WavesGetBlockResponse
+
+	err := w.GET(fmt.Sprintf("/blocks/at/%d", height), nil, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting waves block at height")
+	}
+
+	return w.block(res)
+}
+
+// GetBlockByHash returns the block with the given signature, which waves uses as the block id.
+func (w WavesClient) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	var res WavesGetBlockResponse
+
+	err := w.GET("/blocks/signature/"+hash, nil, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting waves block by signature")
+	}
+
+	return w.block(res)
+}
+
+func (w WavesClient) block(res WavesGetBlockResponse) (*transport.BlockResp, error) {
+	if res.Signature == "" {
+		return nil, errors.New("waves block not found")
+	}
+
+	ids := make([]string, len(res.Transactions))
+	for key, value := range res.Transactions {
+		ids[key] = value.ID
+	}
+
+	block := &transport.BlockResp{}
+	block.Data.Block = transport.Block{
+		Height:    int64(res.Height),
+		Hash:      res.Signature,
+		Parent:    res.Reference,
+		Timestamp: res.Timestamp / 1000,
+		TxCount:   len(ids),
+		TxIDs:     ids,
+	}
+
+	return block, nil
+}
+
 // GetBalance returns the balance of the address.
 func (w WavesClient) GetBalance(addr string) (*transport.Balance, error) {
 	var res WavesGetBalanceResponse
@@ -121,6 +177,40 @@ func (w WavesClient) GetBalance(addr string) (*transport.Balance, error) {
 				{
 					Asset:   WavesAssetID,
 					Balance: fmt.Sprintf("%d", res.Regular),
+					// the regular balance which isn't available has been leased out.
+					Locked: fmt.Sprintf("%d", res.Regular-res.Available),
+				},
+			},
+		},
+	}, nil
+}
+
+// GetConfirmedBalance returns the balance of the address as of minConf blocks ago, the change since
+// is returned as unconfirmed.
+func (w WavesClient) GetConfirmedBalance(addr string, minConf int) (*transport.Balance, error) {
+	var details WavesGetBalanceResponse
+
+	err := w.GET("/addresses/balance/details/"+addr, nil, &details)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting waves balance for address")
+	}
+
+	var res WavesGetConfirmedBalanceResponse
+
+	err = w.GET(fmt.Sprintf("/addresses/balance/%s/%d", addr, minConf), nil, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting waves confirmed balance for address")
+	}
+
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{
+				{
+					Asset:       WavesAssetID,
+					Balance:     fmt.Sprintf("%d", res.Balance),
+					Confirmed:   fmt.Sprintf("%d", res.Balance),
+					Unconfirmed: fmt.Sprintf("%d", details.Regular-res.Balance),
+					Locked:      fmt.Sprintf("%d", details.Regular-details.Available),
 				},
 			},
 		},
@@ -136,19 +226,34 @@ func (w WavesClient) GetTransactionByHash(hash string) (*transport.TransactionRe
 		return nil, errors.Wrap(err, "error getting waves transaction from hash")
 	}
 
+	confirmations := transport.Confirmations{
+		// if the transaction is returned from this endpoint then it is confirmed
+		Confirmed: true,
+	}
+
+	// transactions waiting in the utx pool are only returned by the unconfirmed endpoint.
+	if res.ID == "" {
+		if err := w.GET("/transactions/unconfirmed/info/"+hash, nil, &res); err != nil {
+			return nil, errors.Wrap(err, "error getting unconfirmed waves transaction from hash")
+		}
+
+		if res.ID == "" {
+			return nil, errors.Errorf("waves transaction not found: %s", hash)
+		}
+
+		confirmations = transport.PendingConfirmations()
+	}
+
 	return &transport.TransactionResp{
 		Data: struct {
 			Transaction transport.Transaction `json:"transaction"`
 		}{
 			Transaction: transport.Transaction{
-				ID:    hash,
-				From:  res.Sender,
-				To:    res.Recipient,
-				Value: fmt.Sprintf("%d", res.Amount),
-				Confirmations: transport.Confirmations{
-					// if the transaction is returned from this endpoint then it is confirmed
-					Confirmed: true,
-				},
+				ID:            hash,
+				From:          res.Sender,
+				To:            res.Recipient,
+				Value:         fmt.Sprintf("%d", res.Amount),
+				Confirmations: confirmations,
 			},
 		},
 	}, nil
@@ -75,7 +75,7 @@ var _ = Describe("WavesClient", func() {
 	Describe("#GetBalance", func() {
 		It("Should return the Waves balance transformed to the common output", func() {
 			addr := "3PQxNpso2uNbiPM7PQWJMNeYkVsUv4P5mLm"
-			balRes := 262056011619
+			balRes := 300000000000
 
 			mockServer.Expect(test.ExpectedCall{
 				Path:         "/addresses/balance/details/3PQxNpso2uNbiPM7PQWJMNeYkVsUv4P5mLm",
@@ -91,8 +91,12 @@ var _ = Describe("WavesClient", func() {
 				"Data": MatchAllFields(Fields{
 					"Assets": ConsistOf(
 						MatchAllFields(Fields{
-							"Asset":   Equal("WAVES"),
-							"Balance": Equal(fmt.Sprintf("%d", balRes)),
+							"Asset":       Equal("WAVES"),
+							"Balance":     Equal(fmt.Sprintf("%d", balRes)),
+							"Confirmed":   BeEmpty(),
+							"Unconfirmed": BeEmpty(),
+							"Locked":      Equal("37943988381"),
+							"Issuer":      BeEmpty(),
 						}),
 					),
 				}),
@@ -125,10 +129,140 @@ var _ = Describe("WavesClient", func() {
 							"Threshold": BeNil(),
 							"Confirmed": BeTrue(),
 							"Value":     BeNil(),
+							"Reorged":   BeFalse(),
+							"Pending":   BeFalse(),
 						}),
+						"Transfers":      BeEmpty(),
+						"Reverted":       BeFalse(),
+						"Fees":           BeNil(),
+						"Memo":           BeEmpty(),
+						"MemoType":       BeEmpty(),
+						"Coinbase":       BeFalse(),
+						"Outputs":        BeEmpty(),
+						"DestinationTag": BeNil(),
 					}),
 				}),
 			})))
 		})
 	})
+
+	Describe("#GetBlockByHeight", func() {
+		It("Should return the Waves block transformed to the common output", func() {
+			signature := "2mnBXHijboV55MCUYNqNZemgps5ZR64Md1NpcX4r5GC4dCEXwjGGSKHXTWoFTzhxrkHmbUNHPWFdbtHHz1tbBL8t"
+			txID := "Fvvbm6s9q3Rn5YjQbpQ6h7NZS1gYDFzMxhPcc2KGovT8"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/blocks/at/1744832",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/getblock.json", signature, txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHeight(1744832)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block).To(MatchAllFields(Fields{
+				"Height":       Equal(int64(1744832)),
+				"Hash":         Equal(signature),
+				"Parent":       Equal("4zWzyaPboLbAwTZWJW95AKHt6nPRHztPVbgz8CzbnwanWNhNefS2kU2egJMPRcstG8V9R5YxguNHcRrSimu9GFWZ"),
+				"Timestamp":    Equal(int64(1570702899)),
+				"TxCount":      Equal(1),
+				"TxIDs":        Equal([]string{txID}),
+				"Transactions": BeEmpty(),
+			}))
+		})
+
+		It("Should return an error if the block does not exist", func() {
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/blocks/at/99999999",
+				Method:       http.MethodGet,
+				Response:     `{"status": "error", "details": "No block for this height"}`,
+				ResponseCode: http.StatusOK,
+			})
+
+			_, err := client.(transport.BlockFetcher).GetBlockByHeight(99999999)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#GetBlockByHash", func() {
+		It("Should return the Waves block with the signature", func() {
+			signature := "2mnBXHijboV55MCUYNqNZemgps5ZR64Md1NpcX4r5GC4dCEXwjGGSKHXTWoFTzhxrkHmbUNHPWFdbtHHz1tbBL8t"
+			txID := "Fvvbm6s9q3Rn5YjQbpQ6h7NZS1gYDFzMxhPcc2KGovT8"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/blocks/signature/" + signature,
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/getblock.json", signature, txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			block, err := client.(transport.BlockFetcher).GetBlockByHash(signature)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(block.Data.Block.Hash).To(Equal(signature))
+			Expect(block.Data.Block.TxIDs).To(Equal([]string{txID}))
+		})
+	})
+
+	Describe("#GetTransactionByHash with an unconfirmed transaction", func() {
+		It("Should look the transaction up in the utx pool and report it as pending", func() {
+			txID := "9JnjjmKV5e9h24hKDaGu1tZnFcKLFgQWUzXP9E98UtKc"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/transactions/info/9JnjjmKV5e9h24hKDaGu1tZnFcKLFgQWUzXP9E98UtKc",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/gettransaction_notfound.json")),
+				ResponseCode: http.StatusNotFound,
+			}).Then(test.ExpectedCall{
+				Path:         "/transactions/unconfirmed/info/9JnjjmKV5e9h24hKDaGu1tZnFcKLFgQWUzXP9E98UtKc",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/gettransaction.json", txID)),
+				ResponseCode: http.StatusOK,
+			})
+
+			tx, err := client.GetTransactionByHash(txID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tx.Data.Transaction.Confirmations).To(MatchAllFields(Fields{
+				"Threshold": PointTo(Equal(int64(5))),
+				"Confirmed": BeFalse(),
+				"Value":     PointTo(Equal(int64(0))),
+				"Reorged":   BeFalse(),
+				"Pending":   BeTrue(),
+			}))
+		})
+	})
+
+	Describe("#GetConfirmedBalance", func() {
+		It("Should return the balance at the confirmation depth with the change since as unconfirmed", func() {
+			addr := "3PQxNpso2uNbiPM7PQWJMNeYkVsUv4P5mLm"
+
+			mockServer.Expect(test.ExpectedCall{
+				Path:         "/addresses/balance/details/3PQxNpso2uNbiPM7PQWJMNeYkVsUv4P5mLm",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/getbalance.json", addr, 300000000000)),
+				ResponseCode: http.StatusOK,
+			}).Then(test.ExpectedCall{
+				Path:         "/addresses/balance/3PQxNpso2uNbiPM7PQWJMNeYkVsUv4P5mLm/10",
+				Method:       http.MethodGet,
+				Response:     MustLoad(fb.LoadFixture("waves/res/getconfirmedbalance.json", addr, 10, 299000000000)),
+				ResponseCode: http.StatusOK,
+			})
+
+			balance, err := client.(transport.ConfirmedBalanceGetter).GetConfirmedBalance(addr, 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(balance.Data.Assets).To(ConsistOf(
+				MatchAllFields(Fields{
+					"Asset":       Equal("WAVES"),
+					"Balance":     Equal("299000000000"),
+					"Confirmed":   Equal("299000000000"),
+					"Unconfirmed": Equal("1000000000"),
+					"Locked":      Equal("37943988381"),
+					"Issuer":      BeEmpty(),
+				}),
+			))
+		})
+	})
 })
@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// ImportStore persists import jobs so that any instance of the api can report on them.
+type ImportStore interface {
+	Save(job transport.ImportJob) error
+	// Get returns the job with the given id or transport.ErrImportNotFound.
+	Get(id string) (transport.ImportJob, error)
+}
+
+// FileImportStore is an ImportStore which keeps all jobs in a single json file.
+// The file is re-read on every operation so that instances sharing it see each other's jobs.
+type FileImportStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileImportStore returns a FileImportStore at path, creating the file if it does not exist.
+func NewFileImportStore(path string) (*FileImportStore, error) {
+	s := &FileImportStore{Path: path}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := s.write(map[string]transport.ImportJob{}); err != nil {
+			return nil, errors.Wrapf(err, "error creating import store at: %s", path)
+		}
+	}
+
+	if _, err := s.read(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save adds the job to the store, replacing the job with the same id.
+func (s *FileImportStore) Save(job transport.ImportJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	jobs[job.ID] = job
+
+	return s.write(jobs)
+}
+
+// Get returns the job with the given id or transport.ErrImportNotFound.
+func (s *FileImportStore) Get(id string) (transport.ImportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return transport.ImportJob{}, err
+	}
+
+	job, ok := jobs[id]
+	if !ok {
+		return transport.ImportJob{}, transport.ErrImportNotFound
+	}
+
+	return job, nil
+}
+
+func (s *FileImportStore) read() (map[string]transport.ImportJob, error) {
+	raw, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading import store at: %s", s.Path)
+	}
+
+	jobs := map[string]transport.ImportJob{}
+	if err := json.Unmarshal(raw, &jobs); err != nil {
+		return nil, errors.Wrapf(err, "error decoding import store at: %s", s.Path)
+	}
+
+	return jobs, nil
+}
+
+// write replaces the store file atomically so a concurrent reader never sees a partial file.
+func (s *FileImportStore) write(jobs map[string]transport.ImportJob) error {
+	raw, err := json.Marshal(jobs)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
@@ -0,0 +1,489 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// btcImportRange is the number of receive and change addresses of an xpub which are imported.
+const btcImportRange = 1000
+
+var (
+	// ImportAcceptTimeout is how long an import is given to reach the node's wallet. A job whose addresses the
+	// wallet still doesn't hold after it, while the wallet isn't rescanning, is failed.
+	ImportAcceptTimeout = 2 * time.Second
+
+	// ErrImportStoreNotConfigured is returned when an import job is started by a client without an ImportStore.
+	ErrImportStoreNotConfigured = errors.New("import jobs need a store shared by the api, set IMPORT_STORE_PATH")
+
+	// btcImportScripts maps the script types of an xpub's addresses to the descriptor wrapping its keys.
+	btcImportScripts = map[string]string{
+		"p2pkh":       "pkh(%s)",
+		"p2sh-p2wpkh": "sh(wpkh(%s))",
+		"p2wpkh":      "wpkh(%s)",
+		"p2tr":        "tr(%s)",
+	}
+)
+
+// btcImportRequest is a request of an importdescriptors or importmulti call. Nodes without
+// descriptor wallets are sent the address as a scriptPubKey rather than a descriptor. Timestamp
+// is the unix time the rescan starts from, or "now" for the node not to rescan.
+type btcImportRequest struct {
+	Desc         string           `json:"desc,omitempty"`
+	ScriptPubKey *btcImportScript `json:"scriptPubKey,omitempty"`
+	Timestamp    interface{}      `json:"timestamp"`
+	Range        []int            `json:"range,omitempty"`
+	Internal     bool             `json:"internal,omitempty"`
+	WatchOnly    bool             `json:"watchonly,omitempty"`
+}
+
+type btcImportScript struct {
+	Address string `json:"address"`
+}
+
+// btcImportResult is the result of a request of an importdescriptors or importmulti call.
+type btcImportResult struct {
+	Success bool              `json:"success"`
+	Error   *btcjson.RPCError `json:"error"`
+}
+
+// btcWalletInfo represents the result of a getwalletinfo call. Scanning is false when the wallet isn't rescanning.
+type btcWalletInfo struct {
+	Scanning json.RawMessage `json:"scanning"`
+}
+
+type btcWalletScan struct {
+	Duration int64   `json:"duration"`
+	Progress float64 `json:"progress"`
+}
+
+// btcDescriptors represents the result of a listdescriptors call.
+type btcDescriptors struct {
+	Descriptors []struct {
+		Desc string `json:"desc"`
+	} `json:"descriptors"`
+}
+
+// btcAddressOwnership represents whether the wallet holds the address in the result of a validateaddress call.
+type btcAddressOwnership struct {
+	IsMine      bool `json:"ismine"`
+	IsWatchOnly bool `json:"iswatchonly"`
+}
+
+// StartImport imports the address, or the receive and change addresses of the xpub, into the node's wallet.
+// The node only rescans the blocks mined after the timestamp or birth height, and doesn't respond until its
+// rescan is done, so the job is saved and returned as scanning straight away. Its status is only ever read
+// from the node's wallet by GetImport, which can be asked of any instance sharing the ImportStore.
+func (b BitcoinClient) StartImport(req transport.ImportReq) (*transport.ImportResp, error) {
+	if b.Imports == nil {
+		return nil, ErrImportStoreNotConfigured
+	}
+
+	job, err := b.startImport(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &transport.ImportResp{}
+	res.Data.Import = *job
+
+	return res, nil
+}
+
+// GetImport returns the import job. Until it's complete, the job is complete once the wallet holds its
+// addresses and isn't rescanning, and failed if the wallet never took them.
+func (b BitcoinClient) GetImport(id string) (*transport.ImportResp, error) {
+	if b.Imports == nil {
+		return nil, ErrImportStoreNotConfigured
+	}
+
+	job, err := b.Imports.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.AssetID != b.AssetID {
+		return nil, transport.ErrImportNotFound
+	}
+
+	if job.Status == transport.ImportScanning {
+		if err := b.refreshImport(&job); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &transport.ImportResp{}
+	res.Data.Import = job
+
+	return res, nil
+}
+
+// startImport saves the job if the client has an ImportStore and sends the import to the node, returning the
+// job without waiting for the node to respond.
+func (b BitcoinClient) startImport(req transport.ImportReq) (*transport.ImportJob, error) {
+	if req.Timestamp != 0 && req.BirthHeight != 0 {
+		return nil, errors.New("timestamp and birthHeight can not be used together")
+	}
+
+	descs, err := b.importDescriptors(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// the node rescans from the genesis block for a timestamp of 0, so addresses without a birth
+	// time are only watched from the time they're imported.
+	var rescanFrom interface{} = "now"
+
+	timestamp := req.Timestamp
+	if req.BirthHeight > 0 {
+		block, err := b.GetBlockByHeight(req.BirthHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp = block.Data.Block.Timestamp
+	}
+
+	if timestamp > 0 {
+		rescanFrom = timestamp
+	} else {
+		timestamp = time.Now().Unix()
+	}
+
+	id, err := importJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	method, requests := "importmulti", make([]btcImportRequest, len(descs))
+	for i, desc := range descs {
+		requests[i] = btcImportRequest{Timestamp: rescanFrom}
+
+		if !b.network().Descriptors {
+			requests[i].ScriptPubKey = &btcImportScript{Address: req.Addr}
+			requests[i].WatchOnly = true
+			continue
+		}
+
+		method = "importdescriptors"
+		requests[i].Desc = desc
+
+		if req.XPub != "" {
+			requests[i].Range = []int{0, btcImportRange - 1}
+			requests[i].Internal = i == 1
+		}
+	}
+
+	params, err := json.Marshal(requests)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding import requests")
+	}
+
+	job := transport.ImportJob{
+		ID:          id,
+		AssetID:     b.AssetID,
+		Descriptors: descs,
+		Timestamp:   timestamp,
+		Status:      transport.ImportScanning,
+		StartedAt:   time.Now().UTC(),
+	}
+
+	if err := b.saveImport(job); err != nil {
+		return nil, err
+	}
+
+	// the node doesn't respond until its rescan is done, so its response isn't waited on.
+	go func() {
+		_ = b.runImport(method, []json.RawMessage{params})
+	}()
+
+	return &job, nil
+}
+
+// refreshImport reads the status of the scanning job from the node's wallet, saving the job once it's done.
+// The wallet adds the addresses before rescanning for them and rescans for one import at a time, so the
+// job is complete once the wallet holds its addresses and isn't rescanning.
+func (b BitcoinClient) refreshImport(job *transport.ImportJob) error {
+	scan, err := b.walletScan()
+	if err != nil {
+		return err
+	}
+
+	imported, err := b.imported(job.Descriptors)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case imported && scan == nil:
+		*job = finishImport(*job, nil)
+	case !imported && scan == nil && time.Since(job.StartedAt) > ImportAcceptTimeout:
+		*job = finishImport(*job, errors.New("the node did not import the addresses"))
+	case imported:
+		job.Progress = scan.Progress
+		return nil
+	default:
+		return nil
+	}
+
+	return b.saveImport(*job)
+}
+
+func (b BitcoinClient) saveImport(job transport.ImportJob) error {
+	if b.Imports == nil {
+		return nil
+	}
+
+	return errors.Wrapf(b.Imports.Save(job), "error saving import job: %s", job.ID)
+}
+
+// finishImport marks the job complete, or failed if the import returned an error.
+func finishImport(job transport.ImportJob, err error) transport.ImportJob {
+	if err != nil {
+		job.Status = transport.ImportFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = transport.ImportComplete
+		job.Progress = 1
+	}
+
+	return job
+}
+
+// importDescriptors returns the descriptors of the address, or of the receive and change addresses of the xpub.
+func (b BitcoinClient) importDescriptors(req transport.ImportReq) ([]string, error) {
+	if req.XPub == "" {
+		if req.Addr == "" {
+			return nil, errors.New("an addr or xpub must be given to import")
+		}
+
+		if err := b.ValidateAddress(req.Addr); err != nil {
+			return nil, err
+		}
+
+		return []string{withDescriptorChecksum(fmt.Sprintf("addr(%s)", req.Addr))}, nil
+	}
+
+	if !b.network().Descriptors {
+		return nil, errors.Errorf("%s nodes can't import xpubs", b.AssetID)
+	}
+
+	x, err := parseXPub(b.network(), req.XPub)
+	if err != nil {
+		return nil, err
+	}
+
+	script := req.Script
+	if script == "" {
+		script = x.script
+	}
+
+	format, ok := btcImportScripts[script]
+	if !ok {
+		return nil, errors.Errorf("unknown script: %s", script)
+	}
+
+	key := x.descriptorKey()
+
+	return []string{
+		withDescriptorChecksum(fmt.Sprintf(format, key+"/0/*")),
+		withDescriptorChecksum(fmt.Sprintf(format, key+"/1/*")),
+	}, nil
+}
+
+// runImport calls the import method, returning once the node has rescanned for the imported addresses.
+func (b BitcoinClient) runImport(method string, params []json.RawMessage) error {
+	client := b.ImportClient
+	if client == nil {
+		client = b.Client
+	}
+
+	raw, err := client.RawRequest(method, params)
+	if err != nil {
+		return err
+	}
+
+	var results []btcImportResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return errors.Wrap(err, "error decoding import results")
+	}
+
+	for _, res := range results {
+		if res.Success {
+			continue
+		}
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return errors.New("import was not successful")
+	}
+
+	return nil
+}
+
+// walletScan returns the rescan the node's wallet is running, nil if it isn't rescanning.
+func (b BitcoinClient) walletScan() (*btcWalletScan, error) {
+	raw, err := b.Client.RawRequest("getwalletinfo", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting wallet info")
+	}
+
+	var info btcWalletInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, errors.Wrap(err, "error decoding wallet info")
+	}
+
+	if !strings.HasPrefix(string(info.Scanning), "{") {
+		return nil, nil
+	}
+
+	var scan btcWalletScan
+	if err := json.Unmarshal(info.Scanning, &scan); err != nil {
+		return nil, errors.Wrap(err, "error decoding wallet scan")
+	}
+
+	return &scan, nil
+}
+
+// imported reports whether the node's wallet holds all the descriptors. Nodes without descriptor wallets
+// imported the address of the descriptor, so whether they watch it is asked instead.
+func (b BitcoinClient) imported(descs []string) (bool, error) {
+	if !b.network().Descriptors {
+		for _, desc := range descs {
+			watched, err := b.watchesAddress(descriptorAddress(desc))
+			if err != nil || !watched {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+
+	raw, err := b.Client.RawRequest("listdescriptors", nil)
+	if err != nil {
+		return false, errors.Wrap(err, "error listing wallet descriptors")
+	}
+
+	var res btcDescriptors
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return false, errors.Wrap(err, "error decoding wallet descriptors")
+	}
+
+	held := map[string]bool{}
+	for _, d := range res.Descriptors {
+		held[d.Desc] = true
+	}
+
+	for _, desc := range descs {
+		if !held[desc] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (b BitcoinClient) watchesAddress(addr string) (bool, error) {
+	param, err := json.Marshal(addr)
+	if err != nil {
+		return false, err
+	}
+
+	raw, err := b.Client.RawRequest("validateaddress", []json.RawMessage{param})
+	if err != nil {
+		return false, errors.Wrapf(err, "error validating address: %s", addr)
+	}
+
+	var res btcAddressOwnership
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return false, errors.Wrap(err, "error decoding address validation")
+	}
+
+	return res.IsMine || res.IsWatchOnly, nil
+}
+
+// descriptorAddress returns the address of an addr() descriptor.
+func descriptorAddress(desc string) string {
+	if i := strings.LastIndex(desc, "#"); i >= 0 {
+		desc = desc[:i]
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(desc, "addr("), ")")
+}
+
+func importJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error generating import job id")
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// descriptorInputCharset holds the characters a descriptor can be made of, in the order their checksum is computed with.
+const descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+// descriptorGenerator is the generator of the BCH code output descriptor checksums are computed with.
+var descriptorGenerator = [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+// withDescriptorChecksum appends the checksum importdescriptors requires to the descriptor.
+func withDescriptorChecksum(desc string) string {
+	var (
+		symbols []uint64
+		groups  []uint64
+	)
+
+	for _, c := range desc {
+		v := uint64(strings.IndexRune(descriptorInputCharset, c))
+		symbols = append(symbols, v&31)
+		groups = append(groups, v>>5)
+
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+
+	chk := descriptorPolymod(append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	sum := make([]byte, 8)
+	for i := range sum {
+		sum[i] = bech32Charset[(chk>>(5*(7-uint(i))))&31]
+	}
+
+	return desc + "#" + string(sum)
+}
+
+func descriptorPolymod(symbols []uint64) uint64 {
+	chk := uint64(1)
+	for _, v := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ v
+
+		for i, g := range descriptorGenerator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+
+	return chk
+}
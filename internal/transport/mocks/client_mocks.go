@@ -8,6 +8,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	"github.com/hugorut/coins-oracle/pkg/transport"
 	reflect "reflect"
+	time "time"
 )
 
 // MockCoinClient is a mock of CoinClient interface
@@ -77,3 +78,663 @@ func (mr *MockCoinClientMockRecorder) GetTransactionByHash(hash interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByHash", reflect.TypeOf((*MockCoinClient)(nil).GetTransactionByHash), hash)
 }
+
+// MockAddressImporter is a mock of AddressImporter interface
+type MockAddressImporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressImporterMockRecorder
+}
+
+// MockAddressImporterMockRecorder is the mock recorder for MockAddressImporter
+type MockAddressImporterMockRecorder struct {
+	mock *MockAddressImporter
+}
+
+// NewMockAddressImporter creates a new mock instance
+func NewMockAddressImporter(ctrl *gomock.Controller) *MockAddressImporter {
+	mock := &MockAddressImporter{ctrl: ctrl}
+	mock.recorder = &MockAddressImporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAddressImporter) EXPECT() *MockAddressImporterMockRecorder {
+	return m.recorder
+}
+
+// ImportAddress mocks base method
+func (m *MockAddressImporter) ImportAddress(addr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportAddress", addr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportAddress indicates an expected call of ImportAddress
+func (mr *MockAddressImporterMockRecorder) ImportAddress(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAddress", reflect.TypeOf((*MockAddressImporter)(nil).ImportAddress), addr)
+}
+
+// MockBatchBalanceGetter is a mock of BatchBalanceGetter interface
+type MockBatchBalanceGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchBalanceGetterMockRecorder
+}
+
+// MockBatchBalanceGetterMockRecorder is the mock recorder for MockBatchBalanceGetter
+type MockBatchBalanceGetterMockRecorder struct {
+	mock *MockBatchBalanceGetter
+}
+
+// NewMockBatchBalanceGetter creates a new mock instance
+func NewMockBatchBalanceGetter(ctrl *gomock.Controller) *MockBatchBalanceGetter {
+	mock := &MockBatchBalanceGetter{ctrl: ctrl}
+	mock.recorder = &MockBatchBalanceGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBatchBalanceGetter) EXPECT() *MockBatchBalanceGetterMockRecorder {
+	return m.recorder
+}
+
+// GetBalances mocks base method
+func (m *MockBatchBalanceGetter) GetBalances(addrs []string) (map[string]*transport.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", addrs)
+	ret0, _ := ret[0].(map[string]*transport.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances
+func (mr *MockBatchBalanceGetterMockRecorder) GetBalances(addrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockBatchBalanceGetter)(nil).GetBalances), addrs)
+}
+
+// MockBatchTransactionGetter is a mock of BatchTransactionGetter interface
+type MockBatchTransactionGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchTransactionGetterMockRecorder
+}
+
+// MockBatchTransactionGetterMockRecorder is the mock recorder for MockBatchTransactionGetter
+type MockBatchTransactionGetterMockRecorder struct {
+	mock *MockBatchTransactionGetter
+}
+
+// NewMockBatchTransactionGetter creates a new mock instance
+func NewMockBatchTransactionGetter(ctrl *gomock.Controller) *MockBatchTransactionGetter {
+	mock := &MockBatchTransactionGetter{ctrl: ctrl}
+	mock.recorder = &MockBatchTransactionGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBatchTransactionGetter) EXPECT() *MockBatchTransactionGetterMockRecorder {
+	return m.recorder
+}
+
+// GetTransactionsByHash mocks base method
+func (m *MockBatchTransactionGetter) GetTransactionsByHash(hashes []string) (map[string]*transport.TransactionResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByHash", hashes)
+	ret0, _ := ret[0].(map[string]*transport.TransactionResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByHash indicates an expected call of GetTransactionsByHash
+func (mr *MockBatchTransactionGetterMockRecorder) GetTransactionsByHash(hashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByHash", reflect.TypeOf((*MockBatchTransactionGetter)(nil).GetTransactionsByHash), hashes)
+}
+
+// MockTransactionLister is a mock of TransactionLister interface
+type MockTransactionLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionListerMockRecorder
+}
+
+// MockTransactionListerMockRecorder is the mock recorder for MockTransactionLister
+type MockTransactionListerMockRecorder struct {
+	mock *MockTransactionLister
+}
+
+// NewMockTransactionLister creates a new mock instance
+func NewMockTransactionLister(ctrl *gomock.Controller) *MockTransactionLister {
+	mock := &MockTransactionLister{ctrl: ctrl}
+	mock.recorder = &MockTransactionListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTransactionLister) EXPECT() *MockTransactionListerMockRecorder {
+	return m.recorder
+}
+
+// ListTransactions mocks base method
+func (m *MockTransactionLister) ListTransactions(addr string) (*transport.TransactionsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", addr)
+	ret0, _ := ret[0].(*transport.TransactionsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions
+func (mr *MockTransactionListerMockRecorder) ListTransactions(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionLister)(nil).ListTransactions), addr)
+}
+
+// MockBlockFetcher is a mock of BlockFetcher interface
+type MockBlockFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockFetcherMockRecorder
+}
+
+// MockBlockFetcherMockRecorder is the mock recorder for MockBlockFetcher
+type MockBlockFetcherMockRecorder struct {
+	mock *MockBlockFetcher
+}
+
+// NewMockBlockFetcher creates a new mock instance
+func NewMockBlockFetcher(ctrl *gomock.Controller) *MockBlockFetcher {
+	mock := &MockBlockFetcher{ctrl: ctrl}
+	mock.recorder = &MockBlockFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBlockFetcher) EXPECT() *MockBlockFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetBlockByHeight mocks base method
+func (m *MockBlockFetcher) GetBlockByHeight(height int64) (*transport.BlockResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByHeight", height)
+	ret0, _ := ret[0].(*transport.BlockResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByHeight indicates an expected call of GetBlockByHeight
+func (mr *MockBlockFetcherMockRecorder) GetBlockByHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHeight", reflect.TypeOf((*MockBlockFetcher)(nil).GetBlockByHeight), height)
+}
+
+// GetBlockByHash mocks base method
+func (m *MockBlockFetcher) GetBlockByHash(hash string) (*transport.BlockResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByHash", hash)
+	ret0, _ := ret[0].(*transport.BlockResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByHash indicates an expected call of GetBlockByHash
+func (mr *MockBlockFetcherMockRecorder) GetBlockByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHash", reflect.TypeOf((*MockBlockFetcher)(nil).GetBlockByHash), hash)
+}
+
+// MockMempoolInspector is a mock of MempoolInspector interface
+type MockMempoolInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockMempoolInspectorMockRecorder
+}
+
+// MockMempoolInspectorMockRecorder is the mock recorder for MockMempoolInspector
+type MockMempoolInspectorMockRecorder struct {
+	mock *MockMempoolInspector
+}
+
+// NewMockMempoolInspector creates a new mock instance
+func NewMockMempoolInspector(ctrl *gomock.Controller) *MockMempoolInspector {
+	mock := &MockMempoolInspector{ctrl: ctrl}
+	mock.recorder = &MockMempoolInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMempoolInspector) EXPECT() *MockMempoolInspectorMockRecorder {
+	return m.recorder
+}
+
+// GetMempool mocks base method
+func (m *MockMempoolInspector) GetMempool() (*transport.MempoolResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMempool")
+	ret0, _ := ret[0].(*transport.MempoolResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMempool indicates an expected call of GetMempool
+func (mr *MockMempoolInspectorMockRecorder) GetMempool() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMempool", reflect.TypeOf((*MockMempoolInspector)(nil).GetMempool))
+}
+
+// MockTransactionSinceLister is a mock of TransactionSinceLister interface
+type MockTransactionSinceLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionSinceListerMockRecorder
+}
+
+// MockTransactionSinceListerMockRecorder is the mock recorder for MockTransactionSinceLister
+type MockTransactionSinceListerMockRecorder struct {
+	mock *MockTransactionSinceLister
+}
+
+// NewMockTransactionSinceLister creates a new mock instance
+func NewMockTransactionSinceLister(ctrl *gomock.Controller) *MockTransactionSinceLister {
+	mock := &MockTransactionSinceLister{ctrl: ctrl}
+	mock.recorder = &MockTransactionSinceListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTransactionSinceLister) EXPECT() *MockTransactionSinceListerMockRecorder {
+	return m.recorder
+}
+
+// ListTransactionsSince mocks base method
+func (m *MockTransactionSinceLister) ListTransactionsSince(addr, cursor string, confirmations int64) (*transport.TransactionsResp, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactionsSince", addr, cursor, confirmations)
+	ret0, _ := ret[0].(*transport.TransactionsResp)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTransactionsSince indicates an expected call of ListTransactionsSince
+func (mr *MockTransactionSinceListerMockRecorder) ListTransactionsSince(addr, cursor, confirmations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactionsSince", reflect.TypeOf((*MockTransactionSinceLister)(nil).ListTransactionsSince), addr, cursor, confirmations)
+}
+
+// MockUTXOLister is a mock of UTXOLister interface
+type MockUTXOLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockUTXOListerMockRecorder
+}
+
+// MockUTXOListerMockRecorder is the mock recorder for MockUTXOLister
+type MockUTXOListerMockRecorder struct {
+	mock *MockUTXOLister
+}
+
+// NewMockUTXOLister creates a new mock instance
+func NewMockUTXOLister(ctrl *gomock.Controller) *MockUTXOLister {
+	mock := &MockUTXOLister{ctrl: ctrl}
+	mock.recorder = &MockUTXOListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUTXOLister) EXPECT() *MockUTXOListerMockRecorder {
+	return m.recorder
+}
+
+// ListUTXOs mocks base method
+func (m *MockUTXOLister) ListUTXOs(addr string, minConf int) (*transport.UTXOsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUTXOs", addr, minConf)
+	ret0, _ := ret[0].(*transport.UTXOsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUTXOs indicates an expected call of ListUTXOs
+func (mr *MockUTXOListerMockRecorder) ListUTXOs(addr, minConf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUTXOs", reflect.TypeOf((*MockUTXOLister)(nil).ListUTXOs), addr, minConf)
+}
+
+// MockConfirmedBalanceGetter is a mock of ConfirmedBalanceGetter interface
+type MockConfirmedBalanceGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfirmedBalanceGetterMockRecorder
+}
+
+// MockConfirmedBalanceGetterMockRecorder is the mock recorder for MockConfirmedBalanceGetter
+type MockConfirmedBalanceGetterMockRecorder struct {
+	mock *MockConfirmedBalanceGetter
+}
+
+// NewMockConfirmedBalanceGetter creates a new mock instance
+func NewMockConfirmedBalanceGetter(ctrl *gomock.Controller) *MockConfirmedBalanceGetter {
+	mock := &MockConfirmedBalanceGetter{ctrl: ctrl}
+	mock.recorder = &MockConfirmedBalanceGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfirmedBalanceGetter) EXPECT() *MockConfirmedBalanceGetterMockRecorder {
+	return m.recorder
+}
+
+// GetConfirmedBalance mocks base method
+func (m *MockConfirmedBalanceGetter) GetConfirmedBalance(addr string, minConf int) (*transport.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfirmedBalance", addr, minConf)
+	ret0, _ := ret[0].(*transport.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfirmedBalance indicates an expected call of GetConfirmedBalance
+func (mr *MockConfirmedBalanceGetterMockRecorder) GetConfirmedBalance(addr, minConf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfirmedBalance", reflect.TypeOf((*MockConfirmedBalanceGetter)(nil).GetConfirmedBalance), addr, minConf)
+}
+
+// MockHistoricalBalanceGetter is a mock of HistoricalBalanceGetter interface
+type MockHistoricalBalanceGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoricalBalanceGetterMockRecorder
+}
+
+// MockHistoricalBalanceGetterMockRecorder is the mock recorder for MockHistoricalBalanceGetter
+type MockHistoricalBalanceGetterMockRecorder struct {
+	mock *MockHistoricalBalanceGetter
+}
+
+// NewMockHistoricalBalanceGetter creates a new mock instance
+func NewMockHistoricalBalanceGetter(ctrl *gomock.Controller) *MockHistoricalBalanceGetter {
+	mock := &MockHistoricalBalanceGetter{ctrl: ctrl}
+	mock.recorder = &MockHistoricalBalanceGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHistoricalBalanceGetter) EXPECT() *MockHistoricalBalanceGetterMockRecorder {
+	return m.recorder
+}
+
+// GetBalanceAt mocks base method
+func (m *MockHistoricalBalanceGetter) GetBalanceAt(addr string, height int64) (*transport.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", addr, height)
+	ret0, _ := ret[0].(*transport.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt
+func (mr *MockHistoricalBalanceGetterMockRecorder) GetBalanceAt(addr, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockHistoricalBalanceGetter)(nil).GetBalanceAt), addr, height)
+}
+
+// GetBlockTime mocks base method
+func (m *MockHistoricalBalanceGetter) GetBlockTime(height int64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockTime", height)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockTime indicates an expected call of GetBlockTime
+func (mr *MockHistoricalBalanceGetterMockRecorder) GetBlockTime(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockTime", reflect.TypeOf((*MockHistoricalBalanceGetter)(nil).GetBlockTime), height)
+}
+
+// MockTokenBalanceGetter is a mock of TokenBalanceGetter interface
+type MockTokenBalanceGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenBalanceGetterMockRecorder
+}
+
+// MockTokenBalanceGetterMockRecorder is the mock recorder for MockTokenBalanceGetter
+type MockTokenBalanceGetterMockRecorder struct {
+	mock *MockTokenBalanceGetter
+}
+
+// NewMockTokenBalanceGetter creates a new mock instance
+func NewMockTokenBalanceGetter(ctrl *gomock.Controller) *MockTokenBalanceGetter {
+	mock := &MockTokenBalanceGetter{ctrl: ctrl}
+	mock.recorder = &MockTokenBalanceGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTokenBalanceGetter) EXPECT() *MockTokenBalanceGetterMockRecorder {
+	return m.recorder
+}
+
+// GetTokenBalance mocks base method
+func (m *MockTokenBalanceGetter) GetTokenBalance(contract string, addr string) (*transport.TokenBalanceResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenBalance", contract, addr)
+	ret0, _ := ret[0].(*transport.TokenBalanceResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenBalance indicates an expected call of GetTokenBalance
+func (mr *MockTokenBalanceGetterMockRecorder) GetTokenBalance(contract, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenBalance", reflect.TypeOf((*MockTokenBalanceGetter)(nil).GetTokenBalance), contract, addr)
+}
+
+// MockNFTInspector is a mock of NFTInspector interface
+type MockNFTInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockNFTInspectorMockRecorder
+}
+
+// MockNFTInspectorMockRecorder is the mock recorder for MockNFTInspector
+type MockNFTInspectorMockRecorder struct {
+	mock *MockNFTInspector
+}
+
+// NewMockNFTInspector creates a new mock instance
+func NewMockNFTInspector(ctrl *gomock.Controller) *MockNFTInspector {
+	mock := &MockNFTInspector{ctrl: ctrl}
+	mock.recorder = &MockNFTInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNFTInspector) EXPECT() *MockNFTInspectorMockRecorder {
+	return m.recorder
+}
+
+// GetNFTOwner mocks base method
+func (m *MockNFTInspector) GetNFTOwner(contract string, tokenID string) (*transport.NFTOwnerResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNFTOwner", contract, tokenID)
+	ret0, _ := ret[0].(*transport.NFTOwnerResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNFTOwner indicates an expected call of GetNFTOwner
+func (mr *MockNFTInspectorMockRecorder) GetNFTOwner(contract, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNFTOwner", reflect.TypeOf((*MockNFTInspector)(nil).GetNFTOwner), contract, tokenID)
+}
+
+// ListNFTs mocks base method
+func (m *MockNFTInspector) ListNFTs(contract string, addr string, offset int64) (*transport.NFTHoldingsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNFTs", contract, addr, offset)
+	ret0, _ := ret[0].(*transport.NFTHoldingsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNFTs indicates an expected call of ListNFTs
+func (mr *MockNFTInspectorMockRecorder) ListNFTs(contract, addr, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNFTs", reflect.TypeOf((*MockNFTInspector)(nil).ListNFTs), contract, addr, offset)
+}
+
+// MockImportScheduler is a mock of ImportScheduler interface
+type MockImportScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportSchedulerMockRecorder
+}
+
+// MockImportSchedulerMockRecorder is the mock recorder for MockImportScheduler
+type MockImportSchedulerMockRecorder struct {
+	mock *MockImportScheduler
+}
+
+// NewMockImportScheduler creates a new mock instance
+func NewMockImportScheduler(ctrl *gomock.Controller) *MockImportScheduler {
+	mock := &MockImportScheduler{ctrl: ctrl}
+	mock.recorder = &MockImportSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockImportScheduler) EXPECT() *MockImportSchedulerMockRecorder {
+	return m.recorder
+}
+
+// StartImport mocks base method
+func (m *MockImportScheduler) StartImport(req transport.ImportReq) (*transport.ImportResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImport", req)
+	ret0, _ := ret[0].(*transport.ImportResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartImport indicates an expected call of StartImport
+func (mr *MockImportSchedulerMockRecorder) StartImport(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImport", reflect.TypeOf((*MockImportScheduler)(nil).StartImport), req)
+}
+
+// GetImport mocks base method
+func (m *MockImportScheduler) GetImport(id string) (*transport.ImportResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", id)
+	ret0, _ := ret[0].(*transport.ImportResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport
+func (mr *MockImportSchedulerMockRecorder) GetImport(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockImportScheduler)(nil).GetImport), id)
+}
+
+// MockXPubInspector is a mock of XPubInspector interface
+type MockXPubInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockXPubInspectorMockRecorder
+}
+
+// MockXPubInspectorMockRecorder is the mock recorder for MockXPubInspector
+type MockXPubInspectorMockRecorder struct {
+	mock *MockXPubInspector
+}
+
+// NewMockXPubInspector creates a new mock instance
+func NewMockXPubInspector(ctrl *gomock.Controller) *MockXPubInspector {
+	mock := &MockXPubInspector{ctrl: ctrl}
+	mock.recorder = &MockXPubInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockXPubInspector) EXPECT() *MockXPubInspectorMockRecorder {
+	return m.recorder
+}
+
+// ListXPubAddresses mocks base method
+func (m *MockXPubInspector) ListXPubAddresses(xpub string, gap int) (*transport.XPubAddressesResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListXPubAddresses", xpub, gap)
+	ret0, _ := ret[0].(*transport.XPubAddressesResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListXPubAddresses indicates an expected call of ListXPubAddresses
+func (mr *MockXPubInspectorMockRecorder) ListXPubAddresses(xpub, gap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListXPubAddresses", reflect.TypeOf((*MockXPubInspector)(nil).ListXPubAddresses), xpub, gap)
+}
+
+// GetXPubBalance mocks base method
+func (m *MockXPubInspector) GetXPubBalance(xpub string, gap int) (*transport.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetXPubBalance", xpub, gap)
+	ret0, _ := ret[0].(*transport.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetXPubBalance indicates an expected call of GetXPubBalance
+func (mr *MockXPubInspectorMockRecorder) GetXPubBalance(xpub, gap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXPubBalance", reflect.TypeOf((*MockXPubInspector)(nil).GetXPubBalance), xpub, gap)
+}
+
+// ListXPubTransactions mocks base method
+func (m *MockXPubInspector) ListXPubTransactions(xpub string, gap int) (*transport.TransactionsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListXPubTransactions", xpub, gap)
+	ret0, _ := ret[0].(*transport.TransactionsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListXPubTransactions indicates an expected call of ListXPubTransactions
+func (mr *MockXPubInspectorMockRecorder) ListXPubTransactions(xpub, gap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListXPubTransactions", reflect.TypeOf((*MockXPubInspector)(nil).ListXPubTransactions), xpub, gap)
+}
+
+// MockResourceInspector is a mock of ResourceInspector interface
+type MockResourceInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceInspectorMockRecorder
+}
+
+// MockResourceInspectorMockRecorder is the mock recorder for MockResourceInspector
+type MockResourceInspectorMockRecorder struct {
+	mock *MockResourceInspector
+}
+
+// NewMockResourceInspector creates a new mock instance
+func NewMockResourceInspector(ctrl *gomock.Controller) *MockResourceInspector {
+	mock := &MockResourceInspector{ctrl: ctrl}
+	mock.recorder = &MockResourceInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResourceInspector) EXPECT() *MockResourceInspectorMockRecorder {
+	return m.recorder
+}
+
+// GetResources mocks base method
+func (m *MockResourceInspector) GetResources(addr string) (*transport.ResourcesResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResources", addr)
+	ret0, _ := ret[0].(*transport.ResourcesResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResources indicates an expected call of GetResources
+func (mr *MockResourceInspectorMockRecorder) GetResources(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResources", reflect.TypeOf((*MockResourceInspector)(nil).GetResources), addr)
+}
@@ -76,3 +76,46 @@ func (mr *MockRouterMockRecorder) GetNodes(info interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodes", reflect.TypeOf((*MockRouter)(nil).GetNodes), info)
 }
+
+// GetBalances mocks base method
+func (m *MockRouter) GetBalances(lookups []transport.BalanceLookup) []transport.BalanceResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", lookups)
+	ret0, _ := ret[0].([]transport.BalanceResult)
+	return ret0
+}
+
+// GetBalances indicates an expected call of GetBalances
+func (mr *MockRouterMockRecorder) GetBalances(lookups interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockRouter)(nil).GetBalances), lookups)
+}
+
+// GetTransactions mocks base method
+func (m *MockRouter) GetTransactions(lookups []transport.TransactionLookup) []transport.TransactionResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", lookups)
+	ret0, _ := ret[0].([]transport.TransactionResult)
+	return ret0
+}
+
+// GetTransactions indicates an expected call of GetTransactions
+func (mr *MockRouterMockRecorder) GetTransactions(lookups interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockRouter)(nil).GetTransactions), lookups)
+}
+
+// GetPortfolio mocks base method
+func (m *MockRouter) GetPortfolio(chain, addr string) (*transport.PortfolioData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortfolio", chain, addr)
+	ret0, _ := ret[0].(*transport.PortfolioData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPortfolio indicates an expected call of GetPortfolio
+func (mr *MockRouterMockRecorder) GetPortfolio(chain, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortfolio", reflect.TypeOf((*MockRouter)(nil).GetPortfolio), chain, addr)
+}
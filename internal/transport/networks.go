@@ -0,0 +1,419 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	// MainNet is the network clients are pointed at unless another is asked for.
+	MainNet = "main"
+	TestNet = "testnet"
+	RegTest = "regtest"
+)
+
+// TestNetworks are the networks other than mainnet which an asset can be registered for.
+var TestNetworks = []string{TestNet, RegTest}
+
+// NetworkAssetID returns the id the client of the asset on the network is registered under, e.g. BTC-TESTNET.
+func NetworkAssetID(assetID, network string) string {
+	network = strings.ToLower(network)
+	if network == "" || network == MainNet || network == "mainnet" {
+		return assetID
+	}
+
+	return assetID + "-" + strings.ToUpper(network)
+}
+
+// validNetwork returns whether assets can be registered for the network.
+func validNetwork(network string) bool {
+	if network == MainNet {
+		return true
+	}
+
+	for _, n := range TestNetworks {
+		if n == network {
+			return true
+		}
+	}
+
+	return false
+}
+
+// baseAssetID strips the network from the id of an asset registered for a test network.
+func baseAssetID(assetID string) string {
+	for _, network := range TestNetworks {
+		if suffix := "-" + strings.ToUpper(network); strings.HasSuffix(strings.ToUpper(assetID), suffix) {
+			return assetID[:len(assetID)-len(suffix)]
+		}
+	}
+
+	return assetID
+}
+
+// networkEnv returns the prefix of the os variables configuring the coin's node on the network,
+// e.g. BITCOIN_TESTNET for the BITCOIN_TESTNET_URL and BITCOIN_TESTNET_RPC_USER variables.
+func networkEnv(coin, network string) string {
+	if network == MainNet {
+		return coin
+	}
+
+	return coin + "_" + strings.ToUpper(network)
+}
+
+// BTCNetwork holds what differs between bitcoin and the chains forked from it.
+type BTCNetwork struct {
+	// Params holds the address prefixes of the chain.
+	Params *chaincfg.Params
+	// CashAddrPrefix is the prefix of CashAddr encoded addresses, accepted alongside legacy addresses if set.
+	CashAddrPrefix string
+	// LegacyInfo is set for nodes which report the chain tip with getinfo rather than getblockchaininfo.
+	LegacyInfo bool
+	// Descriptors is set for nodes with descriptor wallets, which import with importdescriptors rather than importmulti.
+	Descriptors bool
+	// LegacyScriptHashAddrIDs are script hash prefixes the chain still accepts alongside the one in Params,
+	// e.g. litecoin's 3 addresses from before it moved its P2SH addresses to M.
+	LegacyScriptHashAddrIDs []byte
+}
+
+var (
+	BitcoinNetwork = &BTCNetwork{Params: &chaincfg.MainNetParams, Descriptors: true}
+
+	LitecoinNetwork = &BTCNetwork{
+		Params: &chaincfg.Params{
+			Name:             "litecoin",
+			Net:              wire.BitcoinNet(0xdbb6c0fb),
+			Bech32HRPSegwit:  "ltc",
+			PubKeyHashAddrID: 0x30,
+			ScriptHashAddrID: 0x32,
+			PrivateKeyID:     0xb0,
+			HDPrivateKeyID:   [4]byte{0x01, 0x9d, 0x9c, 0xfe},
+			HDPublicKeyID:    [4]byte{0x01, 0x9d, 0xa4, 0x62},
+			HDCoinType:       2,
+		},
+		Descriptors:             true,
+		LegacyScriptHashAddrIDs: []byte{chaincfg.MainNetParams.ScriptHashAddrID},
+	}
+
+	DogecoinNetwork = &BTCNetwork{
+		Params: &chaincfg.Params{
+			Name:             "dogecoin",
+			Net:              wire.BitcoinNet(0xc0c0c0c0),
+			PubKeyHashAddrID: 0x1e,
+			ScriptHashAddrID: 0x16,
+			PrivateKeyID:     0x9e,
+			HDPrivateKeyID:   [4]byte{0x02, 0xfa, 0xc3, 0x98},
+			HDPublicKeyID:    [4]byte{0x02, 0xfa, 0xca, 0xfd},
+			HDCoinType:       3,
+		},
+		LegacyInfo: true,
+	}
+
+	BitcoinCashNetwork = &BTCNetwork{
+		Params:         btcForkParams("bitcoincash", 0xe8f3e1e3, 145),
+		CashAddrPrefix: "bitcoincash",
+	}
+
+	BitcoinSVNetwork = &BTCNetwork{
+		Params: btcForkParams("bitcoinsv", 0xe8f3e1e3, 236),
+	}
+
+	BitcoinGoldNetwork = &BTCNetwork{
+		Params: &chaincfg.Params{
+			Name:             "bitcoingold",
+			Net:              wire.BitcoinNet(0x446d47e1),
+			Bech32HRPSegwit:  "btg",
+			PubKeyHashAddrID: 0x26,
+			ScriptHashAddrID: 0x17,
+			PrivateKeyID:     0x80,
+			HDPrivateKeyID:   chaincfg.MainNetParams.HDPrivateKeyID,
+			HDPublicKeyID:    chaincfg.MainNetParams.HDPublicKeyID,
+			HDCoinType:       156,
+		},
+	}
+)
+
+// btcTestNetworks holds the params of the test networks of the bitcoin family, keyed by asset id then network.
+var btcTestNetworks = map[string]map[string]*BTCNetwork{
+	BitcoinAssetID: {
+		TestNet: {Params: &chaincfg.TestNet3Params, Descriptors: true},
+		RegTest: {Params: &chaincfg.RegressionNetParams, Descriptors: true},
+	},
+	LitecoinAssetID: {
+		TestNet: {Params: btcTestParams("litecoin-testnet", "tltc", 0x6f, 0x3a), Descriptors: true, LegacyScriptHashAddrIDs: []byte{0xc4}},
+		RegTest: {Params: btcTestParams("litecoin-regtest", "rltc", 0x6f, 0x3a), Descriptors: true, LegacyScriptHashAddrIDs: []byte{0xc4}},
+	},
+	DogecoinAssetID: {
+		TestNet: {Params: btcTestParams("dogecoin-testnet", "", 0x71, 0xc4), LegacyInfo: true},
+		RegTest: {Params: btcTestParams("dogecoin-regtest", "", 0x6f, 0xc4), LegacyInfo: true},
+	},
+	BitcoinCashAssetID: {
+		TestNet: {Params: btcTestParams("bitcoincash-testnet", "", 0x6f, 0xc4), CashAddrPrefix: "bchtest"},
+		RegTest: {Params: btcTestParams("bitcoincash-regtest", "", 0x6f, 0xc4), CashAddrPrefix: "bchreg"},
+	},
+	BitcoinsvAssetID: {
+		TestNet: {Params: btcTestParams("bitcoinsv-testnet", "", 0x6f, 0xc4)},
+		RegTest: {Params: btcTestParams("bitcoinsv-regtest", "", 0x6f, 0xc4)},
+	},
+	BitcoinGoldAssetID: {
+		TestNet: {Params: btcTestParams("bitcoingold-testnet", "tbtg", 0x6f, 0xc4)},
+	},
+}
+
+// btcNetwork returns the params of the asset on the network, main being those of the asset on mainnet.
+func btcNetwork(assetID, network string, main *BTCNetwork) (*BTCNetwork, error) {
+	if network == MainNet {
+		return main, nil
+	}
+
+	if net, ok := BTCTestNetwork(assetID, network); ok {
+		return net, nil
+	}
+
+	return nil, fmt.Errorf("asset: %s has no %s network", assetID, network)
+}
+
+// BTCTestNetwork returns the params of the bitcoin family asset on a test network, if it has one.
+func BTCTestNetwork(assetID, network string) (*BTCNetwork, bool) {
+	net, ok := btcTestNetworks[assetID][network]
+	return net, ok
+}
+
+// btcForkParams returns the params of a chain which kept bitcoin's address prefixes.
+func btcForkParams(name string, net uint32, coinType uint32) *chaincfg.Params {
+	return &chaincfg.Params{
+		Name:             name,
+		Net:              wire.BitcoinNet(net),
+		PubKeyHashAddrID: chaincfg.MainNetParams.PubKeyHashAddrID,
+		ScriptHashAddrID: chaincfg.MainNetParams.ScriptHashAddrID,
+		PrivateKeyID:     chaincfg.MainNetParams.PrivateKeyID,
+		HDPrivateKeyID:   chaincfg.MainNetParams.HDPrivateKeyID,
+		HDPublicKeyID:    chaincfg.MainNetParams.HDPublicKeyID,
+		HDCoinType:       coinType,
+	}
+}
+
+// btcTestParams returns the params of a test network, which all share bitcoin's testnet key prefixes.
+func btcTestParams(name, hrp string, pubKeyHashAddrID, scriptHashAddrID byte) *chaincfg.Params {
+	return &chaincfg.Params{
+		Name:             name,
+		Bech32HRPSegwit:  hrp,
+		PubKeyHashAddrID: pubKeyHashAddrID,
+		ScriptHashAddrID: scriptHashAddrID,
+		PrivateKeyID:     chaincfg.TestNet3Params.PrivateKeyID,
+		HDPrivateKeyID:   chaincfg.TestNet3Params.HDPrivateKeyID,
+		HDPublicKeyID:    chaincfg.TestNet3Params.HDPublicKeyID,
+		HDCoinType:       chaincfg.TestNet3Params.HDCoinType,
+	}
+}
+
+// IsForNet returns whether the address is a legacy, segwit or CashAddr address of the network.
+func (n BTCNetwork) IsForNet(addr string) bool {
+	if n.CashAddrPrefix != "" && validCashAddr(n.CashAddrPrefix, addr) {
+		return true
+	}
+
+	if (btcStrAddr{addr: addr}).IsForNet(n.Params) {
+		return true
+	}
+
+	return n.legacyScriptHash(addr)
+}
+
+// legacyScriptHash returns whether the address is a P2SH address with one of the network's legacy prefixes.
+func (n BTCNetwork) legacyScriptHash(addr string) bool {
+	if len(n.LegacyScriptHashAddrIDs) == 0 {
+		return false
+	}
+
+	// the payload of a P2SH address is the 160 bit hash of the script.
+	hash, version, err := base58.CheckDecode(addr)
+	if err != nil || len(hash) != 20 {
+		return false
+	}
+
+	for _, id := range n.LegacyScriptHashAddrIDs {
+		if version == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bech32Charset is the alphabet of both segwit and CashAddr addresses.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32m replaced bech32's checksum constant for segwit versions 1 onwards.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// validSegwitAddr checks the address is a segwit address with the human readable part, version 0 programs
+// being bech32 encoded and later versions, e.g. taproot, bech32m encoded.
+// See https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+func validSegwitAddr(hrp, addr string) bool {
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return false
+	}
+
+	addr = strings.ToLower(addr)
+	if !strings.HasPrefix(addr, hrp+"1") || len(addr) > 90 {
+		return false
+	}
+
+	data, ok := bech32Values(addr[len(hrp)+1:])
+	if !ok || len(data) < 7 {
+		return false
+	}
+
+	values := make([]byte, 0, len(hrp)*2+1+len(data))
+	for _, c := range hrp {
+		values = append(values, byte(c)>>5)
+	}
+
+	values = append(values, 0)
+	for _, c := range hrp {
+		values = append(values, byte(c)&0x1f)
+	}
+
+	checksum := bech32Polymod(append(values, data...))
+
+	version := data[0]
+	program, ok := convertBits(data[1 : len(data)-6])
+	if !ok || version > 16 || len(program) < 2 || len(program) > 40 {
+		return false
+	}
+
+	if version == 0 {
+		return checksum == bech32Const && (len(program) == 20 || len(program) == 32)
+	}
+
+	return checksum == bech32mConst
+}
+
+// bech32Values maps the characters of the data part to their 5 bit values.
+func bech32Values(data string) ([]byte, bool) {
+	values := make([]byte, len(data))
+	for i, c := range data {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return nil, false
+		}
+
+		values[i] = byte(v)
+	}
+
+	return values, true
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generators := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	c := uint32(1)
+	for _, v := range values {
+		top := c >> 25
+		c = (c&0x1ffffff)<<5 ^ uint32(v)
+
+		for i, g := range generators {
+			if top>>uint(i)&1 == 1 {
+				c ^= g
+			}
+		}
+	}
+
+	return c
+}
+
+// convertBits regroups 5 bit values into bytes, the padding having to be less than 5 zero bits.
+func convertBits(data []byte) ([]byte, bool) {
+	var (
+		out  []byte
+		acc  uint32
+		bits uint
+	)
+
+	for _, v := range data {
+		acc = acc<<5 | uint32(v)
+		bits += 5
+
+		for bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return nil, false
+	}
+
+	return out, true
+}
+
+// validCashAddr checks the address is a CashAddr encoded P2PKH or P2SH address, the prefix being optional.
+// See https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+func validCashAddr(prefix, addr string) bool {
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return false
+	}
+
+	addr = strings.ToLower(addr)
+	if i := strings.IndexByte(addr, ':'); i >= 0 {
+		if addr[:i] != prefix {
+			return false
+		}
+
+		addr = addr[i+1:]
+	}
+
+	// a 160 bit hash with its version byte is 34 characters followed by the 8 character checksum.
+	if len(addr) != 42 {
+		return false
+	}
+
+	values := make([]byte, 0, len(prefix)+1+len(addr))
+	for _, c := range prefix {
+		values = append(values, byte(c)&0x1f)
+	}
+
+	data, ok := bech32Values(addr)
+	if !ok {
+		return false
+	}
+
+	values = append(append(values, 0), data...)
+	if cashAddrPolymod(values) != 0 {
+		return false
+	}
+
+	// the version byte is the first 8 bits of the payload, its type bits 0 for P2PKH and 1 for P2SH
+	// and its size bits 0 for a 160 bit hash.
+	payload := values[len(prefix)+1:]
+	version := payload[0]<<3 | payload[1]>>2
+
+	// the 21 bytes are padded to 34 characters with 2 zero bits.
+	return (version == 0 || version == 8) && payload[33]&0x3 == 0
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	generators := []uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+
+	c := uint64(1)
+	for _, v := range values {
+		top := c >> 35
+		c = (c&0x07ffffffff)<<5 ^ uint64(v)
+
+		for i, g := range generators {
+			if top>>uint(i)&1 == 1 {
+				c ^= g
+			}
+		}
+	}
+
+	return c ^ 1
+}
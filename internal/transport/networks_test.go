@@ -0,0 +1,92 @@
+package transport_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+)
+
+var _ = Describe("BTCNetwork", func() {
+	Describe("#IsForNet", func() {
+		DescribeTable("Should only accept addresses of the network",
+			func(net *BTCNetwork, addr string, valid bool) {
+				Expect(net.IsForNet(addr)).To(Equal(valid))
+			},
+			Entry("bitcoin p2pkh", BitcoinNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", true),
+			Entry("bitcoin p2sh", BitcoinNetwork, "3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC", true),
+			Entry("bitcoin p2wpkh", BitcoinNetwork, "bc1qw6syq5aa5z5ghkj3w7ux59wrk204txrn9k4rmm", true),
+			Entry("bitcoin p2tr", BitcoinNetwork, "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", true),
+			Entry("litecoin address on bitcoin", BitcoinNetwork, "LW3ByJXVHpiJsuy3u2sdieFQkXHtuk93Yi", false),
+			Entry("bad checksum", BitcoinNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggv", false),
+			Entry("litecoin p2pkh", LitecoinNetwork, "LW3ByJXVHpiJsuy3u2sdieFQkXHtuk93Yi", true),
+			Entry("litecoin p2sh", LitecoinNetwork, "MJiPwX84iBe4WnFDwsYGgtnz1XonPhUqhf", true),
+			Entry("litecoin legacy p2sh", LitecoinNetwork, "3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC", true),
+			Entry("litecoin legacy p2sh bad checksum", LitecoinNetwork, "3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzD", false),
+			Entry("litecoin p2wpkh", LitecoinNetwork, "ltc1qw6syq5aa5z5ghkj3w7ux59wrk204txrnp208rt", true),
+			Entry("bitcoin address on litecoin", LitecoinNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", false),
+			Entry("bitcoin segwit address on litecoin", LitecoinNetwork, "bc1qw6syq5aa5z5ghkj3w7ux59wrk204txrn9k4rmm", false),
+			Entry("dogecoin p2pkh", DogecoinNetwork, "DFxLFMAJWaNYA7TVTUstzPMFRSevAwTSLq", true),
+			Entry("dogecoin p2sh", DogecoinNetwork, "A3FWNUmzq8fXceLoG8DM7PAxPQbNUjae2B", true),
+			Entry("bitcoin address on dogecoin", DogecoinNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", false),
+			Entry("bitcoin cash legacy", BitcoinCashNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", true),
+			Entry("bitcoin cash cashaddr", BitcoinCashNetwork, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", true),
+			Entry("bitcoin cash cashaddr without prefix", BitcoinCashNetwork, "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", true),
+			Entry("bitcoin cash upper case cashaddr", BitcoinCashNetwork, "BITCOINCASH:QPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVY22GDX6A", true),
+			Entry("bitcoin cash mixed case cashaddr", BitcoinCashNetwork, "bitcoincash:Qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", false),
+			Entry("bitcoin cash cashaddr bad checksum", BitcoinCashNetwork, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", false),
+			Entry("bitcoin cash segwit", BitcoinCashNetwork, "bc1qw6syq5aa5z5ghkj3w7ux59wrk204txrn9k4rmm", false),
+			Entry("bitcoin sv legacy", BitcoinSVNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", true),
+			Entry("bitcoin sv cashaddr", BitcoinSVNetwork, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", false),
+			Entry("bitcoin gold p2pkh", BitcoinGoldNetwork, "GUfA8DYcC25YhaaBeqYSsPXYTUiTniXhen", true),
+			Entry("bitcoin gold p2sh", BitcoinGoldNetwork, "ASb7Mb5HYK8QS5UtHYYfbWSk1urK7xvLcm", true),
+			Entry("bitcoin gold p2wpkh", BitcoinGoldNetwork, "btg1qw6syq5aa5z5ghkj3w7ux59wrk204txrnnlnxwn", true),
+			Entry("bitcoin address on bitcoin gold", BitcoinGoldNetwork, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", false),
+			Entry("bitcoin testnet p2pkh", testNetwork(BitcoinAssetID, TestNet), "mrLC19Je2BuWQDkWSTriGYPyQJXKkkBmCx", true),
+			Entry("bitcoin testnet p2sh", testNetwork(BitcoinAssetID, TestNet), "2N44ThNe8NXHyv4bsX8AoVCXquBRW94Ls7W", true),
+			Entry("bitcoin testnet p2wpkh", testNetwork(BitcoinAssetID, TestNet), "tb1qw6syq5aa5z5ghkj3w7ux59wrk204txrn0swsqg", true),
+			Entry("bitcoin mainnet address on testnet", testNetwork(BitcoinAssetID, TestNet), "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", false),
+			Entry("bitcoin testnet address on mainnet", BitcoinNetwork, "mrLC19Je2BuWQDkWSTriGYPyQJXKkkBmCx", false),
+			Entry("bitcoin regtest p2wpkh", testNetwork(BitcoinAssetID, RegTest), "bcrt1qw6syq5aa5z5ghkj3w7ux59wrk204txrndehahp", true),
+			Entry("bitcoin testnet p2wpkh on regtest", testNetwork(BitcoinAssetID, RegTest), "tb1qw6syq5aa5z5ghkj3w7ux59wrk204txrn0swsqg", false),
+			Entry("litecoin testnet p2sh", testNetwork(LitecoinAssetID, TestNet), "QXRDpPWNPdM54FMv9ECpZtyH3ZsL5zGe29", true),
+			Entry("litecoin testnet legacy p2sh", testNetwork(LitecoinAssetID, TestNet), "2N44ThNe8NXHyv4bsX8AoVCXquBRW94Ls7W", true),
+			Entry("litecoin testnet p2wpkh", testNetwork(LitecoinAssetID, TestNet), "tltc1qw6syq5aa5z5ghkj3w7ux59wrk204txrnkcvwsp", true),
+			Entry("dogecoin testnet p2pkh", testNetwork(DogecoinAssetID, TestNet), "nf1PyMuDSYqG362gVJXMEnwYfK3DByPa6E", true),
+			Entry("bitcoin cash testnet cashaddr", testNetwork(BitcoinCashAssetID, TestNet), "bchtest:qpm2qsznhks23z7629mms6s4cwef74vcwvqcw003ap", true),
+			Entry("bitcoin cash regtest cashaddr", testNetwork(BitcoinCashAssetID, RegTest), "bchreg:qpm2qsznhks23z7629mms6s4cwef74vcwv6ycwvz78", true),
+			Entry("bitcoin cash mainnet cashaddr on testnet", testNetwork(BitcoinCashAssetID, TestNet), "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", false),
+		)
+	})
+
+	Describe("#BTCTestNetwork", func() {
+		It("Should not return a network the asset has no node for", func() {
+			_, ok := BTCTestNetwork(BitcoinGoldAssetID, RegTest)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("NetworkAssetID", func() {
+	DescribeTable("Should suffix the asset id with any network other than mainnet",
+		func(network, assetID string) {
+			Expect(NetworkAssetID(BitcoinAssetID, network)).To(Equal(assetID))
+		},
+		Entry("no network", "", "BTC"),
+		Entry("main", "main", "BTC"),
+		Entry("mainnet", "mainnet", "BTC"),
+		Entry("testnet", "testnet", "BTC-TESTNET"),
+		Entry("upper case testnet", "TESTNET", "BTC-TESTNET"),
+		Entry("regtest", "regtest", "BTC-REGTEST"),
+	)
+})
+
+func testNetwork(assetID, network string) *BTCNetwork {
+	net, ok := BTCTestNetwork(assetID, network)
+	if !ok {
+		panic("no " + network + " network for " + assetID)
+	}
+
+	return net
+}
@@ -0,0 +1,207 @@
+package transport
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// maxEnumeratedNFTs caps the token ids listed in a page, as each one costs a contract call.
+const maxEnumeratedNFTs = 20
+
+// nftABIJSON holds the parts of the ERC721 and ERC1155 interfaces used to look up ownership and decode transfers.
+const nftABIJSON = `[
+	{"constant":true,"inputs":[{"name":"_tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"},{"name":"_index","type":"uint256"}],"name":"tokenOfOwnerByIndex","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"interfaceID","type":"bytes4"}],"name":"supportsInterface","outputs":[{"name":"","type":"bool"}],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_from","type":"address"},{"indexed":true,"name":"_to","type":"address"},{"indexed":true,"name":"_tokenId","type":"uint256"}],"name":"Transfer","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_operator","type":"address"},{"indexed":true,"name":"_from","type":"address"},{"indexed":true,"name":"_to","type":"address"},{"indexed":false,"name":"_id","type":"uint256"},{"indexed":false,"name":"_value","type":"uint256"}],"name":"TransferSingle","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_operator","type":"address"},{"indexed":true,"name":"_from","type":"address"},{"indexed":true,"name":"_to","type":"address"},{"indexed":false,"name":"_ids","type":"uint256[]"},{"indexed":false,"name":"_values","type":"uint256[]"}],"name":"TransferBatch","type":"event"}
+]`
+
+var (
+	nftABI = mustParseABI(nftABIJSON)
+
+	// erc721EnumerableID is the ERC165 interface id of the ERC721 enumeration extension.
+	erc721EnumerableID = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+)
+
+// erc1155TransferSingle and erc1155TransferBatch hold the non indexed arguments of the ERC1155 transfer events.
+type erc1155TransferSingle struct {
+	Id    *big.Int
+	Value *big.Int
+}
+
+type erc1155TransferBatch struct {
+	Ids    []*big.Int
+	Values []*big.Int
+}
+
+// GetNFTOwner returns the owner of the ERC721 token with the given id, which can be decimal or 0x prefixed hex.
+func (e EthereumClient) GetNFTOwner(contract, tokenID string) (*transport.NFTOwnerResp, error) {
+	if !common.IsHexAddress(contract) {
+		return nil, fmt.Errorf("invalid contract address: %s", contract)
+	}
+
+	id, err := parseTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	nft := common.HexToAddress(contract)
+
+	// ownerOf reverts for tokens which don't exist or have been burned.
+	var owner common.Address
+	if err := callContract(e.Client, nftABI, nft, nil, &owner, "ownerOf", id); err != nil {
+		return nil, errors.Wrapf(err, "error getting owner of token: %s", id.String())
+	}
+
+	res := &transport.NFTOwnerResp{}
+	res.Data.NFT = transport.NFTOwner{
+		Contract: nft.String(),
+		TokenID:  id.String(),
+		Owner:    owner.String(),
+	}
+
+	return res, nil
+}
+
+// ListNFTs returns the number of ERC721 tokens of the contract held by addr. When the contract supports the
+// enumeration extension the ids of the tokens from offset are listed too, up to maxEnumeratedNFTs of them.
+func (e EthereumClient) ListNFTs(contract, addr string, offset int64) (*transport.NFTHoldingsResp, error) {
+	if !common.IsHexAddress(contract) {
+		return nil, fmt.Errorf("invalid contract address: %s", contract)
+	}
+
+	if !common.IsHexAddress(addr) {
+		return nil, fmt.Errorf("invalid address: %s", addr)
+	}
+
+	if offset < 0 || offset > math.MaxInt64-maxEnumeratedNFTs {
+		return nil, fmt.Errorf("invalid offset: %d", offset)
+	}
+
+	nft := common.HexToAddress(contract)
+	owner := common.HexToAddress(addr)
+
+	var balance *big.Int
+	if err := callContract(e.Client, nftABI, nft, nil, &balance, "balanceOf", owner); err != nil {
+		return nil, errors.Wrapf(err, "error getting nft balance of addr: %s", addr)
+	}
+
+	// contracts predating ERC165 revert on supportsInterface, so an error just means they can't be enumerated.
+	var enumerable bool
+	if err := callContract(e.Client, nftABI, nft, nil, &enumerable, "supportsInterface", erc721EnumerableID); err != nil {
+		enumerable = false
+	}
+
+	holdings := transport.NFTHoldings{
+		Contract:   nft.String(),
+		Owner:      owner.String(),
+		Balance:    balance.String(),
+		Enumerable: enumerable,
+	}
+
+	if enumerable {
+		end := offset + maxEnumeratedNFTs
+		if balance.Cmp(big.NewInt(end)) > 0 {
+			holdings.NextOffset = end
+		} else {
+			end = balance.Int64()
+		}
+
+		for i := offset; i < end; i++ {
+			var id *big.Int
+			if err := callContract(e.Client, nftABI, nft, nil, &id, "tokenOfOwnerByIndex", owner, big.NewInt(i)); err != nil {
+				return nil, errors.Wrapf(err, "error getting token %d of addr: %s", i, addr)
+			}
+
+			holdings.TokenIDs = append(holdings.TokenIDs, id.String())
+		}
+	}
+
+	res := &transport.NFTHoldingsResp{}
+	res.Data.NFTs = holdings
+
+	return res, nil
+}
+
+// decodeNFTTransfers returns the ERC721 and ERC1155 transfers emitted in the logs. ERC20 Transfer events share
+// the ERC721 signature but index only two arguments, so are told apart by their number of topics.
+func decodeNFTTransfers(logs []*types.Log) ([]transport.Transfer, error) {
+	var transfers []transport.Transfer
+	for _, l := range logs {
+		if len(l.Topics) != 4 {
+			continue
+		}
+
+		asset := l.Address.String()
+		switch l.Topics[0] {
+		case nftABI.Events["Transfer"].ID:
+			transfers = append(transfers, transport.Transfer{
+				From:    common.BytesToAddress(l.Topics[1].Bytes()).String(),
+				To:      common.BytesToAddress(l.Topics[2].Bytes()).String(),
+				Value:   "1",
+				Asset:   asset,
+				TokenID: l.Topics[3].Big().String(),
+			})
+		case nftABI.Events["TransferSingle"].ID:
+			var ev erc1155TransferSingle
+			if err := nftABI.UnpackIntoInterface(&ev, "TransferSingle", l.Data); err != nil {
+				return nil, errors.Wrapf(err, "error decoding TransferSingle log %d", l.Index)
+			}
+
+			transfers = append(transfers, transport.Transfer{
+				From:    common.BytesToAddress(l.Topics[2].Bytes()).String(),
+				To:      common.BytesToAddress(l.Topics[3].Bytes()).String(),
+				Value:   ev.Value.String(),
+				Asset:   asset,
+				TokenID: ev.Id.String(),
+			})
+		case nftABI.Events["TransferBatch"].ID:
+			var ev erc1155TransferBatch
+			if err := nftABI.UnpackIntoInterface(&ev, "TransferBatch", l.Data); err != nil {
+				return nil, errors.Wrapf(err, "error decoding TransferBatch log %d", l.Index)
+			}
+
+			if len(ev.Ids) != len(ev.Values) {
+				return nil, fmt.Errorf("TransferBatch log %d has %d ids but %d values", l.Index, len(ev.Ids), len(ev.Values))
+			}
+
+			for i, id := range ev.Ids {
+				transfers = append(transfers, transport.Transfer{
+					From:    common.BytesToAddress(l.Topics[2].Bytes()).String(),
+					To:      common.BytesToAddress(l.Topics[3].Bytes()).String(),
+					Value:   ev.Values[i].String(),
+					Asset:   asset,
+					TokenID: id.String(),
+				})
+			}
+		}
+	}
+
+	return transfers, nil
+}
+
+// parseTokenID parses a decimal or 0x prefixed hex token id.
+func parseTokenID(tokenID string) (*big.Int, error) {
+	s, base := tokenID, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+
+	id, ok := new(big.Int).SetString(s, base)
+	if !ok || id.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token id: %s", tokenID)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,230 @@
+package transport_test
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/pkg/test"
+)
+
+var _ = Describe("NFTs", func() {
+	var (
+		fb *test.FixtureBox
+	)
+
+	contract := "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d"
+	owner := "0x1111111111111111111111111111111111111111"
+	paddedOwner := "0000000000000000000000001111111111111111111111111111111111111111"
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+
+		fb = &test.FixtureBox{
+			Base: path.Join(dir, "../test/fixtures"),
+		}
+	})
+
+	word := func(n string) string {
+		return "0x" + strings.Repeat("0", 64-len(n)) + n
+	}
+
+	Describe("#GetNFTOwner", func() {
+		It("Should call ownerOf with the hex or decimal token id", func() {
+			ownerOf := "0x6352211e000000000000000000000000000000000000000000000000000000000000001f"
+
+			server := test.NewTestServer(GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, ownerOf, 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 1, "0x"+paddedOwner))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, ownerOf, 2)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 2, "0x"+paddedOwner))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{Client: client}
+
+			expected := PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"NFT": MatchAllFields(Fields{
+						"Contract": Equal("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"),
+						"TokenID":  Equal("31"),
+						"Owner":    Equal(owner),
+					}),
+				}),
+			}))
+
+			res, err := ec.GetNFTOwner(contract, "0x1f")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(expected)
+
+			res, err = ec.GetNFTOwner(contract, "31")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(expected)
+		})
+
+		It("Should reject an invalid token id without calling the node", func() {
+			ec := EthereumClient{}
+
+			_, err := ec.GetNFTOwner(contract, "0xzz")
+			Expect(err).To(MatchError("invalid token id: 0xzz"))
+		})
+	})
+
+	Describe("#ListNFTs", func() {
+		It("Should enumerate the tokens held by the owner", func() {
+			server := test.NewTestServer(GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x70a08231"+paddedOwner, 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 1, word("2")))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x01ffc9a7780e9d6300000000000000000000000000000000000000000000000000000000", 2)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 2, word("1")))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x2f745c59"+paddedOwner+word("0")[2:], 3)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 3, word("7")))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x2f745c59"+paddedOwner+word("1")[2:], 4)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 4, word("1f")))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{Client: client}
+
+			res, err := ec.ListNFTs(contract, owner, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res).To(PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"NFTs": MatchAllFields(Fields{
+						"Contract":   Equal("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"),
+						"Owner":      Equal(owner),
+						"Balance":    Equal("2"),
+						"Enumerable": BeTrue(),
+						"TokenIDs":   Equal([]string{"7", "31"}),
+						"NextOffset": BeZero(),
+					}),
+				}),
+			})))
+		})
+
+		It("Should only return the balance of contracts which can't be enumerated", func() {
+			server := test.NewTestServer(GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x70a08231"+paddedOwner, 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 1, word("2")))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x01ffc9a7780e9d6300000000000000000000000000000000000000000000000000000000", 2)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 2, word("0")))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{Client: client}
+
+			res, err := ec.ListNFTs(contract, owner, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.NFTs.Balance).To(Equal("2"))
+			Expect(res.Data.NFTs.Enumerable).To(BeFalse())
+			Expect(res.Data.NFTs.TokenIDs).To(BeEmpty())
+		})
+
+		It("Should list a page of token ids from the offset", func() {
+			listed := func(offset int64) *test.Server {
+				calls := []test.ExpectedCall{
+					test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x70a08231"+paddedOwner, 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 1, word("16")))),
+					test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x01ffc9a7780e9d6300000000000000000000000000000000000000000000000000000000", 2)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 2, word("1")))),
+				}
+
+				for i := offset; i < 22 && i < offset+20; i++ {
+					id := len(calls) + 1
+					index := fmt.Sprintf("%x", i)
+					calls = append(calls, test.ExpectRPCJsonSuccess(
+						MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x2f745c59"+paddedOwner+word(index)[2:], id)),
+						MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", id, word(fmt.Sprintf("%x", 100+i)))),
+					))
+				}
+
+				return test.NewTestServer(GinkgoT(), calls...)
+			}
+
+			server := listed(0)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			res, err := EthereumClient{Client: client}.ListNFTs(contract, owner, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.NFTs.Balance).To(Equal("22"))
+			Expect(res.Data.NFTs.TokenIDs).To(HaveLen(20))
+			Expect(res.Data.NFTs.TokenIDs[0]).To(Equal("100"))
+			Expect(res.Data.NFTs.NextOffset).To(Equal(int64(20)))
+
+			next := listed(20)
+			defer next.Close()
+
+			client, err = ethclient.Dial(next.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			res, err = EthereumClient{Client: client}.ListNFTs(contract, owner, res.Data.NFTs.NextOffset)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Data.NFTs.TokenIDs).To(Equal([]string{"120", "121"}))
+			Expect(res.Data.NFTs.NextOffset).To(BeZero())
+		})
+
+		It("Should reject an invalid address without calling the node", func() {
+			_, err := EthereumClient{}.ListNFTs(contract, "address", 0)
+			Expect(err).To(MatchError("invalid address: address"))
+		})
+	})
+
+	Describe("#GetTransactionByHash with nft transfers", func() {
+		It("Should decode the ERC721 and ERC1155 transfers emitted by the transaction", func() {
+			hash := "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+			blockHash := "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"
+
+			server := test.NewTestServer(
+				GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash.json", hash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionReceipt_nft.json", hash, blockHash))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{Client: client}
+
+			tran, err := ec.GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			transfer := func(from, to, value, asset, id string) types.GomegaMatcher {
+				return MatchAllFields(Fields{
+					"From":    Equal(from),
+					"To":      Equal(to),
+					"Value":   Equal(value),
+					"Asset":   Equal(asset),
+					"TokenID": Equal(id),
+					"Issuer":  BeEmpty(),
+				})
+			}
+
+			// the ERC20 transfer in the receipt isn't an nft so is left out.
+			Expect(tran.Data.Transaction.Value).To(Equal("4290000000000000"))
+			Expect(tran.Data.Transaction.Transfers).To(ConsistOf(
+				transfer(owner, "0x2222222222222222222222222222222222222222", "1", "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", "31"),
+				transfer("0x2222222222222222222222222222222222222222", owner, "10", "0x76BE3b62873462d2142405439777e971754E8E77", "1"),
+				transfer("0x2222222222222222222222222222222222222222", owner, "20", "0x76BE3b62873462d2142405439777e971754E8E77", "2"),
+			))
+		})
+	})
+})
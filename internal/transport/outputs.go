@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/btcsuite/btcd/txscript"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// btcOutputTypes maps the script types reported by bitcoin core to the output types of the common interface.
+var btcOutputTypes = map[string]string{
+	"pubkey":                "p2pk",
+	"pubkeyhash":            "p2pkh",
+	"scripthash":            "p2sh",
+	"witness_v0_keyhash":    "p2wpkh",
+	"witness_v0_scripthash": "p2wsh",
+	"witness_v1_taproot":    "p2tr",
+	"multisig":              "multisig",
+	"nulldata":              "nulldata",
+}
+
+// btcRawTransaction is the result of a verbose getrawtransaction call. It is decoded directly as btcjson
+// predates the single address field which bitcoin core 22 onwards returns in place of addresses.
+type btcRawTransaction struct {
+	Txid          string    `json:"txid"`
+	Hash          string    `json:"hash"`
+	Vin           []btcVin  `json:"vin"`
+	Vout          []btcVout `json:"vout"`
+	Confirmations int64     `json:"confirmations"`
+}
+
+// btcVin is a transaction input, coinbase inputs only holding the coinbase data and no previous output.
+type btcVin struct {
+	Coinbase string `json:"coinbase"`
+	Txid     string `json:"txid"`
+	Vout     uint32 `json:"vout"`
+}
+
+type btcVout struct {
+	Value        float64         `json:"value"`
+	N            int             `json:"n"`
+	ScriptPubKey btcScriptPubKey `json:"scriptPubKey"`
+}
+
+type btcScriptPubKey struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	Type      string   `json:"type"`
+	Address   string   `json:"address"`
+	Addresses []string `json:"addresses"`
+}
+
+// btcOutput normalises the output, nodes which predate taproot reporting its outputs as witness_unknown.
+func btcOutput(v btcVout) transport.Output {
+	script := v.ScriptPubKey
+
+	out := transport.Output{
+		Index: v.N,
+		Type:  btcOutputType(script),
+		Value: strconv.FormatFloat(v.Value, 'f', -1, 64),
+	}
+
+	switch {
+	case out.Type == "multisig":
+		out.Required, out.PubKeys = btcMultisigKeys(script.Asm)
+	case script.Address != "":
+		out.Address = script.Address
+	case len(script.Addresses) == 1:
+		out.Address = script.Addresses[0]
+	}
+
+	return out
+}
+
+func btcOutputType(script btcScriptPubKey) string {
+	if t, ok := btcOutputTypes[script.Type]; ok {
+		return t
+	}
+
+	// a version 1 witness program of 32 bytes: OP_1 OP_DATA_32 <key>.
+	if script.Type == "witness_unknown" && len(script.Hex) == 68 && strings.HasPrefix(script.Hex, "5120") {
+		return "p2tr"
+	}
+
+	return "nonstandard"
+}
+
+// btcMultisigKeys returns the signatures required and the keys of a bare multisig output from its asm,
+// e.g. 1 <pubkey> <pubkey> 2 OP_CHECKMULTISIG.
+func btcMultisigKeys(asm string) (int, []string) {
+	fields := strings.Fields(asm)
+	if len(fields) < 4 || fields[len(fields)-1] != "OP_CHECKMULTISIG" {
+		return 0, nil
+	}
+
+	required, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, nil
+	}
+
+	return required, fields[1 : len(fields)-2]
+}
+
+// btcMemo returns the data pushed by an OP_RETURN output, as text when it is printable and hex encoded otherwise.
+func btcMemo(scriptHex string) string {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return ""
+	}
+
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		return ""
+	}
+
+	var data []byte
+	for _, p := range pushes {
+		data = append(data, p...)
+	}
+
+	if printable(data) {
+		return string(data)
+	}
+
+	return hex.EncodeToString(data)
+}
+
+func printable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
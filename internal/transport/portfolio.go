@@ -0,0 +1,215 @@
+package transport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+const (
+	// EthereumChain is the chain name grouping ETH and all the ERC20 tokens which share an ethereum address.
+	EthereumChain = "ethereum"
+	// EthereumClassicChain is the chain name grouping ETC and the ERC20 tokens deployed on ethereum classic.
+	EthereumClassicChain = "ethereumclassic"
+)
+
+var (
+	// AssetDecimals are the number of decimals used by assets whose clients return balances in the
+	// smallest unit of the asset, e.g. wei or mutez. Assets not listed are returned as reported by the node.
+	AssetDecimals = map[string]int{
+		EthereumAssetID:        18,
+		EthereumclassicAssetID: 18,
+		CardanoAssetID:         6,
+		IotaAssetID:            6,
+		LiskAssetID:            8,
+		NanoAssetID:            30,
+		NemAssetID:             6,
+		TezosAssetID:           6,
+		TronAssetID:            6,
+		WavesAssetID:           8,
+	}
+)
+
+// Portfolio struct to map the balances of every asset held by an owner to the required json format.
+type Portfolio struct {
+	Data PortfolioData `json:"data"`
+}
+
+// PortfolioData holds the normalised balances of every asset held by an owner on a chain.
+type PortfolioData struct {
+	Owner  string           `json:"owner"`
+	Chain  string           `json:"chain"`
+	Assets []PortfolioAsset `json:"assets"`
+	Errors []PortfolioError `json:"errors,omitempty"`
+}
+
+// PortfolioAsset is a single asset balance within a portfolio.
+// Balance has the asset decimals applied, RawBalance is the balance as reported by the node.
+type PortfolioAsset struct {
+	Asset      string `json:"asset"`
+	Balance    string `json:"balance"`
+	RawBalance string `json:"rawBalance,omitempty"`
+	Decimals   *int   `json:"decimals,omitempty"`
+	Issuer     string `json:"issuer,omitempty"`
+}
+
+// PortfolioError records a client which failed to return a balance for the portfolio.
+type PortfolioError struct {
+	AssetID string `json:"assetId"`
+	Error   string `json:"error"`
+}
+
+// RegisterChain groups the given asset ids under a chain name so that a single owner
+// address can be looked up across all of them with GetPortfolio.
+func (r *CoinResolver) RegisterChain(chain string, assets ...string) *CoinResolver {
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.Chains == nil {
+		r.Chains = make(map[string][]string)
+	}
+
+	r.Chains[strings.ToLower(chain)] = assets
+	return r
+}
+
+// GetPortfolio returns the balance of every asset held by addr on the given chain.
+// If no chain is registered under the name it is treated as an asset id, so clients which
+// already return several assets, e.g. Stellar or EOS, are normalised in the same way.
+// An error is only returned if no client returned a balance.
+func (r CoinResolver) GetPortfolio(chain, addr string) (*PortfolioData, error) {
+	assets, ok := r.Chains[strings.ToLower(chain)]
+	if !ok {
+		assets = []string{chain}
+	}
+
+	balances := make([]*transport.Balance, len(assets))
+	errs := make([]error, len(assets))
+
+	wg := sync.WaitGroup{}
+	for i, asset := range assets {
+		client, err := r.Get(asset)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, client transport.CoinClient) {
+			defer wg.Done()
+
+			balances[i], errs[i] = client.GetBalance(addr)
+		}(i, client)
+	}
+
+	wg.Wait()
+
+	data := &PortfolioData{
+		Owner:  addr,
+		Chain:  chain,
+		Assets: []PortfolioAsset{},
+	}
+
+	for i, b := range balances {
+		if errs[i] != nil {
+			data.Errors = append(data.Errors, PortfolioError{AssetID: assets[i], Error: errs[i].Error()})
+			continue
+		}
+
+		if b == nil {
+			continue
+		}
+
+		for _, a := range b.Data.Assets {
+			data.Assets = append(data.Assets, normaliseAsset(a))
+		}
+	}
+
+	if len(data.Errors) > 0 && len(data.Errors) == len(assets) {
+		return nil, fmt.Errorf("could not fetch portfolio for chain: %s, %s", chain, data.Errors[0].Error)
+	}
+
+	return data, nil
+}
+
+func normaliseAsset(a transport.Asset) PortfolioAsset {
+	p := PortfolioAsset{
+		Asset:   a.Asset,
+		Balance: a.Balance,
+		Issuer:  a.Issuer,
+	}
+
+	// issued assets only share their code with the asset of the same id, e.g. a Ripple IOU named TRX.
+	decimals, ok := assetDecimals(a.Asset)
+	if !ok || a.Issuer != "" {
+		return p
+	}
+
+	// the ERC20 clients return their balances in whole tokens already.
+	if isERC20Asset(a.Asset) {
+		raw, err := transport.ParseUnits(a.Balance, decimals)
+		if err != nil {
+			return p
+		}
+
+		p.RawBalance = raw
+		p.Decimals = &decimals
+
+		return p
+	}
+
+	v, err := transport.FormatUnits(a.Balance, decimals)
+	if err != nil {
+		return p
+	}
+
+	p.Balance = v
+	p.RawBalance = a.Balance
+	p.Decimals = &decimals
+
+	return p
+}
+
+// isERC20Asset reports whether the asset is one of the ERC20 tokens rather than the native asset of a chain.
+func isERC20Asset(asset string) bool {
+	asset = strings.ToUpper(asset)
+
+	if _, ok := AssetDecimals[baseAssetID(asset)]; ok {
+		return false
+	}
+
+	_, ok := ERC20Tokens[asset]
+	return ok
+}
+
+func assetDecimals(asset string) (int, bool) {
+	asset = strings.ToUpper(asset)
+
+	if d, ok := AssetDecimals[baseAssetID(asset)]; ok {
+		return d, true
+	}
+
+	if t, ok := ERC20Tokens[asset]; ok {
+		return t.Decimals, true
+	}
+
+	return 0, false
+}
+
+// chainAssets returns the native asset of the chain followed by every configured ERC20 token
+// deployed on it and the network in a stable order.
+func chainAssets(chain, network, native string) []string {
+	var tokens []string
+	for id, config := range ERC20Tokens {
+		if erc20Chain(config) == chain && erc20Network(config) == network {
+			tokens = append(tokens, id)
+		}
+	}
+
+	sort.Strings(tokens)
+
+	return append([]string{native}, tokens...)
+}
@@ -0,0 +1,101 @@
+package transport_test
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Portfolio", func() {
+	var (
+		resolver *CoinResolver
+		ctrl     *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		resolver = &CoinResolver{
+			C:  make(map[string]transport.CoinClient),
+			Mu: &sync.Mutex{},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#GetPortfolio", func() {
+		It("Should aggregate the balances of every asset on the chain and apply decimals", func() {
+			tron := mock_transport.NewMockCoinClient(ctrl)
+			tether := mock_transport.NewMockCoinClient(ctrl)
+			other := mock_transport.NewMockCoinClient(ctrl)
+			failing := mock_transport.NewMockCoinClient(ctrl)
+
+			resolver.Register(TronAssetID, tron)
+			resolver.Register(TetherAssetID, tether)
+			resolver.Register("other", other)
+			resolver.Register("failing", failing)
+			resolver.RegisterChain("test", TronAssetID, TetherAssetID, "other", "failing", "missing")
+
+			tron.EXPECT().GetBalance("addr").Return(balanceOf(TronAssetID, "150840600"), nil)
+			tether.EXPECT().GetBalance("addr").Return(balanceOf(TetherAssetID, "150.8406"), nil)
+			other.EXPECT().GetBalance("addr").Return(&transport.Balance{
+				Data: transport.BalanceData{
+					Assets: []transport.Asset{
+						{Asset: "OTHER", Balance: "1.0000000"},
+						{Asset: "USD", Balance: "25.5000000"},
+						{Asset: TronAssetID, Balance: "12", Issuer: "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+					},
+				},
+			}, nil)
+			failing.EXPECT().GetBalance("addr").Return(nil, errors.New("node down"))
+
+			data, err := resolver.GetPortfolio("TEST", "addr")
+			Expect(err).ToNot(HaveOccurred())
+
+			decimals := 6
+			Expect(data).To(Equal(&PortfolioData{
+				Owner: "addr",
+				Chain: "TEST",
+				Assets: []PortfolioAsset{
+					{Asset: TronAssetID, Balance: "150.8406", RawBalance: "150840600", Decimals: &decimals},
+					{Asset: TetherAssetID, Balance: "150.8406", RawBalance: "150840600", Decimals: &decimals},
+					{Asset: "OTHER", Balance: "1.0000000"},
+					{Asset: "USD", Balance: "25.5000000"},
+					{Asset: TronAssetID, Balance: "12", Issuer: "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"},
+				},
+				Errors: []PortfolioError{
+					{AssetID: "failing", Error: "node down"},
+					{AssetID: "missing", Error: "could not find client named: missing, have you registered the client"},
+				},
+			}))
+		})
+
+		It("Should treat an unregistered chain as an asset id", func() {
+			client := mock_transport.NewMockCoinClient(ctrl)
+			resolver.Register("xlm", client)
+
+			client.EXPECT().GetBalance("addr").Return(balanceOf("XLM", "10.0000000"), nil)
+
+			data, err := resolver.GetPortfolio("xlm", "addr")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data.Assets).To(Equal([]PortfolioAsset{
+				{Asset: "XLM", Balance: "10.0000000"},
+			}))
+			Expect(data.Errors).To(BeEmpty())
+		})
+
+		It("Should return an error if no client returned a balance", func() {
+			_, err := resolver.GetPortfolio("missing", "addr")
+			Expect(err).To(MatchError("could not fetch portfolio for chain: missing, could not find client named: missing, have you registered the client"))
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
@@ -48,23 +49,62 @@ func NewResolver(l echo.Logger) *CoinResolver {
 	r.Register(OntologyAssetID, must(NewOntologyClient()))
 	r.Register(LiskAssetID, must(NewLiskClient()))
 	r.Register(WavesAssetID, must(NewWavesClient()))
-	r.Register(TetherAssetID, must(NewERC20Client(TetherAssetID)))
-	r.Register(OxAssetID, must(NewERC20Client(OxAssetID)))
-	r.Register(BATAssetID, must(NewERC20Client(BATAssetID)))
-	r.Register(ChainLinkAssetID, must(NewERC20Client(ChainLinkAssetID)))
-	r.Register(IconAssetID, must(NewERC20Client(IconAssetID)))
-	r.Register(MakerAssetID, must(NewERC20Client(MakerAssetID)))
-	r.Register(OmiseGoAssetID, must(NewERC20Client(OmiseGoAssetID)))
-	r.Register(VeChainAssetID, must(NewERC20Client(VeChainAssetID)))
-	r.Register(ZilliqaAssetID, must(NewERC20Client(ZilliqaAssetID)))
 	r.Register(QtumAssetID, must(NewQtumClient()))
 	r.Register(TezosAssetID, must(NewTezosClient()))
 	r.Register(IotaAssetID, must(NewIotaClient()))
 	r.Register(DecredAssetID, must(NewDecredClient()))
 
+	r.registerTestNetworks()
+	r.registerERC20Tokens()
+
 	return r
 }
 
+// testNetworkClient builds the client of an asset on a test network, its node configured by the os variables
+// prefixed with coin and the network.
+type testNetworkClient struct {
+	coin string
+	new  func(network string) (transport.CoinClient, error)
+}
+
+// testNetworkClients are the assets which can be pointed at test networks keyed by asset id.
+var testNetworkClients = map[string]testNetworkClient{
+	BitcoinAssetID:         {"BITCOIN", func(n string) (transport.CoinClient, error) { return newBitcoinClient(n) }},
+	LitecoinAssetID:        {"LITECOIN", func(n string) (transport.CoinClient, error) { return newLitecoinClient(n) }},
+	DogecoinAssetID:        {"DOGECOIN", func(n string) (transport.CoinClient, error) { return newDogecoinClient(n) }},
+	BitcoinCashAssetID:     {"BITCOINCASH", func(n string) (transport.CoinClient, error) { return newBitcoincashClient(n) }},
+	BitcoinsvAssetID:       {"BITCOINSV", func(n string) (transport.CoinClient, error) { return newBitcoinsvClient(n) }},
+	BitcoinGoldAssetID:     {"BITCOINGOLD", func(n string) (transport.CoinClient, error) { return newBitcoinGoldClient(n) }},
+	EthereumAssetID:        {"ETHEREUM", func(n string) (transport.CoinClient, error) { return newEthereumClient(EthereumAssetID, "ETHEREUM", n) }},
+	EthereumclassicAssetID: {"ETHEREUMCLASSIC", func(n string) (transport.CoinClient, error) { return newEthereumClassicClient(n) }},
+	RippleAssetID:          {"RIPPLE", func(n string) (transport.CoinClient, error) { return newRippleClient(n) }},
+	StellarAssetID:         {"STELLAR", func(n string) (transport.CoinClient, error) { return newStellarClient(n) }},
+	TronAssetID:            {"TRON", func(n string) (transport.CoinClient, error) { return newTronClient(n) }},
+}
+
+// registerTestNetworks registers a client for every asset on each test network its node is configured for,
+// under the asset id of the network, e.g. BTC-TESTNET when BITCOIN_TESTNET_URL is set.
+func (r *CoinResolver) registerTestNetworks() {
+	ids := make([]string, 0, len(testNetworkClients))
+	for id := range testNetworkClients {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		c := testNetworkClients[id]
+
+		for _, network := range TestNetworks {
+			if os.Getenv(networkEnv(c.coin, network)+"_URL") == "" {
+				continue
+			}
+
+			r.Register(NetworkAssetID(id, network), must(c.new(network)))
+		}
+	}
+}
+
 func must(c transport.CoinClient, err error) transport.CoinClient {
 	if err != nil {
 		s := reflect.TypeOf(c).String()
@@ -107,12 +147,16 @@ type Resolver interface {
 	Register(name string, client transport.CoinClient) *CoinResolver
 	Get(name string) (transport.CoinClient, error)
 	GetNodes(info bool) []CoinNode
+	GetBalances(lookups []BalanceLookup) []BalanceResult
+	GetTransactions(lookups []TransactionLookup) []TransactionResult
+	GetPortfolio(chain, addr string) (*PortfolioData, error)
 }
 
 // CoinResolver is a lookup container for registering and calling
 // different lambdas based on their coin name
 type CoinResolver struct {
 	C      map[string]transport.CoinClient
+	Chains map[string][]string
 	Mu     *sync.Mutex
 	Logger echo.Logger
 }
@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var (
+	// erc20NodeEnv maps the chain a token is deployed on to the prefix of the os variables configuring its node.
+	erc20NodeEnv = map[string]string{
+		EthereumChain:        "ETHEREUM",
+		EthereumClassicChain: "ETHEREUMCLASSIC",
+	}
+
+	// erc20Metadata caches the symbol and decimals of the contracts looked up by GetTokenBalance.
+	erc20Metadata = &erc20MetadataCache{
+		meta: map[string]erc20TokenMetadata{},
+	}
+)
+
+// erc20TokenMetadata holds the properties of a token contract which never change once it is deployed.
+type erc20TokenMetadata struct {
+	Symbol   string
+	Decimals int
+}
+
+type erc20MetadataCache struct {
+	mu   sync.Mutex
+	meta map[string]erc20TokenMetadata
+}
+
+func (c *erc20MetadataCache) get(key string) (erc20TokenMetadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.meta[key]
+	return m, ok
+}
+
+func (c *erc20MetadataCache) set(key string, m erc20TokenMetadata) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.meta[key] = m
+}
+
+// LoadERC20Tokens adds the tokens listed in the json file at path to ERC20Tokens, replacing any
+// configured under the same asset id. The file holds a list of ERC20Config objects, those deployed on
+// a test network being added under the asset id of the network, e.g. USDT-TESTNET.
+func LoadERC20Tokens(path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading erc20 tokens file: %s", path)
+	}
+
+	var tokens []ERC20Config
+	if err := json.Unmarshal(raw, &tokens); err != nil {
+		return errors.Wrapf(err, "error decoding erc20 tokens file: %s", path)
+	}
+
+	for i, t := range tokens {
+		if t.AssetID == "" {
+			return fmt.Errorf("erc20 token %d has no assetId", i)
+		}
+
+		if !common.IsHexAddress(t.ContractAddr) {
+			return fmt.Errorf("erc20 token: %s has invalid contract address: %s", t.AssetID, t.ContractAddr)
+		}
+
+		if t.Decimals < 0 {
+			return fmt.Errorf("erc20 token: %s has negative decimals", t.AssetID)
+		}
+
+		if _, ok := erc20NodeEnv[erc20Chain(t)]; !ok {
+			return fmt.Errorf("erc20 token: %s is deployed on unknown chain: %s", t.AssetID, t.Chain)
+		}
+
+		if !validNetwork(erc20Network(t)) {
+			return fmt.Errorf("erc20 token: %s is deployed on unknown network: %s", t.AssetID, t.Network)
+		}
+	}
+
+	for _, t := range tokens {
+		t.AssetID = strings.ToUpper(t.AssetID)
+		ERC20Tokens[NetworkAssetID(t.AssetID, erc20Network(t))] = t
+	}
+
+	return nil
+}
+
+// registerERC20Tokens registers a client for every configured token, including those listed in the file
+// at ERC20_TOKENS_PATH, and groups them with the native asset of their chain. Tokens deployed on a test
+// network are only registered if the node of the network is configured.
+func (r *CoinResolver) registerERC20Tokens() {
+	if path := os.Getenv("ERC20_TOKENS_PATH"); path != "" {
+		if err := LoadERC20Tokens(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ids := make([]string, 0, len(ERC20Tokens))
+	for id := range ERC20Tokens {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		config := ERC20Tokens[id]
+
+		network := erc20Network(config)
+		if network != MainNet && os.Getenv(networkEnv(erc20NodeEnv[erc20Chain(config)], network)+"_URL") == "" {
+			continue
+		}
+
+		if _, err := r.Get(id); err == nil {
+			log.Fatalf("erc20 token: %s clashes with a registered asset", id)
+		}
+
+		r.Register(id, must(NewERC20Client(id)))
+	}
+
+	r.RegisterChain(EthereumChain, chainAssets(EthereumChain, MainNet, EthereumAssetID)...)
+	r.RegisterChain(EthereumClassicChain, chainAssets(EthereumClassicChain, MainNet, EthereumclassicAssetID)...)
+
+	for _, network := range TestNetworks {
+		for chain, native := range map[string]string{EthereumChain: EthereumAssetID, EthereumClassicChain: EthereumclassicAssetID} {
+			native = NetworkAssetID(native, network)
+			if _, err := r.Get(native); err != nil {
+				continue
+			}
+
+			r.RegisterChain(chain+"-"+network, chainAssets(chain, network, native)...)
+		}
+	}
+}
+
+// erc20Network returns the network the token is deployed on.
+func erc20Network(config ERC20Config) string {
+	if config.Network == "" {
+		return MainNet
+	}
+
+	return strings.ToLower(config.Network)
+}
+
+// erc20Chain returns the chain the token is deployed on.
+func erc20Chain(config ERC20Config) string {
+	if config.Chain == "" {
+		return EthereumChain
+	}
+
+	return strings.ToLower(config.Chain)
+}
+
+// GetTokenBalance returns the balance of addr in the ERC20 token deployed at contract, so that tokens which
+// aren't configured can be looked up. The symbol and decimals are read from the contract the first time
+// it is seen and cached.
+func (e EthereumClient) GetTokenBalance(contract, addr string) (*transport.TokenBalanceResp, error) {
+	if !common.IsHexAddress(contract) {
+		return nil, fmt.Errorf("invalid contract address: %s", contract)
+	}
+
+	token := common.HexToAddress(contract)
+
+	meta, err := e.tokenMetadata(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance *big.Int
+	if err := callERC20(e.Client, token, nil, &balance, "balanceOf", common.HexToAddress(addr)); err != nil {
+		return nil, errors.Wrapf(err, "error getting balance of addr: %s", addr)
+	}
+
+	formatted, err := transport.FormatUnits(balance.String(), meta.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &transport.TokenBalanceResp{}
+	res.Data.Token = transport.TokenBalance{
+		Contract:   token.String(),
+		Symbol:     meta.Symbol,
+		Decimals:   meta.Decimals,
+		Balance:    formatted,
+		RawBalance: balance.String(),
+	}
+
+	return res, nil
+}
+
+// tokenMetadata returns the symbol and decimals of the token, keyed by the client's asset as the same
+// contract address can be deployed on ethereum and ethereum classic.
+func (e EthereumClient) tokenMetadata(token common.Address) (erc20TokenMetadata, error) {
+	key := e.AssetID + ":" + token.String()
+	if meta, ok := erc20Metadata.get(key); ok {
+		return meta, nil
+	}
+
+	out, err := rawCallContract(e.Client, erc20ABI, token, nil, "symbol")
+	if err != nil {
+		return erc20TokenMetadata{}, errors.Wrapf(err, "error getting symbol of contract: %s", token.String())
+	}
+
+	var symbol string
+	if err := erc20ABI.UnpackIntoInterface(&symbol, "symbol", out); err != nil {
+		// some early tokens, e.g. MKR, return their symbol as a bytes32.
+		if len(out) != 32 {
+			return erc20TokenMetadata{}, errors.Wrapf(err, "error decoding symbol of contract: %s", token.String())
+		}
+
+		symbol = strings.TrimRight(string(out), "\x00")
+	}
+
+	var decimals uint8
+	if err := callERC20(e.Client, token, nil, &decimals, "decimals"); err != nil {
+		return erc20TokenMetadata{}, errors.Wrapf(err, "error getting decimals of contract: %s", token.String())
+	}
+
+	meta := erc20TokenMetadata{Symbol: symbol, Decimals: int(decimals)}
+	erc20Metadata.set(key, meta)
+
+	return meta, nil
+}
@@ -0,0 +1,132 @@
+package transport_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/pkg/test"
+)
+
+var _ = Describe("Tokens", func() {
+	var (
+		fb *test.FixtureBox
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+
+		fb = &test.FixtureBox{
+			Base: path.Join(dir, "../test/fixtures"),
+		}
+	})
+
+	Describe("#LoadERC20Tokens", func() {
+		var file string
+
+		BeforeEach(func() {
+			f, err := ioutil.TempFile("", "erc20-tokens")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+
+			file = f.Name()
+		})
+
+		AfterEach(func() {
+			delete(ERC20Tokens, "UNI")
+			delete(ERC20Tokens, "CLASSIC")
+			Expect(os.Remove(file)).To(Succeed())
+		})
+
+		It("Should add the tokens in the file to the configured tokens", func() {
+			Expect(ioutil.WriteFile(file, []byte(`[
+				{"assetId": "uni", "contractAddr": "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984", "decimals": 18},
+				{"assetId": "CLASSIC", "contractAddr": "0x1111111111111111111111111111111111111111", "decimals": 8, "chain": "ethereumclassic"}
+			]`), 0644)).To(Succeed())
+
+			Expect(LoadERC20Tokens(file)).To(Succeed())
+
+			Expect(ERC20Tokens).To(HaveKeyWithValue("UNI", ERC20Config{
+				AssetID:      "UNI",
+				ContractAddr: "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984",
+				Decimals:     18,
+			}))
+			Expect(ERC20Tokens).To(HaveKeyWithValue("CLASSIC", ERC20Config{
+				AssetID:      "CLASSIC",
+				ContractAddr: "0x1111111111111111111111111111111111111111",
+				Decimals:     8,
+				Chain:        EthereumClassicChain,
+			}))
+			Expect(ERC20Tokens).To(HaveKey(TetherAssetID))
+		})
+
+		It("Should reject the file if a token is invalid", func() {
+			Expect(ioutil.WriteFile(file, []byte(`[
+				{"assetId": "UNI", "contractAddr": "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984", "decimals": 18},
+				{"assetId": "CLASSIC", "contractAddr": "0x1111", "decimals": 8}
+			]`), 0644)).To(Succeed())
+
+			Expect(LoadERC20Tokens(file)).To(MatchError("erc20 token: CLASSIC has invalid contract address: 0x1111"))
+			Expect(ERC20Tokens).ToNot(HaveKey("UNI"))
+		})
+	})
+
+	Describe("#GetTokenBalance", func() {
+		It("Should read the token metadata once and the balance from the contract", func() {
+			contract := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+			addr := "0xd6bd8F134262109E36EA70ee89548B0Bc8bF6D0c"
+			balanceOf := "0x70a08231000000000000000000000000d6bd8f134262109e36ea70ee89548b0bc8bf6d0c"
+
+			// symbol() returns the abi encoded string USDT.
+			symbol := "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000004" +
+				"5553445400000000000000000000000000000000000000000000000000000000"
+			decimals := "0x0000000000000000000000000000000000000000000000000000000000000006"
+			balance := "0x0000000000000000000000000000000000000000000000000000000008fda518"
+
+			server := test.NewTestServer(GinkgoT(),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x95d89b41", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 1, symbol))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, "0x313ce567", 2)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 2, decimals))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, balanceOf, 3)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 3, balance))),
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_call.json", contract, balanceOf, 4)), MustLoad(fb.LoadFixture("ethereum/res/eth_call.json", 4, balance))),
+			)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.HttpTest.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ec := EthereumClient{
+				AssetID: "TOKENS-TEST",
+				Client:  client,
+			}
+
+			expected := PointTo(MatchAllFields(Fields{
+				"Data": MatchAllFields(Fields{
+					"Token": MatchAllFields(Fields{
+						"Contract":   Equal("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+						"Symbol":     Equal("USDT"),
+						"Decimals":   Equal(6),
+						"Balance":    Equal("150.8406"),
+						"RawBalance": Equal("150840600"),
+					}),
+				}),
+			}))
+
+			res, err := ec.GetTokenBalance(contract, addr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(expected)
+
+			res, err = ec.GetTokenBalance(contract, addr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(expected)
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+const (
+	// TraceModeDebug traces transactions with geth's debug_traceTransaction and its built in callTracer.
+	TraceModeDebug = "debug"
+	// TraceModeParity traces transactions with trace_transaction, served by OpenEthereum, Erigon and Nethermind.
+	TraceModeParity = "parity"
+)
+
+// callFrame is a call made within a transaction, as returned by the callTracer.
+type callFrame struct {
+	Type  string         `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+	Error string         `json:"error"`
+	Calls []callFrame    `json:"calls"`
+}
+
+// parityTrace is a single action within a transaction, as returned by trace_transaction.
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType      string         `json:"callType"`
+		From          common.Address `json:"from"`
+		To            common.Address `json:"to"`
+		Value         *hexutil.Big   `json:"value"`
+		Address       common.Address `json:"address"`
+		RefundAddress common.Address `json:"refundAddress"`
+		Balance       *hexutil.Big   `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		Address common.Address `json:"address"`
+	} `json:"result"`
+	TraceAddress []int  `json:"traceAddress"`
+	Error        string `json:"error"`
+}
+
+// validTraceMode checks the trace mode configured for a client, an empty mode disabling tracing.
+func validTraceMode(mode string) error {
+	switch mode {
+	case "", TraceModeDebug, TraceModeParity:
+		return nil
+	default:
+		return fmt.Errorf("unknown trace mode: %s, expected %s or %s", mode, TraceModeDebug, TraceModeParity)
+	}
+}
+
+// internalTransfers traces the transaction with the client's trace mode, returning the value moved by the calls,
+// contract creations and self destructs it made. The top level call is left out as it is the transaction itself,
+// as are calls which reverted, along with every call they made.
+func (e EthereumClient) internalTransfers(ctx context.Context, hash common.Hash) ([]transport.Transfer, error) {
+	switch e.TraceMode {
+	case TraceModeDebug:
+		var frame callFrame
+		if err := e.RPC.CallContext(ctx, &frame, "debug_traceTransaction", hash, map[string]string{"tracer": "callTracer"}); err != nil {
+			return nil, errors.Wrap(err, "error tracing transaction")
+		}
+
+		var transfers []transport.Transfer
+		for _, call := range frame.Calls {
+			transfers = append(transfers, callFrameTransfers(call)...)
+		}
+
+		return transfers, nil
+	case TraceModeParity:
+		var traces []parityTrace
+		if err := e.RPC.CallContext(ctx, &traces, "trace_transaction", hash); err != nil {
+			return nil, errors.Wrap(err, "error tracing transaction")
+		}
+
+		return parityTransfers(traces), nil
+	default:
+		return nil, nil
+	}
+}
+
+// callFrameTransfers returns the transfers made by the frame and the calls beneath it.
+func callFrameTransfers(frame callFrame) []transport.Transfer {
+	if frame.Error != "" {
+		return nil
+	}
+
+	var transfers []transport.Transfer
+
+	// delegate and static calls run in the context of the caller, so never move value themselves.
+	switch strings.ToUpper(frame.Type) {
+	case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		if value := traceValue(frame.Value); value.Sign() > 0 {
+			transfers = append(transfers, transport.Transfer{
+				From:  frame.From.String(),
+				To:    frame.To.String(),
+				Value: value.String(),
+			})
+		}
+	}
+
+	for _, call := range frame.Calls {
+		transfers = append(transfers, callFrameTransfers(call)...)
+	}
+
+	return transfers
+}
+
+// parityTransfers returns the transfers made by the traces, which are listed depth first.
+func parityTransfers(traces []parityTrace) []transport.Transfer {
+	var (
+		transfers []transport.Transfer
+		failed    [][]int
+	)
+
+	for _, t := range traces {
+		if len(t.TraceAddress) == 0 || underFailedTrace(t.TraceAddress, failed) {
+			continue
+		}
+
+		if t.Error != "" {
+			failed = append(failed, t.TraceAddress)
+			continue
+		}
+
+		var transfer transport.Transfer
+		switch t.Type {
+		case "call":
+			if t.Action.CallType != "call" {
+				continue
+			}
+
+			transfer = transport.Transfer{From: t.Action.From.String(), To: t.Action.To.String(), Value: traceValue(t.Action.Value).String()}
+		case "create":
+			if t.Result == nil {
+				continue
+			}
+
+			transfer = transport.Transfer{From: t.Action.From.String(), To: t.Result.Address.String(), Value: traceValue(t.Action.Value).String()}
+		case "suicide":
+			transfer = transport.Transfer{From: t.Action.Address.String(), To: t.Action.RefundAddress.String(), Value: traceValue(t.Action.Balance).String()}
+		default:
+			continue
+		}
+
+		if transfer.Value != "0" {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers
+}
+
+// underFailedTrace reports whether the trace at address was made by one of the failed traces.
+func underFailedTrace(address []int, failed [][]int) bool {
+	for _, f := range failed {
+		if len(f) > len(address) {
+			continue
+		}
+
+		prefix := true
+		for i := range f {
+			if f[i] != address[i] {
+				prefix = false
+				break
+			}
+		}
+
+		if prefix {
+			return true
+		}
+	}
+
+	return false
+}
+
+func traceValue(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+
+	return v.ToInt()
+}
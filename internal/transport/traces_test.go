@@ -0,0 +1,118 @@
+package transport_test
+
+import (
+	"os"
+	"path"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	"github.com/hugorut/coins-oracle/pkg/test"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Traces", func() {
+	var (
+		fb *test.FixtureBox
+	)
+
+	hash := "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	blockHash := "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"
+	batch := "0xF02c1c8e6114b1Dbe8937a39260b5b0a374432bB"
+
+	// the value paid out by the batch contract, leaving out the delegate call and the call which reverted.
+	internal := []transport.Transfer{
+		{From: batch, To: "0x1111111111111111111111111111111111111111", Value: "1000"},
+		{From: batch, To: "0x2222222222222222222222222222222222222222", Value: "2000"},
+		{From: batch, To: "0x5555555555555555555555555555555555555555", Value: "300"},
+		{From: "0x5555555555555555555555555555555555555555", To: "0x1111111111111111111111111111111111111111", Value: "300"},
+	}
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+
+		fb = &test.FixtureBox{
+			Base: path.Join(dir, "../test/fixtures"),
+		}
+	})
+
+	tracedServer := func(receipt string, trace ...test.ExpectedCall) *test.Server {
+		calls := []test.ExpectedCall{
+			test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "latest", 1)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+			test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionByHash.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/eth_getTransactionByHash.json", hash))),
+			test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_chainId.json")), MustLoad(fb.LoadFixture("ethereum/res/eth_chainId.json"))),
+			test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getTransactionReceipt.json", hash)), MustLoad(fb.LoadFixture(receipt, hash, blockHash))),
+			test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/eth_getBlockByNumber.json", "0xb", 5)), MustLoad(fb.LoadFixture("ethereum/res/eth_getBlockByNumber.json"))),
+		}
+
+		return test.NewTestServer(GinkgoT(), append(calls, trace...)...)
+	}
+
+	tracedClient := func(server *test.Server, mode string) EthereumClient {
+		rpcClient, err := rpc.Dial(server.HttpTest.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		return EthereumClient{
+			Client:    ethclient.NewClient(rpcClient),
+			RPC:       rpcClient,
+			TraceMode: mode,
+		}
+	}
+
+	Describe("#GetTransactionByHash with a debug trace mode", func() {
+		It("Should return the value moved by the calls traced by the callTracer", func() {
+			server := tracedServer("ethereum/res/eth_getTransactionReceipt.json",
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/debug_traceTransaction.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/debug_traceTransaction.json"))),
+			)
+			defer server.Close()
+
+			tran, err := tracedClient(server, TraceModeDebug).GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.To).To(Equal(batch))
+			Expect(tran.Data.Transaction.Value).To(Equal("4290000000000000"))
+			Expect(tran.Data.Transaction.Transfers).To(Equal(internal))
+		})
+	})
+
+	Describe("#GetTransactionByHash with a parity trace mode", func() {
+		It("Should return the value moved by the calls listed by trace_transaction", func() {
+			server := tracedServer("ethereum/res/eth_getTransactionReceipt.json",
+				test.ExpectRPCJsonSuccess(MustLoad(fb.LoadFixture("ethereum/req/trace_transaction.json", hash)), MustLoad(fb.LoadFixture("ethereum/res/trace_transaction.json", hash, blockHash))),
+			)
+			defer server.Close()
+
+			tran, err := tracedClient(server, TraceModeParity).GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.Transfers).To(Equal(internal))
+		})
+	})
+
+	Describe("#GetTransactionByHash with a reverted transaction", func() {
+		It("Should not trace the transaction as it moved no value", func() {
+			server := tracedServer("ethereum/res/eth_getTransactionReceipt_reverted.json")
+			defer server.Close()
+
+			tran, err := tracedClient(server, TraceModeDebug).GetTransactionByHash(hash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tran.Data.Transaction.Reverted).To(BeTrue())
+			Expect(tran.Data.Transaction.Transfers).To(BeEmpty())
+		})
+	})
+
+	Describe("#NewEthereumClient", func() {
+		It("Should reject an unknown trace mode", func() {
+			os.Setenv("ETHEREUM_TRACE_MODE", "geth")
+			defer os.Unsetenv("ETHEREUM_TRACE_MODE")
+
+			_, err := NewEthereumClient()
+			Expect(err).To(MatchError("unknown trace mode: geth, expected debug or parity"))
+		})
+	})
+})
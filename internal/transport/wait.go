@@ -0,0 +1,132 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+const (
+	// TxStatePending means the transaction had not reached the requested confirmations before the wait ended.
+	TxStatePending = "pending"
+	// TxStateConfirmed means the transaction reached the requested confirmations.
+	TxStateConfirmed = "confirmed"
+	// TxStateDropped means the transaction was seen unconfirmed and then disappeared, e.g. evicted from the mempool.
+	TxStateDropped = "dropped"
+	// TxStateReorged means the transaction lost confirmations, i.e. its block was removed from the chain.
+	TxStateReorged = "reorged"
+)
+
+var (
+	// WaitPollInterval is how often a transaction is fetched while waiting for it to confirm.
+	WaitPollInterval = 2 * time.Second
+	// DroppedAfter is the number of consecutive failed lookups of a previously seen transaction before it is
+	// considered gone, so that a single node error isn't reported as a dropped transaction.
+	DroppedAfter = 3
+)
+
+// TransactionWait holds the outcome of waiting for a transaction, the transaction is the last version seen.
+type TransactionWait struct {
+	State       string                 `json:"state"`
+	Transaction *transport.Transaction `json:"transaction"`
+}
+
+// WaitForTransaction polls the client until the transaction has at least the given confirmations, is dropped or
+// reorged, or the context is done. A transaction which is never found is reported as pending.
+func WaitForTransaction(ctx context.Context, client transport.CoinClient, hash string, confirmations int64) *TransactionWait {
+	res := &TransactionWait{State: TxStatePending}
+
+	ticker := time.NewTicker(WaitPollInterval)
+	defer ticker.Stop()
+
+	var (
+		seen     bool
+		failures int
+		deepest  int64
+	)
+
+	for {
+		tr, err := lookupTransaction(ctx, client, hash)
+		if ctx.Err() != nil {
+			return res
+		}
+
+		if err != nil {
+			failures++
+			if seen && failures >= DroppedAfter {
+				res.State = TxStateDropped
+				if deepest > 0 {
+					res.State = TxStateReorged
+				}
+
+				return res
+			}
+		} else {
+			tx := tr.Data.Transaction
+			res.Transaction = &tx
+
+			seen = true
+			failures = 0
+
+			depth := confirmationsOf(tx)
+			if depth < deepest {
+				res.State = TxStateReorged
+				return res
+			}
+
+			deepest = depth
+
+			if depth >= confirmations || (tx.Confirmations.Value == nil && tx.Confirmations.Confirmed) {
+				res.State = TxStateConfirmed
+				return res
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return res
+		case <-ticker.C:
+		}
+	}
+}
+
+// lookupTransaction fetches the transaction unless the context is done. Clients can't cancel a lookup, so it is
+// raced against the context to return by its deadline however slow the node is.
+func lookupTransaction(ctx context.Context, client transport.CoinClient, hash string) (*transport.TransactionResp, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type lookup struct {
+		tr  *transport.TransactionResp
+		err error
+	}
+
+	found := make(chan lookup, 1)
+	go func() {
+		tr, err := client.GetTransactionByHash(hash)
+		found <- lookup{tr, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l := <-found:
+		return l.tr, l.err
+	}
+}
+
+// confirmationsOf returns the number of confirmations of the transaction. Clients which only report
+// whether the transaction is confirmed are treated as having a single confirmation once confirmed.
+func confirmationsOf(tx transport.Transaction) int64 {
+	if tx.Confirmations.Value != nil {
+		return *tx.Confirmations.Value
+	}
+
+	if tx.Confirmations.Confirmed {
+		return 1
+	}
+
+	return 0
+}
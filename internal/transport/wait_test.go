@@ -0,0 +1,118 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/transport"
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+func txWithConfirmations(confirmations int64) *transport.TransactionResp {
+	tx := &transport.TransactionResp{}
+	tx.Data.Transaction = transport.Transaction{
+		ID: "hash",
+		Confirmations: transport.Confirmations{
+			Threshold: transport.ConfirmThresholdValue,
+			Confirmed: confirmations >= *transport.ConfirmThresholdValue,
+			Value:     transport.NewInt64(confirmations),
+		},
+	}
+
+	return tx
+}
+
+var _ = Describe("WaitForTransaction", func() {
+	var (
+		ctrl     *gomock.Controller
+		client   *mock_transport.MockCoinClient
+		interval time.Duration
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mock_transport.NewMockCoinClient(ctrl)
+
+		interval = WaitPollInterval
+		WaitPollInterval = time.Millisecond
+	})
+
+	AfterEach(func() {
+		WaitPollInterval = interval
+		ctrl.Finish()
+	})
+
+	It("Should return once the transaction reaches the requested confirmations", func() {
+		gomock.InOrder(
+			client.EXPECT().GetTransactionByHash("hash").Return(nil, errors.New("not found")),
+			client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(0), nil),
+			client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(2), nil),
+		)
+
+		res := WaitForTransaction(context.Background(), client, "hash", 2)
+		Expect(res.State).To(Equal(TxStateConfirmed))
+		Expect(*res.Transaction.Confirmations.Value).To(Equal(int64(2)))
+	})
+
+	It("Should return pending with the last seen transaction when the context is done", func() {
+		client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(1), nil).AnyTimes()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		res := WaitForTransaction(ctx, client, "hash", 6)
+		Expect(res.State).To(Equal(TxStatePending))
+		Expect(res.Transaction.ID).To(Equal("hash"))
+	})
+
+	It("Should not look the transaction up once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res := WaitForTransaction(ctx, client, "hash", 1)
+		Expect(res.State).To(Equal(TxStatePending))
+		Expect(res.Transaction).To(BeNil())
+	})
+
+	It("Should return by the deadline when a lookup is slow", func() {
+		client.EXPECT().GetTransactionByHash("hash").DoAndReturn(func(string) (*transport.TransactionResp, error) {
+			time.Sleep(500 * time.Millisecond)
+			return txWithConfirmations(6), nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		res := WaitForTransaction(ctx, client, "hash", 1)
+		Expect(time.Since(start)).To(BeNumerically("<", 250*time.Millisecond))
+		Expect(res.State).To(Equal(TxStatePending))
+	})
+
+	It("Should return dropped when an unconfirmed transaction disappears", func() {
+		gomock.InOrder(
+			client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(0), nil),
+			client.EXPECT().GetTransactionByHash("hash").Return(nil, errors.New("not found")).Times(DroppedAfter),
+		)
+
+		res := WaitForTransaction(context.Background(), client, "hash", 1)
+		Expect(res.State).To(Equal(TxStateDropped))
+	})
+
+	It("Should return reorged when the transaction loses confirmations", func() {
+		gomock.InOrder(
+			client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(2), nil),
+			client.EXPECT().GetTransactionByHash("hash").Return(txWithConfirmations(0), nil),
+		)
+
+		res := WaitForTransaction(context.Background(), client, "hash", 6)
+		Expect(res.State).To(Equal(TxStateReorged))
+		Expect(*res.Transaction.Confirmations.Value).To(Equal(int64(0)))
+	})
+})
@@ -0,0 +1,389 @@
+package transport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// hdVersion describes the addresses of the extended public keys serialized with a version.
+type hdVersion struct {
+	script string
+	test   bool
+}
+
+var (
+	// hdPublicVersions maps the versions of extended public keys to the script of the addresses derived from them,
+	// ypub and zpub keys, and their testnet equivalents, being serialized with the version of their script.
+	hdPublicVersions = map[[4]byte]hdVersion{
+		{0x04, 0x88, 0xb2, 0x1e}: {script: "p2pkh"},                   // xpub
+		{0x04, 0x9d, 0x7c, 0xb2}: {script: "p2sh-p2wpkh"},             // ypub
+		{0x04, 0xb2, 0x47, 0x46}: {script: "p2wpkh"},                  // zpub
+		{0x04, 0x35, 0x87, 0xcf}: {script: "p2pkh", test: true},       // tpub
+		{0x04, 0x4a, 0x52, 0x62}: {script: "p2sh-p2wpkh", test: true}, // upub
+		{0x04, 0x5f, 0x1c, 0xf6}: {script: "p2wpkh", test: true},      // vpub
+		{0x01, 0x9d, 0xa4, 0x62}: {script: "p2pkh"},                   // Ltub
+		{0x01, 0xb2, 0x6e, 0xf6}: {script: "p2sh-p2wpkh"},             // Mtub
+		{0x02, 0xfa, 0xca, 0xfd}: {script: "p2pkh"},                   // dgub
+	}
+
+	// hdPurposes maps scripts to the purpose of the BIP their account keys are derived by.
+	hdPurposes = map[string]uint32{
+		"p2pkh":       44,
+		"p2sh-p2wpkh": 49,
+		"p2wpkh":      84,
+	}
+)
+
+// btcXPub is an extended public key along with how the addresses of its wallet are derived.
+type btcXPub struct {
+	key    *hdkeychain.ExtendedKey
+	script string
+	// path is the derivation path of the key, m/purpose'/coin'/account', if it's a BIP44, 49 or 84 account key.
+	path string
+	test bool
+}
+
+// parseXPub decodes the extended public key, the script of its addresses being read from its version.
+func parseXPub(net *BTCNetwork, xpub string) (*btcXPub, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding xpub")
+	}
+
+	if key.IsPrivate() {
+		return nil, errors.New("xpub must be an extended public key")
+	}
+
+	// the key decoded, so the payload is known to hold the version, depth, parent fingerprint and child number.
+	payload := base58.Decode(xpub)
+
+	var version [4]byte
+	copy(version[:], payload[:4])
+
+	v, ok := hdPublicVersions[version]
+	if !ok {
+		return nil, errors.Errorf("unknown xpub version: %x", version)
+	}
+
+	test := net.Params.HDPublicKeyID == chaincfg.TestNet3Params.HDPublicKeyID
+	if v.test != test {
+		return nil, errors.Errorf("xpub is not for the %s network", net.Params.Name)
+	}
+
+	x := &btcXPub{key: key, script: v.script, test: v.test}
+
+	if childNum := binary.BigEndian.Uint32(payload[9:13]); key.Depth() == 3 && childNum >= hdkeychain.HardenedKeyStart {
+		x.path = fmt.Sprintf("m/%d'/%d'/%d'", hdPurposes[v.script], net.Params.HDCoinType, childNum-hdkeychain.HardenedKeyStart)
+	}
+
+	return x, nil
+}
+
+// descriptorKey returns the key serialized with the xpub or tpub version, the only ones descriptors accept.
+func (x btcXPub) descriptorKey() string {
+	net := &chaincfg.MainNetParams
+	if x.test {
+		net = &chaincfg.TestNet3Params
+	}
+
+	key := *x.key
+	key.SetNet(net)
+
+	return key.String()
+}
+
+// address derives the address at the index of the receive, or change, chain of the key.
+func (x btcXPub) address(net *BTCNetwork, change bool, index uint32) (transport.XPubAddress, error) {
+	chain := uint32(0)
+	if change {
+		chain = 1
+	}
+
+	path := fmt.Sprintf("%d/%d", chain, index)
+	if x.path != "" {
+		path = x.path + "/" + path
+	}
+
+	chainKey, err := x.key.Child(chain)
+	if err != nil {
+		return transport.XPubAddress{}, errors.Wrapf(err, "error deriving key: %s", path)
+	}
+
+	key, err := chainKey.Child(index)
+	if err != nil {
+		return transport.XPubAddress{}, errors.Wrapf(err, "error deriving key: %s", path)
+	}
+
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return transport.XPubAddress{}, errors.Wrapf(err, "error deriving key: %s", path)
+	}
+
+	hash := btcutil.Hash160(pub.SerializeCompressed())
+
+	var addr btcutil.Address
+	switch x.script {
+	case "p2pkh":
+		addr, err = btcutil.NewAddressPubKeyHash(hash, net.Params)
+	case "p2sh-p2wpkh":
+		addr, err = btcutil.NewAddressScriptHash(append([]byte{0x00, 0x14}, hash...), net.Params)
+	case "p2wpkh":
+		if net.Params.Bech32HRPSegwit == "" {
+			return transport.XPubAddress{}, errors.Errorf("%s has no segwit addresses", net.Params.Name)
+		}
+
+		addr, err = btcutil.NewAddressWitnessPubKeyHash(hash, net.Params)
+	default:
+		return transport.XPubAddress{}, errors.Errorf("%s addresses can't be derived", x.script)
+	}
+
+	if err != nil {
+		return transport.XPubAddress{}, errors.Wrapf(err, "error encoding address: %s", path)
+	}
+
+	return transport.XPubAddress{
+		Address: addr.EncodeAddress(),
+		Path:    path,
+		Change:  change,
+		Index:   index,
+	}, nil
+}
+
+// btcReceived represents an address in the result of a listreceivedbyaddress call.
+type btcReceived struct {
+	Address string   `json:"address"`
+	TxIDs   []string `json:"txids"`
+}
+
+// xpubOutput is an output paid to one of the addresses of an xpub.
+type xpubOutput struct {
+	address string
+	amount  btcutil.Amount
+}
+
+// ListXPubAddresses derives the receive and change addresses of the xpub until gap consecutive addresses haven't
+// received funds. The node only knows about addresses it has imported, see StartImport, so no more than the
+// first 1000 of each chain are derived.
+func (b BitcoinClient) ListXPubAddresses(xpub string, gap int) (*transport.XPubAddressesResp, error) {
+	x, err := parseXPub(b.network(), xpub)
+	if err != nil {
+		return nil, err
+	}
+
+	if gap < 1 {
+		return nil, errors.New("gap must be positive")
+	}
+
+	received, err := b.listReceived()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &transport.XPubAddressesResp{}
+	res.Data.Script = x.script
+	res.Data.Addresses = []transport.XPubAddress{}
+
+	for _, change := range []bool{false, true} {
+		unused := 0
+		for index := uint32(0); unused < gap && index < btcImportRange; index++ {
+			addr, err := x.address(b.network(), change, index)
+			if err != nil {
+				return nil, err
+			}
+
+			_, addr.Used = received[addr.Address]
+			if addr.Used {
+				unused = 0
+			} else {
+				unused++
+			}
+
+			res.Data.Addresses = append(res.Data.Addresses, addr)
+		}
+	}
+
+	return res, nil
+}
+
+// GetXPubBalance sums the balances of the used addresses of the xpub, see ListXPubAddresses.
+func (b BitcoinClient) GetXPubBalance(xpub string, gap int) (*transport.Balance, error) {
+	addrs, err := b.usedXPubAddresses(xpub, gap)
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmed, unconfirmed btcutil.Amount
+	if len(addrs) > 0 {
+		totals, err := b.unspentTotals(addrs, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, total := range totals {
+			confirmed += total.confirmed
+			unconfirmed += total.unconfirmed
+		}
+	}
+
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{
+				{
+					Asset:       b.AssetID,
+					Balance:     fmt.Sprintf("%f", confirmed.ToBTC()),
+					Confirmed:   fmt.Sprintf("%f", confirmed.ToBTC()),
+					Unconfirmed: fmt.Sprintf("%f", unconfirmed.ToBTC()),
+				},
+			},
+		},
+	}, nil
+}
+
+// ListXPubTransactions lists the transactions which moved funds to or from the used addresses of the xpub, see
+// ListXPubAddresses. Each is listed once with its value netted across the addresses, negative when the xpub spent
+// more than it received back as change, and transfers between the xpub's own addresses are left out.
+func (b BitcoinClient) ListXPubTransactions(xpub string, gap int) (*transport.TransactionsResp, error) {
+	addrs, err := b.usedXPubAddresses(xpub, gap)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &transport.TransactionsResp{}
+	res.Data.Transactions = []transport.Transaction{}
+
+	if len(addrs) == 0 {
+		return res, nil
+	}
+
+	owned := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		owned[addr] = true
+	}
+
+	since, err := b.listSinceBlock("", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// the wallet lists an entry for each output paid to its addresses and each output of the transactions spending
+	// from them, so the transactions with either are all those which could move the xpub's funds.
+	var txids []string
+	listed := map[string]bool{}
+	for _, e := range since.Transactions {
+		if listed[e.TxID] || e.Confirmations < 0 {
+			continue
+		}
+
+		if e.Category == "send" || (btcReceiveCategory(e.Category) && owned[e.Address]) {
+			listed[e.TxID] = true
+			txids = append(txids, e.TxID)
+		}
+	}
+
+	txs := make([]*btcRawTransaction, len(txids))
+	outputs := map[string]xpubOutput{}
+
+	for i, id := range txids {
+		tx, err := b.getTransaction(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range tx.Vout {
+			out := btcOutput(v)
+			if !owned[out.Address] {
+				continue
+			}
+
+			// NewAmount rounds to the nearest satoshi, avoiding float truncation errors.
+			amount, err := btcutil.NewAmount(v.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error converting amount of transaction: %s", id)
+			}
+
+			outputs[xpubOutpoint(id, uint32(v.N))] = xpubOutput{address: out.Address, amount: amount}
+		}
+
+		txs[i] = tx
+	}
+
+	// every output the xpub's addresses were paid is in the transactions listed, so the inputs which aren't
+	// among them spent the funds of others.
+	for _, tx := range txs {
+		var received, spent btcutil.Amount
+
+		moved := transport.Transaction{
+			ID:            tx.Txid,
+			Confirmations: btcConfirmations(tx.Confirmations),
+		}
+		internal := true
+
+		for _, in := range tx.Vin {
+			prev, ok := outputs[xpubOutpoint(in.Txid, in.Vout)]
+			if !ok {
+				internal = false
+				continue
+			}
+
+			spent += prev.amount
+			if moved.From == "" {
+				moved.From = prev.address
+			}
+		}
+
+		for _, v := range tx.Vout {
+			out, ok := outputs[xpubOutpoint(tx.Txid, uint32(v.N))]
+			if !ok {
+				internal = false
+				if spent > 0 && moved.To == "" {
+					moved.To = btcOutput(v).Address
+				}
+
+				continue
+			}
+
+			received += out.amount
+			if spent == 0 && moved.To == "" {
+				moved.To = out.address
+			}
+		}
+
+		if internal || (received == 0 && spent == 0) {
+			continue
+		}
+
+		moved.Value = fmt.Sprintf("%f", (received - spent).ToBTC())
+		res.Data.Transactions = append(res.Data.Transactions, moved)
+	}
+
+	return res, nil
+}
+
+func xpubOutpoint(txid string, vout uint32) string {
+	return txid + ":" + strconv.FormatUint(uint64(vout), 10)
+}
+
+func (b BitcoinClient) usedXPubAddresses(xpub string, gap int) ([]string, error) {
+	res, err := b.ListXPubAddresses(xpub, gap)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, addr := range res.Data.Addresses {
+		if addr.Used {
+			addrs = append(addrs, addr.Address)
+		}
+	}
+
+	return addrs, nil
+}
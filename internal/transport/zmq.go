@@ -0,0 +1,163 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	zmqFlagMore    = 0x01
+	zmqFlagLong    = 0x02
+	zmqFlagCommand = 0x04
+)
+
+// zmqSubscriber is a minimal ZMTP 3.0 SUB socket using the NULL security mechanism.
+// It implements just enough of the protocol to receive the notifications published by bitcoind's zmq interface,
+// which saves pulling in libzmq for a single socket type.
+type zmqSubscriber struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialZMQ connects to the zmq publisher at addr, e.g. tcp://127.0.0.1:28332, and subscribes to the given topics.
+func dialZMQ(ctx context.Context, addr string, topics ...string) (*zmqSubscriber, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", strings.TrimPrefix(addr, "tcp://"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to zmq publisher: %s", addr)
+	}
+
+	z := &zmqSubscriber{conn: conn, r: bufio.NewReader(conn)}
+	if err := z.handshake(topics); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return z, nil
+}
+
+func (z *zmqSubscriber) handshake(topics []string) error {
+	greeting := make([]byte, 64)
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3
+	copy(greeting[12:32], "NULL")
+
+	if _, err := z.conn.Write(greeting); err != nil {
+		return errors.Wrap(err, "error sending zmq greeting")
+	}
+
+	peer := make([]byte, 64)
+	if _, err := io.ReadFull(z.r, peer); err != nil {
+		return errors.Wrap(err, "error reading zmq greeting")
+	}
+
+	if peer[0] != 0xff || peer[9] != 0x7f || peer[10] < 3 {
+		return errors.New("zmq peer does not support ZMTP 3")
+	}
+
+	ready := []byte{5}
+	ready = append(ready, "READY"...)
+	ready = append(ready, byte(len("Socket-Type")))
+	ready = append(ready, "Socket-Type"...)
+	ready = append(ready, 0, 0, 0, 3)
+	ready = append(ready, "SUB"...)
+
+	if err := z.writeFrame(zmqFlagCommand, ready); err != nil {
+		return errors.Wrap(err, "error sending zmq ready command")
+	}
+
+	flags, body, err := z.readFrame()
+	if err != nil {
+		return errors.Wrap(err, "error reading zmq ready command")
+	}
+
+	if flags&zmqFlagCommand == 0 || len(body) < 6 || string(body[1:6]) != "READY" {
+		return errors.New("zmq peer did not send a ready command")
+	}
+
+	// ZMTP 3.0 subscriptions are sent as a message starting with 0x01 followed by the topic.
+	for _, topic := range topics {
+		if err := z.writeFrame(0, append([]byte{1}, topic...)); err != nil {
+			return errors.Wrapf(err, "error subscribing to zmq topic: %s", topic)
+		}
+	}
+
+	return nil
+}
+
+// ReadMessage blocks until the next multipart message is received, skipping any commands.
+func (z *zmqSubscriber) ReadMessage() ([][]byte, error) {
+	var parts [][]byte
+	for {
+		flags, body, err := z.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		if flags&zmqFlagCommand != 0 {
+			continue
+		}
+
+		parts = append(parts, body)
+		if flags&zmqFlagMore == 0 {
+			return parts, nil
+		}
+	}
+}
+
+// Close closes the underlying connection, unblocking any pending ReadMessage.
+func (z *zmqSubscriber) Close() error {
+	return z.conn.Close()
+}
+
+func (z *zmqSubscriber) writeFrame(flags byte, body []byte) error {
+	var header []byte
+	if len(body) > 255 {
+		header = make([]byte, 9)
+		header[0] = flags | zmqFlagLong
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+
+	_, err := z.conn.Write(append(header, body...))
+	return err
+}
+
+func (z *zmqSubscriber) readFrame() (byte, []byte, error) {
+	flags, err := z.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var size uint64
+	if flags&zmqFlagLong != 0 {
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(z.r, b); err != nil {
+			return 0, nil, err
+		}
+
+		size = binary.BigEndian.Uint64(b)
+	} else {
+		b, err := z.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		size = uint64(b)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(z.r, body); err != nil {
+		return 0, nil, err
+	}
+
+	return flags, body, nil
+}
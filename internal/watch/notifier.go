@@ -0,0 +1,122 @@
+package watch
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body, keyed with the watch secret.
+	SignatureHeader = "X-Coins-Oracle-Signature"
+	// EventHeader holds the type of the event being delivered.
+	EventHeader = "X-Coins-Oracle-Event"
+)
+
+var (
+	// DefaultMaxAttempts is the number of times delivery of an event is attempted before it is dead lettered.
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the wait before the first retry, it doubles after every failed attempt.
+	DefaultBackoff = time.Second
+)
+
+// Sender delivers an event to a watch's callback url.
+type Sender interface {
+	Send(w *Watch, e Event) error
+}
+
+// DeliveryError is returned by a Notifier once all attempts to deliver an event have failed.
+type DeliveryError struct {
+	Attempts int
+	Err      error
+}
+
+func (d DeliveryError) Error() string {
+	return fmt.Sprintf("event not delivered after %d attempts: %s", d.Attempts, d.Err)
+}
+
+// Notifier POSTs signed events to watch callback urls, retrying with an exponential backoff.
+type Notifier struct {
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// NewNotifier returns a Notifier using the default retry settings.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+// Sign returns the signature for body sent with SignatureHeader, receivers should
+// compute the same value with the secret returned when the watch was created.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers the event to the watch's callback url. Any non 2xx response is treated as a failure.
+func (n Notifier) Send(w *Watch, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "error encoding event")
+	}
+
+	attempts := n.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	backoff := n.Backoff
+	for i := 1; ; i++ {
+		err = n.post(w, e, body)
+		if err == nil {
+			return nil
+		}
+
+		if i >= attempts {
+			return DeliveryError{Attempts: i, Err: err}
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (n Notifier) post(w *Watch, e Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, e.Type)
+	req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+
+	res, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("callback returned status: %d", res.StatusCode)
+	}
+
+	return nil
+}
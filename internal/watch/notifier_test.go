@@ -0,0 +1,72 @@
+package watch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/watch"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		server   *httptest.Server
+		notifier *Notifier
+		calls    int
+		status   []int
+		bodies   [][]byte
+		headers  []http.Header
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		bodies = nil
+		headers = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, body)
+			headers = append(headers, r.Header)
+
+			w.WriteHeader(status[calls])
+			calls++
+		}))
+
+		notifier = &Notifier{Client: server.Client(), MaxAttempts: 3}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should POST the event signed with the watch secret", func() {
+		status = []int{http.StatusOK}
+		w := &Watch{ID: "watch", CallbackURL: server.URL, Secret: "secret"}
+
+		Expect(notifier.Send(w, Event{ID: "event", Type: EventTransactionReceived})).To(Succeed())
+
+		Expect(calls).To(Equal(1))
+		Expect(headers[0].Get(EventHeader)).To(Equal(EventTransactionReceived))
+		Expect(headers[0].Get(SignatureHeader)).To(Equal(Sign("secret", bodies[0])))
+	})
+
+	It("Should retry failed deliveries", func() {
+		status = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent}
+		w := &Watch{ID: "watch", CallbackURL: server.URL, Secret: "secret"}
+
+		Expect(notifier.Send(w, Event{ID: "event"})).To(Succeed())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("Should return a DeliveryError once all attempts fail", func() {
+		status = []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}
+		w := &Watch{ID: "watch", CallbackURL: server.URL, Secret: "secret"}
+
+		err := notifier.Send(w, Event{ID: "event"})
+		Expect(err).To(MatchError("event not delivered after 3 attempts: callback returned status: 500"))
+		Expect(err.(DeliveryError).Attempts).To(Equal(3))
+	})
+})
@@ -0,0 +1,227 @@
+package watch
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+// ClientGetter returns the CoinClient registered for an asset id.
+type ClientGetter interface {
+	Get(name string) (transport.CoinClient, error)
+}
+
+// Poller periodically checks every watched address and notifies the watch callback of new activity.
+type Poller struct {
+	Store    Store
+	Clients  ClientGetter
+	Sender   Sender
+	Interval time.Duration
+	Logger   *log.Logger
+}
+
+// Run polls all watches every Interval until the context is cancelled.
+func (p Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.Poll()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every watch once, sending any new events and persisting the updated watch state.
+// Events which cannot be delivered are stored as dead letters.
+func (p Poller) Poll() {
+	watches, err := p.Store.List()
+	if err != nil {
+		p.logf("error listing watches: %s", err)
+		return
+	}
+
+	for _, w := range watches {
+		events, err := p.Check(w)
+		if err != nil {
+			p.logf("error checking watch: %s for asset: %s, err: %s", w.ID, w.AssetID, err)
+			continue
+		}
+
+		for _, e := range events {
+			err := p.Sender.Send(w, e)
+			if err == nil {
+				continue
+			}
+
+			p.logf("error sending event: %s for watch: %s, err: %s", e.ID, w.ID, err)
+
+			attempts := 1
+			if de, ok := err.(DeliveryError); ok {
+				attempts = de.Attempts
+			}
+
+			if err := p.Store.AddDeadLetter(DeadLetter{
+				Event:       e,
+				CallbackURL: w.CallbackURL,
+				Attempts:    attempts,
+				Error:       err.Error(),
+				FailedAt:    time.Now().UTC(),
+			}); err != nil {
+				p.logf("error storing dead letter for event: %s, err: %s", e.ID, err)
+			}
+		}
+
+		if err := p.Store.Update(w); err != nil && err != ErrNotFound {
+			p.logf("error updating watch: %s, err: %s", w.ID, err)
+		}
+	}
+}
+
+// Check returns the events for any new activity on the watched address and records it in the watch state.
+// Clients which implement transport.TransactionLister are checked for incoming transactions,
+// all other clients are checked for balance changes.
+func (p Poller) Check(w *Watch) ([]Event, error) {
+	client, err := p.Clients.Get(w.AssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.State.Seen == nil {
+		w.State.Seen = map[string]bool{}
+	}
+	if w.State.Confirmed == nil {
+		w.State.Confirmed = map[string]bool{}
+	}
+	if w.State.Balances == nil {
+		w.State.Balances = map[string]string{}
+	}
+
+	var events []Event
+	if lister, ok := client.(transport.TransactionLister); ok {
+		events, err = p.checkTransactions(w, lister)
+	} else {
+		events, err = p.checkBalance(w, client)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// activity which existed before the watch was first polled is recorded but not sent.
+	if !w.State.Synced {
+		w.State.Synced = true
+		return nil, nil
+	}
+
+	return events, nil
+}
+
+func (p Poller) checkTransactions(w *Watch, lister transport.TransactionLister) ([]Event, error) {
+	res, err := p.listTransactions(w, lister)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing transactions")
+	}
+
+	var events []Event
+	for _, tx := range res.Data.Transactions {
+		if !strings.EqualFold(tx.To, w.Addr) {
+			continue
+		}
+
+		tx := tx
+		if !w.State.Seen[tx.ID] {
+			w.State.Seen[tx.ID] = true
+			events = append(events, newEvent(w, EventTransactionReceived, func(e *Event) { e.Transaction = &tx }))
+		}
+
+		var confirmations int64
+		if tx.Confirmations.Value != nil {
+			confirmations = *tx.Confirmations.Value
+		}
+
+		if !w.State.Confirmed[tx.ID] && confirmations >= w.MinConfirmations {
+			w.State.Confirmed[tx.ID] = true
+			events = append(events, newEvent(w, EventTransactionConfirmed, func(e *Event) { e.Transaction = &tx }))
+		}
+	}
+
+	return events, nil
+}
+
+// listTransactions lists the transactions of the watched address. Clients which implement
+// transport.TransactionSinceLister only list those since the cursor of the last poll, which is kept in the watch state.
+func (p Poller) listTransactions(w *Watch, lister transport.TransactionLister) (*transport.TransactionsResp, error) {
+	since, ok := lister.(transport.TransactionSinceLister)
+	if !ok {
+		return lister.ListTransactions(w.Addr)
+	}
+
+	res, cursor, err := since.ListTransactionsSince(w.Addr, w.State.Cursor, w.MinConfirmations)
+	if err != nil {
+		return nil, err
+	}
+
+	w.State.Cursor = cursor
+
+	return res, nil
+}
+
+func (p Poller) checkBalance(w *Watch, client transport.CoinClient) ([]Event, error) {
+	b, err := client.GetBalance(w.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting balance")
+	}
+
+	var events []Event
+	for _, a := range b.Data.Assets {
+		prev, ok := w.State.Balances[a.Asset]
+		w.State.Balances[a.Asset] = a.Balance
+
+		if ok && prev == a.Balance {
+			continue
+		}
+
+		a := a
+		events = append(events, newEvent(w, EventBalanceChanged, func(e *Event) {
+			e.Asset = a.Asset
+			e.Balance = a.Balance
+			e.PreviousBalance = prev
+		}))
+	}
+
+	return events, nil
+}
+
+func (p Poller) logf(format string, v ...interface{}) {
+	if p.Logger == nil {
+		return
+	}
+
+	p.Logger.Printf(format, v...)
+}
+
+func newEvent(w *Watch, t string, fn func(e *Event)) Event {
+	id, _ := randomHex(16)
+
+	e := Event{
+		ID:        id,
+		Type:      t,
+		WatchID:   w.ID,
+		AssetID:   w.AssetID,
+		Addr:      w.Addr,
+		CreatedAt: time.Now().UTC(),
+	}
+	fn(&e)
+
+	return e
+}
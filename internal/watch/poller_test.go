@@ -0,0 +1,231 @@
+package watch_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	mock_transport "github.com/hugorut/coins-oracle/internal/transport/mocks"
+	. "github.com/hugorut/coins-oracle/internal/watch"
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+type clients map[string]transport.CoinClient
+
+func (c clients) Get(name string) (transport.CoinClient, error) {
+	if client, ok := c[name]; ok {
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("could not find client named: %s", name)
+}
+
+type listerClient struct {
+	*mock_transport.MockCoinClient
+	*mock_transport.MockTransactionLister
+}
+
+type sinceListerClient struct {
+	*mock_transport.MockCoinClient
+	*mock_transport.MockTransactionLister
+	*mock_transport.MockTransactionSinceLister
+}
+
+type sender struct {
+	events []Event
+	err    error
+}
+
+func (s *sender) Send(w *Watch, e Event) error {
+	s.events = append(s.events, e)
+	return s.err
+}
+
+func transactions(confirmations ...int64) *transport.TransactionsResp {
+	res := &transport.TransactionsResp{}
+	for i, c := range confirmations {
+		res.Data.Transactions = append(res.Data.Transactions, transport.Transaction{
+			ID:            fmt.Sprintf("tx%d", i),
+			To:            "addr",
+			Value:         "1.000000",
+			Confirmations: transport.Confirmations{Value: transport.NewInt64(c)},
+		})
+	}
+
+	return res
+}
+
+func balance(value string) *transport.Balance {
+	return &transport.Balance{
+		Data: transport.BalanceData{
+			Assets: []transport.Asset{{Asset: "TEST", Balance: value}},
+		},
+	}
+}
+
+var _ = Describe("Poller", func() {
+	var (
+		ctrl   *gomock.Controller
+		dir    string
+		store  *FileStore
+		s      *sender
+		poller Poller
+		w      *Watch
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		var err error
+		dir, err = ioutil.TempDir("", "watch")
+		Expect(err).ToNot(HaveOccurred())
+
+		store, err = NewFileStore(filepath.Join(dir, "watches.json"))
+		Expect(err).ToNot(HaveOccurred())
+
+		w, err = NewWatch("test", "addr", "http://localhost/callback", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Create(w)).To(Succeed())
+
+		s = &sender{}
+		poller = Poller{Store: store, Sender: s}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		os.RemoveAll(dir)
+	})
+
+	Context("When the client can list transactions", func() {
+		var client listerClient
+
+		BeforeEach(func() {
+			client = listerClient{
+				MockCoinClient:        mock_transport.NewMockCoinClient(ctrl),
+				MockTransactionLister: mock_transport.NewMockTransactionLister(ctrl),
+			}
+			poller.Clients = clients{"test": client}
+		})
+
+		It("Should notify new incoming transactions and when they are confirmed", func() {
+			gomock.InOrder(
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(10), nil),
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(11, 0), nil),
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(12, 2), nil),
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(13, 3), nil),
+			)
+
+			// the first poll records existing transactions without sending them.
+			poller.Poll()
+			Expect(s.events).To(BeEmpty())
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(1))
+			Expect(s.events[0].Type).To(Equal(EventTransactionReceived))
+			Expect(s.events[0].WatchID).To(Equal(w.ID))
+			Expect(s.events[0].Transaction.ID).To(Equal("tx1"))
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(2))
+			Expect(s.events[1].Type).To(Equal(EventTransactionConfirmed))
+			Expect(s.events[1].Transaction.ID).To(Equal("tx1"))
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(2))
+		})
+
+		It("Should store events which could not be delivered as dead letters", func() {
+			s.err = DeliveryError{Attempts: 5, Err: errors.New("callback returned status: 500")}
+
+			gomock.InOrder(
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(), nil),
+				client.MockTransactionLister.EXPECT().ListTransactions("addr").Return(transactions(0), nil),
+			)
+
+			poller.Poll()
+			poller.Poll()
+
+			letters, err := store.DeadLetters()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(letters).To(HaveLen(1))
+			Expect(letters[0].Attempts).To(Equal(5))
+			Expect(letters[0].CallbackURL).To(Equal("http://localhost/callback"))
+			Expect(letters[0].Event.Type).To(Equal(EventTransactionReceived))
+
+			stored, err := store.Get(w.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.State.Seen).To(HaveKey("tx0"))
+		})
+	})
+
+	Context("When the client can list transactions incrementally", func() {
+		It("Should only list the transactions since the cursor of the last poll", func() {
+			client := sinceListerClient{
+				MockCoinClient:             mock_transport.NewMockCoinClient(ctrl),
+				MockTransactionLister:      mock_transport.NewMockTransactionLister(ctrl),
+				MockTransactionSinceLister: mock_transport.NewMockTransactionSinceLister(ctrl),
+			}
+			poller.Clients = clients{"test": client}
+
+			// transactions are listed until they are confirmed, so the new transaction is listed twice.
+			received := func(confirmations int64) *transport.TransactionsResp {
+				res := transactions(confirmations)
+				res.Data.Transactions[0].ID = "tx1"
+				return res
+			}
+
+			gomock.InOrder(
+				client.MockTransactionSinceLister.EXPECT().ListTransactionsSince("addr", "", int64(2)).Return(transactions(10), "block1", nil),
+				client.MockTransactionSinceLister.EXPECT().ListTransactionsSince("addr", "block1", int64(2)).Return(received(0), "block2", nil),
+				client.MockTransactionSinceLister.EXPECT().ListTransactionsSince("addr", "block2", int64(2)).Return(received(2), "block3", nil),
+			)
+
+			poller.Poll()
+			Expect(s.events).To(BeEmpty())
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(1))
+			Expect(s.events[0].Type).To(Equal(EventTransactionReceived))
+			Expect(s.events[0].Transaction.ID).To(Equal("tx1"))
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(2))
+			Expect(s.events[1].Type).To(Equal(EventTransactionConfirmed))
+			Expect(s.events[1].Transaction.ID).To(Equal("tx1"))
+
+			stored, err := store.Get(w.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.State.Cursor).To(Equal("block3"))
+		})
+	})
+
+	Context("When the client can only fetch balances", func() {
+		It("Should notify balance changes", func() {
+			client := mock_transport.NewMockCoinClient(ctrl)
+			poller.Clients = clients{"test": client}
+
+			gomock.InOrder(
+				client.EXPECT().GetBalance("addr").Return(balance("1.5"), nil),
+				client.EXPECT().GetBalance("addr").Return(balance("1.5"), nil),
+				client.EXPECT().GetBalance("addr").Return(balance("2"), nil),
+			)
+
+			poller.Poll()
+			poller.Poll()
+			Expect(s.events).To(BeEmpty())
+
+			poller.Poll()
+			Expect(s.events).To(HaveLen(1))
+			Expect(s.events[0].Type).To(Equal(EventBalanceChanged))
+			Expect(s.events[0].Asset).To(Equal("TEST"))
+			Expect(s.events[0].Balance).To(Equal("2"))
+			Expect(s.events[0].PreviousBalance).To(Equal("1.5"))
+		})
+	})
+})
@@ -0,0 +1,212 @@
+package watch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Store persists watches and the dead letters of events which could not be delivered.
+type Store interface {
+	Create(w *Watch) error
+	Get(id string) (*Watch, error)
+	List() ([]*Watch, error)
+	Update(w *Watch) error
+	Delete(id string) error
+	AddDeadLetter(d DeadLetter) error
+	DeadLetters() ([]DeadLetter, error)
+}
+
+type fileData struct {
+	Watches     map[string]*Watch `json:"watches"`
+	DeadLetters []DeadLetter      `json:"deadLetters"`
+}
+
+// FileStore is a Store which keeps all watches in a single json file.
+// The file is re-read on every operation so that the api and poller processes can share it.
+type FileStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore at path, creating the file if it does not exist.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{Path: path}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := s.write(&fileData{Watches: map[string]*Watch{}}); err != nil {
+			return nil, errors.Wrapf(err, "error creating watch store at: %s", path)
+		}
+	}
+
+	if _, err := s.read(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Create adds a new watch to the store.
+func (s *FileStore) Create(w *Watch) error {
+	return s.update(func(d *fileData) error {
+		if _, ok := d.Watches[w.ID]; ok {
+			return errors.Errorf("watch with id: %s already exists", w.ID)
+		}
+
+		d.Watches[w.ID] = w
+		return nil
+	})
+}
+
+// Get returns the watch with the given id or ErrNotFound.
+func (s *FileStore) Get(id string) (*Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	w, ok := d.Watches[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return w, nil
+}
+
+// List returns all watches ordered by creation time.
+func (s *FileStore) List() ([]*Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Watch, 0, len(d.Watches))
+	for _, w := range d.Watches {
+		list = append(list, w)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID < list[j].ID
+		}
+
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+
+	return list, nil
+}
+
+// Update replaces an existing watch. Updating a watch which has since been deleted returns ErrNotFound.
+func (s *FileStore) Update(w *Watch) error {
+	return s.update(func(d *fileData) error {
+		if _, ok := d.Watches[w.ID]; !ok {
+			return ErrNotFound
+		}
+
+		d.Watches[w.ID] = w
+		return nil
+	})
+}
+
+// Delete removes the watch with the given id.
+func (s *FileStore) Delete(id string) error {
+	return s.update(func(d *fileData) error {
+		if _, ok := d.Watches[id]; !ok {
+			return ErrNotFound
+		}
+
+		delete(d.Watches, id)
+		return nil
+	})
+}
+
+// AddDeadLetter records an event which could not be delivered.
+func (s *FileStore) AddDeadLetter(dl DeadLetter) error {
+	return s.update(func(d *fileData) error {
+		d.DeadLetters = append(d.DeadLetters, dl)
+		return nil
+	})
+}
+
+// DeadLetters returns all events which could not be delivered.
+func (s *FileStore) DeadLetters() ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DeadLetters, nil
+}
+
+func (s *FileStore) update(fn func(d *fileData) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(d); err != nil {
+		return err
+	}
+
+	return s.write(d)
+}
+
+func (s *FileStore) read() (*fileData, error) {
+	raw, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading watch store at: %s", s.Path)
+	}
+
+	d := &fileData{}
+	if err := json.Unmarshal(raw, d); err != nil {
+		return nil, errors.Wrapf(err, "error decoding watch store at: %s", s.Path)
+	}
+
+	if d.Watches == nil {
+		d.Watches = map[string]*Watch{}
+	}
+
+	return d, nil
+}
+
+// write replaces the store file atomically so a concurrent reader never sees a partial file.
+func (s *FileStore) write(d *fileData) error {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
@@ -0,0 +1,81 @@
+package watch_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/internal/watch"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		dir   string
+		store *FileStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "watch")
+		Expect(err).ToNot(HaveOccurred())
+
+		store, err = NewFileStore(filepath.Join(dir, "watches.json"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Should persist watches across store instances", func() {
+		w, err := NewWatch("btc", "addr", "http://localhost/callback", 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Create(w)).To(Succeed())
+
+		w.State.Synced = true
+		Expect(store.Update(w)).To(Succeed())
+
+		reopened, err := NewFileStore(store.Path)
+		Expect(err).ToNot(HaveOccurred())
+
+		got, err := reopened.Get(w.ID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.AssetID).To(Equal("btc"))
+		Expect(got.Secret).To(Equal(w.Secret))
+		Expect(got.State.Synced).To(BeTrue())
+
+		list, err := reopened.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+	})
+
+	It("Should return ErrNotFound for missing watches", func() {
+		_, err := store.Get("missing")
+		Expect(err).To(Equal(ErrNotFound))
+		Expect(store.Delete("missing")).To(Equal(ErrNotFound))
+		Expect(store.Update(&Watch{ID: "missing"})).To(Equal(ErrNotFound))
+	})
+
+	It("Should delete watches", func() {
+		w, err := NewWatch("btc", "addr", "http://localhost/callback", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Create(w)).To(Succeed())
+		Expect(store.Delete(w.ID)).To(Succeed())
+
+		list, err := store.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(BeEmpty())
+	})
+
+	It("Should record dead letters", func() {
+		Expect(store.AddDeadLetter(DeadLetter{Event: Event{ID: "event"}, Attempts: 5})).To(Succeed())
+
+		letters, err := store.DeadLetters()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(letters).To(HaveLen(1))
+		Expect(letters[0].Event.ID).To(Equal("event"))
+	})
+})
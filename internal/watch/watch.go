@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+const (
+	// EventTransactionReceived is sent the first time an incoming transaction to a watched address is seen.
+	EventTransactionReceived = "transaction.received"
+	// EventTransactionConfirmed is sent once an incoming transaction reaches the watch's minimum confirmations.
+	EventTransactionConfirmed = "transaction.confirmed"
+	// EventBalanceChanged is sent when the balance of a watched address changes. It is used for
+	// clients which are not able to list the transactions of an address.
+	EventBalanceChanged = "balance.changed"
+)
+
+var (
+	// ErrNotFound is returned by a Store when a watch does not exist.
+	ErrNotFound = errors.New("watch not found")
+)
+
+// Watch is a registration to be notified at CallbackURL of activity on an address.
+type Watch struct {
+	ID               string    `json:"id"`
+	AssetID          string    `json:"assetId"`
+	Addr             string    `json:"addr"`
+	CallbackURL      string    `json:"callbackURL"`
+	MinConfirmations int64     `json:"minConfirmations"`
+	Secret           string    `json:"secret,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+
+	// State holds what the poller has already seen so that each event is only sent once.
+	State State `json:"state"`
+}
+
+// State records the activity of a watched address which has already been notified.
+type State struct {
+	// Synced is set after the first poll. Activity which exists before the first poll is recorded but not notified.
+	Synced    bool              `json:"synced"`
+	Seen      map[string]bool   `json:"seen,omitempty"`
+	Confirmed map[string]bool   `json:"confirmed,omitempty"`
+	Balances  map[string]string `json:"balances,omitempty"`
+	// Cursor is where the next poll lists transactions from, for clients which list them incrementally.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Event is the payload POSTed to a watch's callback url.
+type Event struct {
+	ID              string                 `json:"id"`
+	Type            string                 `json:"type"`
+	WatchID         string                 `json:"watchId"`
+	AssetID         string                 `json:"assetId"`
+	Addr            string                 `json:"addr"`
+	Transaction     *transport.Transaction `json:"transaction,omitempty"`
+	Asset           string                 `json:"asset,omitempty"`
+	Balance         string                 `json:"balance,omitempty"`
+	PreviousBalance string                 `json:"previousBalance,omitempty"`
+	CreatedAt       time.Time              `json:"createdAt"`
+}
+
+// DeadLetter records an event which could not be delivered after all retry attempts.
+type DeadLetter struct {
+	Event       Event     `json:"event"`
+	CallbackURL string    `json:"callbackURL"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error"`
+	FailedAt    time.Time `json:"failedAt"`
+}
+
+// NewWatch returns a new watch with a generated id and signing secret.
+func NewWatch(assetID, addr, callbackURL string, minConfirmations int64) (*Watch, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watch{
+		ID:               id,
+		AssetID:          assetID,
+		Addr:             addr,
+		CallbackURL:      callbackURL,
+		MinConfirmations: minConfirmations,
+		Secret:           secret,
+		CreatedAt:        time.Now().UTC(),
+	}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
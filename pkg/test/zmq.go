@@ -0,0 +1,170 @@
+package test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+)
+
+// ZMQPublisher is a fake ZMTP 3.0 PUB socket which accepts a single subscriber.
+// It allows clients which consume zmq notifications, e.g. bitcoind's hashblock, to be tested without libzmq.
+type ZMQPublisher struct {
+	Listener net.Listener
+
+	conn  net.Conn
+	ready chan struct{}
+	err   error
+
+	// Subscriptions holds the topics the subscriber subscribed to, available once the subscriber is ready.
+	Subscriptions []string
+}
+
+// NewZMQPublisher returns a ZMQPublisher listening on a random local port.
+func NewZMQPublisher(topics int) (*ZMQPublisher, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ZMQPublisher{Listener: l, ready: make(chan struct{})}
+	go p.accept(topics)
+
+	return p, nil
+}
+
+// Addr returns the tcp:// address subscribers should connect to.
+func (p *ZMQPublisher) Addr() string {
+	return "tcp://" + p.Listener.Addr().String()
+}
+
+// Publish waits for the subscriber to finish its handshake and sends it a multipart message.
+func (p *ZMQPublisher) Publish(parts ...[]byte) error {
+	<-p.ready
+	if p.err != nil {
+		return p.err
+	}
+
+	for i, part := range parts {
+		var flags byte
+		if i < len(parts)-1 {
+			flags = 0x01
+		}
+
+		if err := writeZMQFrame(p.conn, flags, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close stops the publisher and disconnects the subscriber.
+func (p *ZMQPublisher) Close() error {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+
+	return p.Listener.Close()
+}
+
+func (p *ZMQPublisher) accept(topics int) {
+	defer close(p.ready)
+
+	conn, err := p.Listener.Accept()
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	p.conn = conn
+	r := bufio.NewReader(conn)
+
+	greeting := make([]byte, 64)
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3
+	copy(greeting[12:32], "NULL")
+
+	if _, err := conn.Write(greeting); err != nil {
+		p.err = err
+		return
+	}
+
+	if _, err := io.ReadFull(r, make([]byte, 64)); err != nil {
+		p.err = err
+		return
+	}
+
+	if _, err := readZMQFrame(r); err != nil {
+		p.err = err
+		return
+	}
+
+	ready := []byte{5}
+	ready = append(ready, "READY"...)
+	ready = append(ready, byte(len("Socket-Type")))
+	ready = append(ready, "Socket-Type"...)
+	ready = append(ready, 0, 0, 0, 3)
+	ready = append(ready, "PUB"...)
+
+	if err := writeZMQFrame(conn, 0x04, ready); err != nil {
+		p.err = err
+		return
+	}
+
+	for i := 0; i < topics; i++ {
+		body, err := readZMQFrame(r)
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		if len(body) > 0 && body[0] == 1 {
+			p.Subscriptions = append(p.Subscriptions, string(body[1:]))
+		}
+	}
+}
+
+func writeZMQFrame(w io.Writer, flags byte, body []byte) error {
+	var header []byte
+	if len(body) > 255 {
+		header = make([]byte, 9)
+		header[0] = flags | 0x02
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+
+	_, err := w.Write(append(header, body...))
+	return err
+}
+
+func readZMQFrame(r *bufio.Reader) ([]byte, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint64
+	if flags&0x02 != 0 {
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+
+		size = binary.BigEndian.Uint64(b)
+	} else {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		size = uint64(b)
+	}
+
+	body := make([]byte, size)
+	_, err = io.ReadFull(r, body)
+
+	return body, err
+}
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"log"
@@ -25,6 +26,13 @@ var (
 	StdLogger                    = log.New(os.Stderr, "", log.LstdFlags)
 
 	hexReg = regexp.MustCompile("^0x")
+
+	// ErrSubscriptionNotSupported is returned by a BlockSubscriber which is not configured to open a native subscription.
+	ErrSubscriptionNotSupported = errors.New("block subscriptions are not supported by the client")
+	// ErrBlockHashNotSupported is returned by a BlockFetcher whose node can't look up blocks by hash.
+	ErrBlockHashNotSupported = errors.New("looking up blocks by hash is not supported by the client")
+	// ErrImportNotFound is returned by an ImportScheduler which has no import job with the id asked for.
+	ErrImportNotFound = errors.New("import job not found")
 )
 
 // NewInt64 returns a new pointer to an int64.
@@ -59,6 +67,15 @@ type BalanceData struct {
 type Asset struct {
 	Asset   string `json:"asset"`
 	Balance string `json:"balance"`
+	// Confirmed is the part of the balance which has reached the confirmation depth, when the chain reports it.
+	Confirmed string `json:"confirmed,omitempty"`
+	// Unconfirmed is the pending change to the balance which hasn't been confirmed yet, it's negative for pending spends.
+	Unconfirmed string `json:"unconfirmed,omitempty"`
+	// Locked is the part of the balance which can't be spent, e.g. frozen, staked or reserved by open offers.
+	Locked string `json:"locked,omitempty"`
+	// Issuer is the account which issued the asset on chains where any account can issue an asset of the same
+	// code, e.g. the counterparty of a Ripple trust line.
+	Issuer string `json:"issuer,omitempty"`
 }
 
 // Transaction represents a specific blockchain transaction.
@@ -68,6 +85,67 @@ type Transaction struct {
 	To            string        `json:"to"`
 	Value         string        `json:"value"`
 	Confirmations Confirmations `json:"confirmations"`
+	// Transfers lists the movements decoded individually from the transaction, e.g. transfers emitted by a
+	// token contract, in which case From, To and Value hold the first of them. Transfers of another asset
+	// than the transaction's own, e.g. NFTs moved by an ethereum transaction, have their Asset set.
+	Transfers []Transfer `json:"transfers,omitempty"`
+	// Reverted is set for transactions which were mined but failed, so moved none of their value.
+	Reverted bool `json:"reverted,omitempty"`
+	// Fees holds the gas paid by the transaction on chains which price execution in gas.
+	Fees *GasFees `json:"fees,omitempty"`
+	// Memo holds data attached to the transaction by its sender, e.g. the payload of an OP_RETURN output.
+	Memo string `json:"memo,omitempty"`
+	// MemoType is the kind of memo on chains whose memos are typed, e.g. Stellar's text, id, hash and return memos.
+	MemoType string `json:"memoType,omitempty"`
+	// Coinbase is set for transactions which mint the block reward, so have no sender.
+	Coinbase bool `json:"coinbase,omitempty"`
+	// Outputs lists the outputs of utxo based transactions.
+	Outputs []Output `json:"outputs,omitempty"`
+	// DestinationTag identifies the recipient of a payment to a shared account, e.g. the customer of an
+	// exchange depositing to its Ripple address.
+	DestinationTag *uint32 `json:"destinationTag,omitempty"`
+}
+
+// Output is a single output of a utxo based transaction. Outputs which don't pay an address, e.g. bare
+// multisig and OP_RETURN data outputs, have no Address.
+type Output struct {
+	Index   int    `json:"index"`
+	Address string `json:"address,omitempty"`
+	// Type is the kind of script locking the output: p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, multisig,
+	// nulldata or nonstandard.
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	// Required and PubKeys hold the number of signatures needed to spend a multisig output and the keys
+	// which can provide them.
+	Required int      `json:"required,omitempty"`
+	PubKeys  []string `json:"pubKeys,omitempty"`
+}
+
+// GasFees holds the gas pricing of a transaction, prices being given in the smallest unit per gas.
+// Dynamic fee transactions only bid a MaxFee and MaxPriorityFee, the GasPrice they pay being set
+// by the base fee of the block they are mined in.
+type GasFees struct {
+	// Type is the EIP-2718 type of the transaction, 0 for legacy, 1 for access list and 2 for dynamic fee.
+	Type           int    `json:"type"`
+	GasUsed        uint64 `json:"gasUsed,omitempty"`
+	GasPrice       string `json:"gasPrice,omitempty"`
+	BaseFee        string `json:"baseFee,omitempty"`
+	PriorityFee    string `json:"priorityFee,omitempty"`
+	MaxFee         string `json:"maxFee,omitempty"`
+	MaxPriorityFee string `json:"maxPriorityFee,omitempty"`
+}
+
+// Transfer is a single movement of an asset between two addresses within a transaction.
+type Transfer struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	// Asset is the contract of the transferred token when it differs from the transaction's asset.
+	Asset string `json:"asset,omitempty"`
+	// TokenID identifies the non fungible token transferred, Value being the number of copies.
+	TokenID string `json:"tokenId,omitempty"`
+	// Issuer is the account which issued the transferred asset, see Asset.
+	Issuer string `json:"issuer,omitempty"`
 }
 
 // Confirmations is a struct to hold the transaction confirmations data
@@ -75,6 +153,19 @@ type Confirmations struct {
 	Threshold *int64 `json:"threshold,omitempty"`
 	Confirmed bool   `json:"confirmed"`
 	Value     *int64 `json:"value,omitempty"`
+	// Reorged is set when the block the transaction was included in has been orphaned.
+	Reorged bool `json:"reorged,omitempty"`
+	// Pending is set when the transaction is still in the mempool waiting to be included in a block.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// PendingConfirmations returns the confirmations of a transaction which has not been included in a block yet.
+func PendingConfirmations() Confirmations {
+	return Confirmations{
+		Threshold: ConfirmThresholdValue,
+		Value:     NewInt64(0),
+		Pending:   true,
+	}
 }
 
 // TransactionResp wraps a transaction in a json.api defined response.
@@ -91,6 +182,205 @@ type TransactionsResp struct {
 	} `json:"data"`
 }
 
+// UTXO represents an unspent transaction output which can be spent by an address.
+type UTXO struct {
+	TxID string `json:"txid"`
+	Vout uint32 `json:"vout"`
+	// Amount is the exact value of the output in the smallest unit of the coin, e.g. satoshis.
+	Amount int64 `json:"amount"`
+	// Script is the hex encoded locking script of the output.
+	Script        string `json:"script"`
+	Confirmations int64  `json:"confirmations"`
+}
+
+// UTXOsResp wraps a list of unspent outputs in a json.api defined response.
+type UTXOsResp struct {
+	Data struct {
+		UTXOs []UTXO `json:"utxos"`
+	} `json:"data"`
+}
+
+// Block holds a standardised format for displaying a block.
+type Block struct {
+	Height int64  `json:"height"`
+	Hash   string `json:"hash"`
+	Parent string `json:"parent"`
+	// Timestamp is the unix time in seconds the block was produced.
+	Timestamp int64    `json:"timestamp"`
+	TxCount   int      `json:"tx_count"`
+	TxIDs     []string `json:"tx_ids"`
+	// Transactions holds the normalised transactions of the block, only populated when they are expanded.
+	Transactions []Transaction `json:"transactions,omitempty"`
+}
+
+// BlockResp wraps a block in a json.api defined response.
+type BlockResp struct {
+	Data struct {
+		Block Block `json:"block"`
+	} `json:"data"`
+}
+
+// TokenBalance holds the balance of an address in a token contract which was looked up by its address.
+// Balance has the token decimals applied, RawBalance is the balance as reported by the contract.
+type TokenBalance struct {
+	Contract   string `json:"contract"`
+	Symbol     string `json:"symbol"`
+	Decimals   int    `json:"decimals"`
+	Balance    string `json:"balance"`
+	RawBalance string `json:"rawBalance"`
+}
+
+// TokenBalanceResp wraps a token balance in a json.api defined response.
+type TokenBalanceResp struct {
+	Data struct {
+		Token TokenBalance `json:"token"`
+	} `json:"data"`
+}
+
+// NFTOwner holds the current owner of a non fungible token.
+type NFTOwner struct {
+	Contract string `json:"contract"`
+	TokenID  string `json:"tokenId"`
+	Owner    string `json:"owner"`
+}
+
+// NFTOwnerResp wraps an nft owner in a json.api defined response.
+type NFTOwnerResp struct {
+	Data struct {
+		NFT NFTOwner `json:"nft"`
+	} `json:"data"`
+}
+
+// NFTHoldings holds the non fungible tokens of a contract held by an owner. TokenIDs are only listed
+// when the contract supports enumerating the tokens of an owner, a page at a time.
+type NFTHoldings struct {
+	Contract   string   `json:"contract"`
+	Owner      string   `json:"owner"`
+	Balance    string   `json:"balance"`
+	Enumerable bool     `json:"enumerable"`
+	TokenIDs   []string `json:"tokenIds,omitempty"`
+	// NextOffset is the offset the ids of the owner's remaining tokens are listed from, 0 once all are listed.
+	NextOffset int64 `json:"nextOffset,omitempty"`
+}
+
+// NFTHoldingsResp wraps nft holdings in a json.api defined response.
+type NFTHoldingsResp struct {
+	Data struct {
+		NFTs NFTHoldings `json:"nfts"`
+	} `json:"data"`
+}
+
+// Mempool holds a standardised summary of the transactions waiting to be included in a block.
+type Mempool struct {
+	// Size is the number of transactions which can be included in the next blocks.
+	Size int `json:"size"`
+	// Bytes is the total size of the pending transactions, when reported by the node.
+	Bytes int64 `json:"bytes,omitempty"`
+	// Queued is the number of transactions which can't be included yet, e.g. ethereum nonce gaps.
+	Queued int `json:"queued,omitempty"`
+	// FeeHistogram buckets the pending transactions by fee rate, when the node exposes them.
+	FeeHistogram []FeeBucket `json:"fee_histogram,omitempty"`
+}
+
+// FeeBucket counts the pending transactions paying at least FeeRate, and less than the next bucket.
+type FeeBucket struct {
+	// FeeRate is the lower bound of the bucket in the smallest unit of the coin per virtual byte.
+	FeeRate int64 `json:"fee_rate"`
+	Count   int   `json:"count"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// MempoolResp wraps a mempool summary in a json.api defined response.
+type MempoolResp struct {
+	Data struct {
+		Mempool Mempool `json:"mempool"`
+	} `json:"data"`
+}
+
+// Import job statuses.
+const (
+	ImportScanning = "scanning"
+	ImportComplete = "complete"
+	ImportFailed   = "failed"
+)
+
+// ImportReq describes the addresses a node should add to its wallet and how far back it should rescan for them.
+type ImportReq struct {
+	// Addr is a single address to import.
+	Addr string `json:"addr"`
+	// XPub is an extended public key whose receive and change addresses are imported in place of Addr.
+	XPub string `json:"xpub,omitempty"`
+	// Script is how the addresses of XPub are encoded: p2pkh, p2sh-p2wpkh, p2wpkh or p2tr, defaulting to the
+	// script of the key's version, e.g. p2wpkh for zpub keys.
+	Script string `json:"script,omitempty"`
+	// Timestamp is the unix time the addresses were first used, blocks mined before it aren't rescanned.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// BirthHeight bounds the rescan by the height of the first block the addresses were used in, in place of Timestamp.
+	BirthHeight int64 `json:"birthHeight,omitempty"`
+}
+
+// ImportJob holds the progress of an import started by an ImportScheduler.
+type ImportJob struct {
+	ID          string   `json:"id"`
+	AssetID     string   `json:"assetId"`
+	Descriptors []string `json:"descriptors"`
+	// Timestamp is the unix time the node's rescan starts from.
+	Timestamp int64  `json:"timestamp"`
+	Status    string `json:"status"`
+	// Progress is the fraction of the rescan done, between 0 and 1.
+	Progress  float64   `json:"progress"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// ImportResp wraps an import job in a json.api defined response.
+type ImportResp struct {
+	Data struct {
+		Import ImportJob `json:"import"`
+	} `json:"data"`
+}
+
+// XPubAddress is an address derived from an extended public key.
+type XPubAddress struct {
+	Address string `json:"address"`
+	// Path is the derivation path of the address, from the master key for BIP44, 49 and 84 account keys,
+	// otherwise relative to the extended key.
+	Path   string `json:"path"`
+	Change bool   `json:"change"`
+	Index  uint32 `json:"index"`
+	// Used is set for addresses which have received funds.
+	Used bool `json:"used"`
+}
+
+// XPubAddressesResp wraps the addresses derived from an extended public key in a json.api defined response.
+type XPubAddressesResp struct {
+	Data struct {
+		// Script is how the addresses are encoded: p2pkh, p2sh-p2wpkh or p2wpkh.
+		Script    string        `json:"script"`
+		Addresses []XPubAddress `json:"addresses"`
+	} `json:"data"`
+}
+
+// Resource is an account's usage of a resource the chain meters, e.g. the cpu, net and ram of an EOS account.
+// Max and Available are -1 when the account's usage of the resource is unlimited.
+type Resource struct {
+	Resource string `json:"resource"`
+	// Unit is what the usage is measured in, e.g. us of cpu time or bytes.
+	Unit      string `json:"unit"`
+	Used      int64  `json:"used"`
+	Available int64  `json:"available"`
+	Max       int64  `json:"max"`
+	// Staked is the amount of the chain's asset staked for the resource, when it's allotted by stake.
+	Staked string `json:"staked,omitempty"`
+}
+
+// ResourcesResp wraps the resources of an account in a json.api defined response.
+type ResourcesResp struct {
+	Data struct {
+		Resources []Resource `json:"resources"`
+	} `json:"data"`
+}
+
 // CoinClient defines an interface that communicates
 // with a coin specific lambda function.
 type CoinClient interface {
@@ -102,6 +392,38 @@ type CoinClient interface {
 	GetTransactionByHash(hash string) (*TransactionResp, error)
 }
 
+// ConfirmedBalanceGetter defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can return balances at a given confirmation depth.
+type ConfirmedBalanceGetter interface {
+	// GetConfirmedBalance fetches the balance of the address counting only funds with at least minConf confirmations.
+	GetConfirmedBalance(addr string, minConf int) (*Balance, error)
+}
+
+// HistoricalBalanceGetter defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can return balances as of a past block.
+type HistoricalBalanceGetter interface {
+	// GetBalanceAt fetches the balance of the address at the end of the block at the given height.
+	GetBalanceAt(addr string, height int64) (*Balance, error)
+	// GetBlockTime fetches the time the block at the given height was mined, used to resolve timestamps to heights.
+	GetBlockTime(height int64) (time.Time, error)
+}
+
+// TokenBalanceGetter defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can look up balances in any token contract on its chain.
+type TokenBalanceGetter interface {
+	// GetTokenBalance fetches the balance of the address in the token deployed at contract.
+	GetTokenBalance(contract, addr string) (*TokenBalanceResp, error)
+}
+
+// NFTInspector defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can look up the ownership of non fungible tokens on its chain.
+type NFTInspector interface {
+	// GetNFTOwner fetches the owner of the token with the given id in the contract.
+	GetNFTOwner(contract, tokenID string) (*NFTOwnerResp, error)
+	// ListNFTs fetches the tokens of the contract held by the address, listing their ids from offset.
+	ListNFTs(contract, addr string, offset int64) (*NFTHoldingsResp, error)
+}
+
 // AddressImporter defines an interface that a coin client can adhear to.
 // If a CoinClient has this interface then it can import and address to watch.
 type AddressImporter interface {
@@ -110,6 +432,96 @@ type AddressImporter interface {
 	ImportAddress(addr string) error
 }
 
+// ImportScheduler defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can import addresses without blocking on the rescan of its node.
+type ImportScheduler interface {
+	// StartImport asks the node to import the addresses, returning the job tracking its rescan straight away.
+	StartImport(req ImportReq) (*ImportResp, error)
+	// GetImport fetches the import job with the given id and the progress of its rescan.
+	GetImport(id string) (*ImportResp, error)
+}
+
+// XPubInspector defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can look up the wallet of an extended public key. The receive and
+// change addresses of the key are derived until gap consecutive addresses are unused.
+type XPubInspector interface {
+	// ListXPubAddresses derives the addresses of the extended public key, the unused ones past the last used included.
+	ListXPubAddresses(xpub string, gap int) (*XPubAddressesResp, error)
+	// GetXPubBalance fetches the balance summed across the used addresses of the extended public key.
+	GetXPubBalance(xpub string, gap int) (*Balance, error)
+	// ListXPubTransactions fetches the transactions moving funds to or from the used addresses of the extended
+	// public key, each netted across the addresses.
+	ListXPubTransactions(xpub string, gap int) (*TransactionsResp, error)
+}
+
+// ResourceInspector defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can look up the resources an account has been allotted.
+type ResourceInspector interface {
+	// GetResources fetches the usage of every resource metered for the address.
+	GetResources(addr string) (*ResourcesResp, error)
+}
+
+// BatchBalanceGetter defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can fetch the balances of many addresses
+// using a single upstream request.
+type BatchBalanceGetter interface {
+	// GetBalances fetches the current balances for each of the addresses, keyed by address.
+	GetBalances(addrs []string) (map[string]*Balance, error)
+}
+
+// BatchTransactionGetter defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can fetch many transactions using a single upstream request.
+type BatchTransactionGetter interface {
+	// GetTransactionsByHash fetches information about the transactions, keyed by hash.
+	GetTransactionsByHash(hashes []string) (map[string]*TransactionResp, error)
+}
+
+// TransactionLister defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can list the incoming transactions of an address.
+type TransactionLister interface {
+	// ListTransactions fetches the transactions received by the address which the node knows about.
+	ListTransactions(addr string) (*TransactionsResp, error)
+}
+
+// TransactionSinceLister defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can list the incoming transactions of an address incrementally.
+type TransactionSinceLister interface {
+	// ListTransactionsSince fetches the transactions received by the address after the cursor, along with the cursor
+	// to pass next time. Transactions are listed until they have the given confirmations, an empty cursor lists all.
+	ListTransactionsSince(addr, cursor string, confirmations int64) (*TransactionsResp, string, error)
+}
+
+// UTXOLister defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can list the unspent outputs of an address.
+type UTXOLister interface {
+	// ListUTXOs fetches the unspent outputs of the address with at least minConf confirmations.
+	ListUTXOs(addr string, minConf int) (*UTXOsResp, error)
+}
+
+// BlockSubscriber defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can be notified of new blocks by its node rather than polling.
+type BlockSubscriber interface {
+	// SubscribeBlocks sends the chain tip to blocks every time a new block arrives. It blocks until
+	// the context is cancelled or the subscription fails.
+	SubscribeBlocks(ctx context.Context, blocks chan<- CoinData) error
+}
+
+// BlockFetcher defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can look up blocks by height or hash.
+type BlockFetcher interface {
+	// GetBlockByHeight fetches the block at the given height of the main chain.
+	GetBlockByHeight(height int64) (*BlockResp, error)
+	// GetBlockByHash fetches the block with the given hash.
+	GetBlockByHash(hash string) (*BlockResp, error)
+}
+
+// MempoolInspector defines an interface that a coin client can adhear to.
+// If a CoinClient has this interface then it can summarise the transactions pending in its node.
+type MempoolInspector interface {
+	// GetMempool fetches a summary of the transactions waiting to be included in a block.
+	GetMempool() (*MempoolResp, error)
+}
+
 // BaseClient handles some of the more repetitive http client handling
 type BaseClient struct {
 	BaseURL *url.URL
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HeightAt returns the height of the last block mined at or before t, binary searching the
+// blocks between genesis and tip. blockTime looks up the timestamp of the block at a height.
+func HeightAt(t time.Time, tip int64, blockTime func(height int64) (time.Time, error)) (int64, error) {
+	tipTime, err := blockTime(tip)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error getting time of block: %d", tip)
+	}
+
+	if !t.Before(tipTime) {
+		return tip, nil
+	}
+
+	genesisTime, err := blockTime(0)
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting time of genesis block")
+	}
+
+	if t.Before(genesisTime) {
+		return 0, fmt.Errorf("no block was mined at or before %s", t.Format(time.RFC3339))
+	}
+
+	// invariant: block lo was mined at or before t and block hi after it.
+	lo, hi := int64(0), tip
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+
+		midTime, err := blockTime(mid)
+		if err != nil {
+			return 0, errors.Wrapf(err, "error getting time of block: %d", mid)
+		}
+
+		if midTime.After(t) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return lo, nil
+}
@@ -0,0 +1,53 @@
+package transport_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("History", func() {
+	Describe("HeightAt", func() {
+		genesis := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		// blocks are mined every ten minutes, apart from a gap between blocks 50 and 51.
+		blockTime := func(height int64) (time.Time, error) {
+			offset := time.Duration(height) * 10 * time.Minute
+			if height > 50 {
+				offset += 24 * time.Hour
+			}
+
+			return genesis.Add(offset), nil
+		}
+
+		DescribeTable("Should return the last block mined at or before the time",
+			func(t time.Time, expected int64) {
+				height, err := HeightAt(t, 100, blockTime)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(height).To(Equal(expected))
+			},
+			Entry("genesis", genesis, int64(0)),
+			Entry("exact block time", genesis.Add(230*time.Minute), int64(23)),
+			Entry("between blocks", genesis.Add(235*time.Minute), int64(23)),
+			Entry("inside a gap", genesis.Add(12*time.Hour), int64(50)),
+			Entry("after the tip", genesis.Add(365*24*time.Hour), int64(100)),
+		)
+
+		It("Should error when the time is before genesis", func() {
+			_, err := HeightAt(genesis.Add(-time.Second), 100, blockTime)
+			Expect(err).To(MatchError("no block was mined at or before 2019-12-31T23:59:59Z"))
+		})
+
+		It("Should return the error looking up a block", func() {
+			_, err := HeightAt(genesis, 100, func(int64) (time.Time, error) {
+				return time.Time{}, errors.New("node down")
+			})
+			Expect(err).To(MatchError("error getting time of block: 100: node down"))
+		})
+	})
+})
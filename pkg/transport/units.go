@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits converts an integer amount of an asset's base unit, e.g. wei or satoshi,
+// into a decimal string using the given number of decimals. Trailing zeros are removed.
+func FormatUnits(value string, decimals int) (string, error) {
+	i, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid integer amount: %s", value)
+	}
+
+	if decimals <= 0 {
+		return i.String(), nil
+	}
+
+	s := new(big.Int).Abs(i).String()
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+
+	out := s[:len(s)-decimals]
+	if frac := strings.TrimRight(s[len(s)-decimals:], "0"); frac != "" {
+		out += "." + frac
+	}
+
+	if i.Sign() < 0 {
+		out = "-" + out
+	}
+
+	return out, nil
+}
+
+// ParseUnits converts a decimal amount of an asset into an integer amount of its base unit using the given
+// number of decimals, the inverse of FormatUnits. Amounts more precise than the base unit are rejected.
+func ParseUnits(value string, decimals int) (string, error) {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	whole, frac := value, ""
+	if i := strings.Index(value, "."); i >= 0 {
+		whole, frac = value[:i], value[i+1:]
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > decimals || strings.ContainsAny(frac, "+-") {
+		return "", fmt.Errorf("invalid amount: %s for %d decimals", value, decimals)
+	}
+
+	i, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount: %s", value)
+	}
+
+	return i.String(), nil
+}
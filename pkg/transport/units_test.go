@@ -0,0 +1,105 @@
+package transport_test
+
+import (
+	"math/big"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/hugorut/coins-oracle/pkg/transport"
+)
+
+var _ = Describe("Units", func() {
+	Describe("FormatUnits", func() {
+		DescribeTable("Should shift the amount by the decimals",
+			func(value string, decimals int, expected string) {
+				out, err := FormatUnits(value, decimals)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(expected))
+			},
+			Entry("whole amount", "1000000", 6, "1"),
+			Entry("fractional amount", "150840600", 6, "150.8406"),
+			Entry("amount smaller than one unit", "5", 6, "0.000005"),
+			Entry("zero", "0", 18, "0"),
+			Entry("no decimals", "42", 0, "42"),
+			Entry("negative amount", "-1500", 3, "-1.5"),
+			Entry("amount larger than int64", "325586539664609129644855132177", 30, "0.325586539664609129644855132177"),
+		)
+
+		It("Should match exact decimal division for random amounts", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+
+			for i := 0; i < 200; i++ {
+				limit := new(big.Int).Lsh(big.NewInt(1), uint(r.Intn(256)+1))
+				value := new(big.Int).Rand(r, limit)
+				if r.Intn(4) == 0 {
+					value.Neg(value)
+				}
+
+				decimals := r.Intn(31)
+
+				out, err := FormatUnits(value.String(), decimals)
+				Expect(err).ToNot(HaveOccurred())
+
+				// parsing the output back gives the amount divided by 10^decimals exactly.
+				parsed, ok := new(big.Rat).SetString(out)
+				Expect(ok).To(BeTrue(), out)
+
+				scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+				Expect(parsed.Cmp(new(big.Rat).SetFrac(value, scale))).To(BeZero(), "value: %s decimals: %d out: %s", value, decimals, out)
+				Expect(out).ToNot(HaveSuffix("."))
+				Expect(out).ToNot(MatchRegexp(`\.\d*0$`))
+			}
+		})
+
+		It("Should error on a non integer amount", func() {
+			_, err := FormatUnits("1.5", 6)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ParseUnits", func() {
+		DescribeTable("Should shift the amount back to the base unit",
+			func(value string, decimals int, expected string) {
+				out, err := ParseUnits(value, decimals)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(expected))
+			},
+			Entry("whole amount", "1", 6, "1000000"),
+			Entry("fractional amount", "150.8406", 6, "150840600"),
+			Entry("amount smaller than one unit", "0.000005", 6, "5"),
+			Entry("trailing zeros", "1.500", 3, "1500"),
+			Entry("no decimals", "42", 0, "42"),
+			Entry("negative amount", "-1.5", 3, "-1500"),
+		)
+
+		It("Should round trip random amounts formatted by FormatUnits", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+
+			for i := 0; i < 200; i++ {
+				limit := new(big.Int).Lsh(big.NewInt(1), uint(r.Intn(256)+1))
+				value := new(big.Int).Rand(r, limit)
+				decimals := r.Intn(31)
+
+				formatted, err := FormatUnits(value.String(), decimals)
+				Expect(err).ToNot(HaveOccurred())
+
+				out, err := ParseUnits(formatted, decimals)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(value.String()), "decimals: %d formatted: %s", decimals, formatted)
+			}
+		})
+
+		DescribeTable("Should error on an amount which isn't a whole number of base units",
+			func(value string) {
+				_, err := ParseUnits(value, 6)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("too precise", "0.0000001"),
+			Entry("not a number", "1.5a"),
+			Entry("signed fraction", "1.-5"),
+		)
+	})
+})